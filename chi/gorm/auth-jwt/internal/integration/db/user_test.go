@@ -5,42 +5,25 @@ import (
 	"os"
 	"testing"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
+	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/integration/testdb"
 	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/repository/dao"
-	"github.com/yizeng/gab/chi/gorm/auth-jwt/pkg/dockertester"
 )
 
 type UserDBTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
+	db *gorm.DB
 
 	userDAO *dao.UserDAO
 }
 
 func (s *UserDBTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *UserDBTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	s.db = testdb.Open(s.T())
 }
 
 func (s *UserDBTestSuite) SetupTest() {
@@ -8,7 +8,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -18,9 +17,9 @@ import (
 	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/config"
 	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/integration/testdb"
 	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/pkg/jwthelper"
 	"github.com/yizeng/gab/chi/gorm/auth-jwt/internal/repository/dao"
-	"github.com/yizeng/gab/chi/gorm/auth-jwt/pkg/dockertester"
 )
 
 const (
@@ -30,28 +29,12 @@ const (
 type UserHandlerTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
-	server   *api.Server
+	db     *gorm.DB
+	server *api.Server
 }
 
 func (s *UserHandlerTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *UserHandlerTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	s.db = testdb.Open(s.T())
 }
 
 func (s *UserHandlerTestSuite) SetupTest() {
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/api"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/config"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/db"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/logger"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/pkg/cache"
+)
+
+func Start() error {
+	conf, err := config.Load("./cmd/app/config.yml")
+	if err != nil {
+		return fmt.Errorf("failed to initialize config -> %w", err)
+	}
+
+	if err = logger.Init(conf.API.Environment); err != nil {
+		return fmt.Errorf("failed to initialize logger -> %w", err)
+	}
+
+	postgresDB, err := db.OpenPostgres(conf.Postgres)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database -> %w", err)
+	}
+
+	articleCache, err := openCache(conf)
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache -> %w", err)
+	}
+
+	s := api.NewServer(conf, postgresDB, articleCache)
+
+	addr := ":" + s.Config.API.Port
+	zap.L().Info(fmt.Sprintf("starting server at %v", addr))
+	if err = http.ListenAndServe(addr, s.Router); err != nil {
+		return fmt.Errorf("failed to start the server -> %w", err)
+	}
+
+	return nil
+}
+
+// defaultCacheSize is how many entries the lru cache holds when it's not configured.
+const defaultCacheSize = 1000
+
+// openCache returns the configured cache backend, or nil when caching is disabled.
+func openCache(conf *config.AppConfig) (cache.Cache, error) {
+	if conf.Cache == nil {
+		return nil, nil
+	}
+
+	switch conf.Cache.Backend {
+	case config.CacheBackendLRU:
+		size := conf.Cache.Size
+		if size == 0 {
+			size = defaultCacheSize
+		}
+
+		return cache.NewLRU(size), nil
+	case config.CacheBackendRedis:
+		client, err := db.OpenRedis(conf.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("db.OpenRedis -> %w", err)
+		}
+
+		return cache.NewRedis(client), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,284 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/api/middleware"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/service"
+)
+
+type ArticleService interface {
+	CreateArticle(ctx context.Context, article domain.Article) (domain.Article, error)
+	GetArticle(ctx context.Context, id uint) (domain.Article, error)
+	ListArticles(ctx context.Context, page uint, perPage uint) ([]domain.Article, error)
+	SearchArticles(ctx context.Context, title, content string) ([]domain.Article, error)
+	UpdateArticle(ctx context.Context, article domain.Article) (domain.Article, error)
+	DeleteArticle(ctx context.Context, id uint) error
+}
+
+type ArticleHandler struct {
+	svc ArticleService
+}
+
+func NewArticleHandler(svc ArticleService) *ArticleHandler {
+	return &ArticleHandler{
+		svc: svc,
+	}
+}
+
+// HandleCreateArticle godoc
+// @Summary      Create an article
+// @Tags         articles
+// @Produce      json
+// @Param        request   body      request.CreateArticleRequest true "request body"
+// @Success      200      {object}   domain.Article
+// @Failure      400      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles [post]
+func (h *ArticleHandler) HandleCreateArticle(w http.ResponseWriter, r *http.Request) {
+	req := request.CreateArticleRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	article, err := h.svc.CreateArticle(r.Context(), domain.Article{
+		UserID:  req.UserID,
+		Title:   req.Title,
+		Content: req.Content,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrArticleDuplicated) {
+			_ = render.Render(w, r, response.ErrBadRequest(service.ErrArticleDuplicated))
+
+			return
+		}
+
+		err = fmt.Errorf("v1.HandleCreateArticle -> h.svc.CreateArticle -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, article)
+}
+
+// HandleGetArticle godoc
+// @Summary      Get an article
+// @Tags         articles
+// @Produce      json
+// @Param        articleID   path    int  true "article ID"
+// @Success      200      {object}   domain.Article
+// @Failure      400      {object}   response.Err
+// @Failure      404      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/{articleID} [get]
+func (h *ArticleHandler) HandleGetArticle(w http.ResponseWriter, r *http.Request) {
+	rawArticleID := chi.URLParam(r, "articleID")
+	articleID, err := strconv.Atoi(rawArticleID)
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("articleID", rawArticleID))
+
+		return
+	}
+
+	if articleID <= 0 {
+		_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+		return
+	}
+
+	article, err := h.svc.GetArticle(r.Context(), uint(articleID))
+	if err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+			return
+		}
+
+		err = fmt.Errorf("v1.HandleGetArticle -> h.svc.GetArticle -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, article)
+}
+
+// HandleUpdateArticle godoc
+// @Summary      Update an article
+// @Tags         articles
+// @Produce      json
+// @Param        articleID   path    int  true "article ID"
+// @Param        request   body      request.UpdateArticleRequest true "request body"
+// @Success      200      {object}   domain.Article
+// @Failure      400      {object}   response.Err
+// @Failure      404      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/{articleID} [put]
+func (h *ArticleHandler) HandleUpdateArticle(w http.ResponseWriter, r *http.Request) {
+	rawArticleID := chi.URLParam(r, "articleID")
+	articleID, err := strconv.Atoi(rawArticleID)
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("articleID", rawArticleID))
+
+		return
+	}
+
+	if articleID <= 0 {
+		_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+		return
+	}
+
+	req := request.UpdateArticleRequest{}
+	if err = render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	article, err := h.svc.UpdateArticle(r.Context(), domain.Article{
+		ID:      uint(articleID),
+		Title:   req.Title,
+		Content: req.Content,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+			return
+		}
+		if errors.Is(err, service.ErrArticleDuplicated) {
+			_ = render.Render(w, r, response.ErrBadRequest(service.ErrArticleDuplicated))
+
+			return
+		}
+
+		err = fmt.Errorf("v1.HandleUpdateArticle -> h.svc.UpdateArticle -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, article)
+}
+
+// HandleDeleteArticle godoc
+// @Summary      Delete an article
+// @Tags         articles
+// @Produce      json
+// @Param        articleID   path    int  true "article ID"
+// @Success      204
+// @Failure      400      {object}   response.Err
+// @Failure      404      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/{articleID} [delete]
+func (h *ArticleHandler) HandleDeleteArticle(w http.ResponseWriter, r *http.Request) {
+	rawArticleID := chi.URLParam(r, "articleID")
+	articleID, err := strconv.Atoi(rawArticleID)
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("articleID", rawArticleID))
+
+		return
+	}
+
+	if articleID <= 0 {
+		_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+		return
+	}
+
+	if err = h.svc.DeleteArticle(r.Context(), uint(articleID)); err != nil {
+		if errors.Is(err, service.ErrArticleNotFound) {
+			_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+			return
+		}
+
+		err = fmt.Errorf("v1.HandleDeleteArticle -> h.svc.DeleteArticle -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListArticles godoc
+// @Summary      List all articles
+// @Tags         articles
+// @Produce      json
+// @Param        page     query      int  false  "which page to load. Default to 1 if empty."
+// @Param        per_page query      int  false  "how many items per page. Default to 10 if empty."
+// @Success      200      {object}   []domain.Article
+// @Failure      500      {object}   response.Err
+// @Router       /articles [get]
+func (h *ArticleHandler) HandleListArticles(w http.ResponseWriter, r *http.Request) {
+	pageVal := r.Context().Value(middleware.PageQueryKey)
+	page, ok := pageVal.(uint)
+	if !ok {
+		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PageQueryKey, pageVal)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+	perPageVal := r.Context().Value(middleware.PerPageQueryKey)
+	perPage, ok := perPageVal.(uint)
+	if !ok {
+		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PerPageQueryKey, perPage)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	articles, err := h.svc.ListArticles(r.Context(), page, perPage)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleListArticles -> h.svc.ListArticles -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, articles)
+}
+
+// HandleSearchArticles godoc
+// @Summary      Search articles
+// @Tags         articles
+// @Produce      json
+// @Param        title    query     string  false  "search by title"
+// @Param        content  query     string  false  "search by content"
+// @Success      200      {object}   []domain.Article
+// @Failure      500      {object}   response.Err
+// @Router       /articles/search [get]
+func (h *ArticleHandler) HandleSearchArticles(w http.ResponseWriter, r *http.Request) {
+	titleParam := r.URL.Query().Get("title")
+	contentParam := r.URL.Query().Get("content")
+
+	articles, err := h.svc.SearchArticles(r.Context(), titleParam, contentParam)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleSearchArticles -> h.svc.SearchArticles -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, articles)
+}
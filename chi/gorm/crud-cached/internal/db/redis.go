@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yizeng/gab/chi/gorm/crud-cached/internal/config"
+)
+
+func OpenRedis(conf *config.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     conf.Addr,
+		Password: conf.Password,
+		DB:       conf.DB,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("client.Ping -> %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LRU keeps up to size values in process and evicts the least recently used one when it's full.
+// It's suitable for a single instance, writes on a replica don't invalidate the others.
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of *entry, the most recently used first
+	items map[string]*list.Element
+	now   func() time.Time
+
+	// counters are kept apart so they're never evicted, like in Redis where they have no TTL.
+	counters map[string]int64
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero means no expiration
+}
+
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:     size,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+		counters: make(map[string]int64),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if counter, ok := c.counters[key]; ok {
+		return []byte(strconv.FormatInt(counter, 10)), nil
+	}
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	e := elem.Value.(*entry)
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+
+		return nil, ErrMiss
+	}
+	c.order.MoveToFront(elem)
+
+	return e.value, nil
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&entry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *LRU) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.counters, key)
+		if elem, ok := c.items[key]; ok {
+			c.remove(elem)
+		}
+	}
+
+	return nil
+}
+
+func (c *LRU) Incr(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counters[key]++
+
+	return c.counters[key], nil
+}
+
+func (c *LRU) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
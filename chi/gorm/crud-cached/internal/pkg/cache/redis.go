@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps values in Redis, so they're shared and invalidated across all replicas.
+// Values expire by themselves with their TTL, Redis evicts them when it's full if a maxmemory-policy is set.
+type Redis struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedis(client redis.Cmdable) *Redis {
+	return &Redis{
+		client: client,
+		prefix: "cache:",
+	}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrMiss
+		}
+
+		return nil, fmt.Errorf("c.client.Get -> %w", err)
+	}
+
+	return value, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("c.client.Set -> %w", err)
+	}
+
+	return nil
+}
+
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, c.prefix+key)
+	}
+
+	if err := c.client.Del(ctx, prefixed...).Err(); err != nil {
+		return fmt.Errorf("c.client.Del -> %w", err)
+	}
+
+	return nil
+}
+
+func (c *Redis) Incr(ctx context.Context, key string) (int64, error) {
+	counter, err := c.client.Incr(ctx, c.prefix+key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("c.client.Incr -> %w", err)
+	}
+
+	return counter, nil
+}
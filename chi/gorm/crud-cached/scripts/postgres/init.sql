@@ -0,0 +1 @@
+CREATE DATABASE chi_gorm_crud_cached;
@@ -5,42 +5,25 @@ import (
 	"os"
 	"testing"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
+	"github.com/yizeng/gab/chi/gorm/crud/internal/integration/testdb"
 	"github.com/yizeng/gab/chi/gorm/crud/internal/repository/dao"
-	"github.com/yizeng/gab/chi/gorm/crud/pkg/dockertester"
 )
 
 type ArticleDBTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
+	db *gorm.DB
 
 	articleDAO *dao.ArticleDAO
 }
 
 func (s *ArticleDBTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *ArticleDBTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	s.db = testdb.Open(s.T())
 }
 
 func (s *ArticleDBTestSuite) SetupTest() {
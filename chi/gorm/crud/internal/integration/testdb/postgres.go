@@ -0,0 +1,109 @@
+package testdb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/chi/gorm/crud/pkg/dockertester"
+)
+
+// dsnBackend creates a database in the PostgreSQL server of EnvDSN for each suite,
+// so that packages running at the same time don't see each other's rows.
+type dsnBackend struct{}
+
+func (dsnBackend) Name() string { return "dsn" }
+
+func (dsnBackend) Open() (*gorm.DB, func() error, error) {
+	dsn := os.Getenv(EnvDSN)
+	if dsn == "" {
+		return nil, nil, fmt.Errorf("%v isn't set", EnvDSN)
+	}
+
+	server, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("gorm.Open -> %w", err)
+	}
+
+	name, err := createDatabase(server)
+	if err != nil {
+		return nil, nil, errors.Join(err, closeDB(server))
+	}
+
+	db, err := connect(dsn, name)
+	if err != nil {
+		return nil, nil, errors.Join(err, dropDatabase(server, name), closeDB(server))
+	}
+
+	return db, func() error {
+		return errors.Join(closeDB(db), dropDatabase(server, name), closeDB(server))
+	}, nil
+}
+
+// dockerBackend starts a PostgreSQL container for each suite.
+type dockerBackend struct{}
+
+func (dockerBackend) Name() string { return "docker" }
+
+func (dockerBackend) Open() (*gorm.DB, func() error, error) {
+	dt, err := dockertester.InitPostgres()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("dockertester.OpenPostgres -> %w", err), dt.Pool.Purge(dt.Resource))
+	}
+
+	return db, func() error {
+		return errors.Join(closeDB(db), dt.Pool.Purge(dt.Resource))
+	}, nil
+}
+
+// createDatabase creates a database with a random name in the server of db.
+func createDatabase(db *gorm.DB) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand.Read -> %w", err)
+	}
+
+	name := "test_" + hex.EncodeToString(b)
+	if err := db.Exec("CREATE DATABASE " + name).Error; err != nil {
+		return "", fmt.Errorf("failed to create database -> %w", err)
+	}
+
+	return name, nil
+}
+
+// dropDatabase drops a database of the server of db, even when it's still connected to.
+func dropDatabase(db *gorm.DB, name string) error {
+	if err := db.Exec("DROP DATABASE IF EXISTS " + name + " WITH (FORCE)").Error; err != nil {
+		return fmt.Errorf("failed to drop database -> %w", err)
+	}
+
+	return nil
+}
+
+// connect connects to another database of the server of dsn.
+func connect(dsn, name string) (*gorm.DB, error) {
+	conf, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("pgx.ParseConfig -> %w", err)
+	}
+	conf.Database = name
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: stdlib.OpenDB(*conf)}), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("gorm.Open -> %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api"
+)
+
+func newRoutesCommand(e *env) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "routes",
+		Short: "Print all the routes of the API",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("format must be text or json, got %q", format)
+			}
+
+			// Routes don't depend on the search index, the rate limiter nor the idempotency store,
+			// they're left out so that nothing is opened nor connected.
+			conf := *e.conf
+			conf.Search, conf.RateLimit, conf.Idempotency = nil, nil, nil
+
+			routes, err := api.NewServer(&conf, nil, nil).Routes()
+			if err != nil {
+				return fmt.Errorf("failed to list routes -> %w", err)
+			}
+
+			if format == "json" {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+
+				return encoder.Encode(routes)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "METHOD\tPATH")
+			for _, route := range routes {
+				fmt.Fprintf(w, "%v\t%v\n", route.Method, route.Path)
+			}
+
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "output format, text or json")
+
+	return cmd
+}
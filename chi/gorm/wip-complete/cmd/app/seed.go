@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/seed"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
+)
+
+const defaultSeedPath = "./scripts/seed/dev.yml"
+
+func newSeedCommand(e *env) *cobra.Command {
+	return &cobra.Command{
+		Use:   "seed [files...]",
+		Short: "Create the users and articles of YAML fixture files, " + defaultSeedPath + " by default",
+		Long: "Create the users and articles of YAML fixture files, " + defaultSeedPath + " by default.\n" +
+			"Users and articles that already exist are skipped, so it can be run again.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{defaultSeedPath}
+			}
+
+			fixtures, err := seed.Load(args...)
+			if err != nil {
+				return fmt.Errorf("failed to load fixtures -> %w", err)
+			}
+
+			gormDB, err := e.openDB()
+			if err != nil {
+				return err
+			}
+
+			articleSvc, closeIndex, err := e.newArticleService(gormDB)
+			if err != nil {
+				return err
+			}
+			defer closeIndex()
+
+			userRepo := repository.NewUserRepository(dao.NewUserDAO(gormDB))
+			seeder := seed.NewSeeder(service.NewAuthService(userRepo), service.NewUserService(userRepo), articleSvc,
+				e.newTxManager(gormDB))
+
+			result, err := seeder.Seed(context.Background(), fixtures)
+			if err != nil {
+				return fmt.Errorf("failed to seed database -> %w", err)
+			}
+
+			zap.L().Info(fmt.Sprintf("seeded %v users and %v articles, skipped %v existing ones",
+				result.Users, result.Articles, result.Skipped))
+
+			return nil
+		},
+	}
+}
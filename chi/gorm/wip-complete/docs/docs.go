@@ -15,8 +15,92 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/log/level": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the current log level",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.LogLevelResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the log level at runtime",
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.SetLogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.LogLevelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
         "/articles": {
             "get": {
+                "description": "Articles are paginated with offsets by default, which returns a bare array.\nSending the cursor or limit query switches to cursor pagination, which returns a page with the next and prev cursors to follow, also in the Link header.",
                 "produces": [
                     "application/json"
                 ],
@@ -25,6 +109,332 @@ const docTemplate = `{
                 ],
                 "summary": "List all articles",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cursor of the page to load with cursor pagination, from a previous response. The first page is loaded if empty.",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "how many items per page with cursor pagination. Default to 10 if empty, at most 100.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "which page to load with offset pagination, can't be used with cursor or limit.",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "how many items per page with offset pagination. Default to 10 if empty, at most 100.",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to return the total count of articles, in total or the X-Total-Count header.",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to sort by, prefixed by - for descending order, e.g. -created_at,title. Only created_at with cursor pagination.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filters look like filter[field]=value or filter[field][operator]=value, see request.ArticleListQuery for fields and operators.",
+                        "name": "filter[user_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,title. All fields are returned if empty.",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "relations to embed, only author, whose public profile leaves out their email. Authors are loaded with a single query for the whole page.",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached copy, 304 is returned if it's still current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Page-domain_Article"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "Create an article",
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateArticleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Article"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/bulk": {
+            "post": {
+                "description": "Rows are read from NDJSON with an article per line, or from CSV with a header of user_id, title and content.\nEach row is validated like a created article, then rows are created in batches, a transaction per batch.\nThe outcome of each row is streamed back as NDJSON as soon as its batch is done, followed by a summary line.\nWith atomic=true, all rows are created in a single transaction, which is rolled back unless every row is created.\nRows of other users than the caller are rejected unless the caller is an admin.",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "Import articles in bulk",
+                "parameters": [
+                    {
+                        "description": "articles as NDJSON or CSV",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to import all rows or none.",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ImportRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/export": {
+            "get": {
+                "description": "Streams all the articles matching the filters, for analytics. Articles are read from a database cursor\nand written as they come, so exports take the same memory whatever their size.\nAn export failing midway is cut short, a JSON export then lacks its closing bracket.\nq needs PostgreSQL, exports searching other databases are rejected.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "Export articles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv, ndjson or json. Default to json if empty.",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to sort by, prefixed by - for descending order, e.g. -created_at,title. Default to id.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filters look like filter[field]=value or filter[field][operator]=value, see request.ArticleListQuery for fields and operators.",
+                        "name": "filter[user_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search terms to only export the matching articles, supporting quoted phrases, OR and -excluded words",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "text search configuration of q, e.g. english or german. Default to english if empty.",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Article"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/search": {
+            "get": {
+                "description": "Full-text search in titles and contents, the most relevant articles come first.\nFuzzy matching and facets need the bleve search backend.\nHeadlines are HTML-escaped fragments of the contents, only the matches are marked up with \u003cb\u003e\u003c/b\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "Search articles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search terms, supporting quoted phrases, OR and -excluded words",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "text search configuration, e.g. english or german. Default to english if empty.",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether terms also match with a typo.",
+                        "name": "fuzzy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to count the matching articles by author and month.",
+                        "name": "facets",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "which page to load. Default to 1 if empty.",
@@ -33,19 +443,95 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "how many items per page. Default to 10 if empty.",
+                        "description": "how many items per page. Default to 10 if empty, at most 100.",
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to return the total count of matching articles.",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ArticleSearchPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{articleID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "Get an article",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "article ID",
+                        "name": "articleID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,title. All fields are returned if empty.",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "relations to embed, only author, whose public profile leaves out their email.",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached copy, 304 is returned if it's still current",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Article"
-                            }
+                            "$ref": "#/definitions/domain.Article"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
                         }
                     },
                     "500": {
@@ -56,23 +542,36 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "articles"
                 ],
-                "summary": "Create an article",
+                "summary": "Update an article",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "article ID",
+                        "name": "articleID",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateArticleRequest"
+                            "$ref": "#/definitions/request.UpdateArticleRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the article the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -88,46 +587,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Err"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
-                    }
-                }
-            }
-        },
-        "/articles/search": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "articles"
-                ],
-                "summary": "Search articles",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "search by title",
-                        "name": "title",
-                        "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "description": "search by content",
-                        "name": "content",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Article"
-                            }
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
                         }
                     },
                     "500": {
@@ -137,17 +618,12 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/articles/{articleID}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
                     "articles"
                 ],
-                "summary": "Get an article",
+                "summary": "Delete an article",
                 "parameters": [
                     {
                         "type": "integer",
@@ -155,14 +631,17 @@ const docTemplate = `{
                         "name": "articleID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the article the deletion is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Article"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -170,12 +649,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Err"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Err"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -245,6 +742,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/request.SignupRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "replays the stored response when the request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -260,6 +763,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Err"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -285,6 +800,12 @@ const docTemplate = `{
                         "name": "userID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return, e.g. id,email. All fields are returned if empty.",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -314,15 +835,84 @@ const docTemplate = `{
         "domain.Article": {
             "type": "object",
             "properties": {
+                "author": {
+                    "description": "only when requested with include=author",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Author"
+                        }
+                    ]
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ArticleFacets": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "description": "by user ID, the most frequent first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FacetCount"
+                    }
+                },
+                "months": {
+                    "description": "by month of creation like 2024-01, the oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FacetCount"
+                    }
+                }
+            }
+        },
+        "domain.ArticleMatch": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "only when requested with include=author",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Author"
+                        }
+                    ]
+                },
                 "content": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "headline": {
+                    "description": "HTML-escaped fragments of the content with the matches in \u003cb\u003e\u003c/b\u003e",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "rank": {
+                    "description": "relevance of the article, higher is better",
+                    "type": "number"
+                },
                 "title": {
                     "type": "string"
                 },
@@ -331,6 +921,31 @@ const docTemplate = `{
                 },
                 "user_id": {
                     "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Author": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
@@ -346,6 +961,9 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "is_admin": {
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "type": "string"
                 }
@@ -385,6 +1003,26 @@ const docTemplate = `{
                 }
             }
         },
+        "request.SetLogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error",
+                        "dpanic",
+                        "panic",
+                        "fatal"
+                    ]
+                }
+            }
+        },
         "request.SignupRequest": {
             "type": "object",
             "required": [
@@ -404,15 +1042,226 @@ const docTemplate = `{
                 }
             }
         },
+        "request.UpdateArticleRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.ArticleSearchPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ArticleMatch"
+                    }
+                },
+                "facets": {
+                    "description": "only when requested with facets=true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ArticleFacets"
+                        }
+                    ]
+                },
+                "total": {
+                    "description": "only when requested with count=true",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.Code": {
+            "type": "string",
+            "enum": [
+                "bad_request",
+                "invalid_input",
+                "validation_failed",
+                "malformed_json",
+                "not_found",
+                "internal_error",
+                "wrong_credentials",
+                "unauthenticated",
+                "permission_denied",
+                "too_many_requests",
+                "precondition_failed",
+                "idempotency_key_reused",
+                "idempotency_key_in_progress",
+                "article_not_found",
+                "article_duplicated",
+                "article_modified",
+                "user_not_found",
+                "user_email_exists",
+                "search_unsupported",
+                "unsupported_media_type"
+            ],
+            "x-enum-varnames": [
+                "CodeBadRequest",
+                "CodeInvalidInput",
+                "CodeValidationFailed",
+                "CodeMalformedJSON",
+                "CodeNotFound",
+                "CodeInternal",
+                "CodeWrongCredentials",
+                "CodeUnauthenticated",
+                "CodePermissionDenied",
+                "CodeTooManyRequests",
+                "CodePreconditionFailed",
+                "CodeIdempotencyKeyReused",
+                "CodeIdempotencyKeyInProgress",
+                "CodeArticleNotFound",
+                "CodeArticleDuplicated",
+                "CodeArticleModified",
+                "CodeUserNotFound",
+                "CodeUserEmailExists",
+                "CodeSearchUnsupported",
+                "CodeUnsupportedMediaType"
+            ]
+        },
         "response.Err": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "stable application-specific error code",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.Code"
+                        }
+                    ],
+                    "example": "article_duplicated"
+                },
+                "column": {
+                    "description": "where decoding stopped, when code is malformed_json",
+                    "type": "integer"
+                },
+                "detail": {
+                    "description": "user-facing explanation of this occurrence",
+                    "type": "string",
+                    "example": "article already exists"
+                },
+                "errors": {
+                    "description": "invalid fields, when code is validation_failed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "URI of the request that caused the problem",
+                    "type": "string",
+                    "example": "/api/v1/articles"
+                },
+                "line": {
+                    "description": "where decoding stopped, when code is malformed_json",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "http response status code",
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "description": "short summary of the problem type",
+                    "type": "string",
+                    "example": "Article already exists"
+                },
+                "type": {
+                    "description": "URI identifying the problem type",
+                    "type": "string",
+                    "example": "urn:gab:error:article_duplicated"
+                }
+            }
+        },
+        "response.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "stable code of the failed rule",
+                    "type": "string",
+                    "example": "validation_length_out_of_range"
+                },
+                "field": {
+                    "description": "path of the field, made of JSON names",
+                    "type": "string",
+                    "example": "states[2].population"
+                },
+                "message": {
+                    "description": "user-facing message",
+                    "type": "string",
+                    "example": "the length must be between 1 and 128"
+                },
+                "params": {
+                    "description": "parameters of the failed rule, e.g. min and max",
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "response.ImportRow": {
+            "type": "object",
+            "properties": {
+                "article": {
+                    "description": "when created",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Article"
+                        }
+                    ]
+                },
                 "error": {
-                    "description": "user-facing error message",
+                    "description": "why the row isn't created",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.Err"
+                        }
+                    ]
+                },
+                "row": {
+                    "description": "position in the import, starting from 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "description": "created, duplicate or invalid",
+                    "type": "string",
+                    "example": "created"
+                }
+            }
+        },
+        "response.LogLevelResponse": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.Page-domain_Article": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Article"
+                    }
+                },
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
                     "type": "string"
                 },
-                "error_code": {
-                    "description": "application-specific error code",
+                "total": {
+                    "description": "only when requested with count=true",
                     "type": "integer"
                 }
             }
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,24 +14,47 @@ import (
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/middleware"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/cursor"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/fieldset"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/jwthelper"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/listquery"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
 )
 
 type ArticleService interface {
-	CreateArticle(ctx context.Context, article domain.Article) (domain.Article, error)
-	GetArticle(ctx context.Context, id uint) (domain.Article, error)
-	ListArticles(ctx context.Context, page uint, perPage uint) ([]domain.Article, error)
-	SearchArticles(ctx context.Context, title, content string) ([]domain.Article, error)
+	CreateArticle(ctx context.Context, caller domain.Caller, article domain.Article) (domain.Article, error)
+	GetArticle(ctx context.Context, id uint, relations domain.ArticleRelations) (domain.Article, error)
+	ListArticles(ctx context.Context, page uint, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) ([]domain.Article, error)
+	ListArticlesByCursor(ctx context.Context, cursor *domain.Cursor, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) (domain.Page[domain.Article], error)
+	CountArticles(ctx context.Context, spec listquery.Spec) (int64, error)
+	ExportArticles(ctx context.Context, spec listquery.Spec, export domain.ArticleExport, fn func(domain.Article) error) error
+	SearchArticles(ctx context.Context, search domain.ArticleSearch) (domain.ArticleSearchResult, error)
+	UpdateArticle(ctx context.Context, caller domain.Caller, article domain.Article) (domain.Article, error)
+	DeleteArticle(ctx context.Context, caller domain.Caller, id, version uint) error
+	ImportArticles(ctx context.Context, caller domain.Caller, batchSize int, atomic bool, next func() (domain.ArticleImportRow, error), report func(rows []domain.ArticleImportRow) error) error
 }
 
+// defaultImportBatchSize is how many rows of a bulk import are created per transaction when it isn't configured.
+const defaultImportBatchSize = 100
+
 type ArticleHandler struct {
-	svc ArticleService
+	svc             ArticleService
+	cursors         *cursor.Codec
+	importBatchSize int
 }
 
-func NewArticleHandler(svc ArticleService) *ArticleHandler {
+func NewArticleHandler(svc ArticleService, cursors *cursor.Codec, importConf *config.ImportConfig) *ArticleHandler {
+	importBatchSize := defaultImportBatchSize
+	if importConf != nil && importConf.BatchSize > 0 {
+		importBatchSize = importConf.BatchSize
+	}
+
 	return &ArticleHandler{
-		svc: svc,
+		svc:             svc,
+		cursors:         cursors,
+		importBatchSize: importBatchSize,
 	}
 }
 
@@ -39,8 +63,13 @@ func NewArticleHandler(svc ArticleService) *ArticleHandler {
 // @Tags         articles
 // @Produce      json
 // @Param        request   body      request.CreateArticleRequest true "request body"
+// @Param        Idempotency-Key header string false "replays the stored response when the request is retried with the same key"
 // @Success      200      {object}   domain.Article
 // @Failure      400      {object}   response.Err
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Failure      409      {object}   response.Err
+// @Failure      422      {object}   response.Err
 // @Failure      500      {object}   response.Err
 // @Router       /articles [post]
 func (h *ArticleHandler) HandleCreateArticle(w http.ResponseWriter, r *http.Request) {
@@ -51,17 +80,34 @@ func (h *ArticleHandler) HandleCreateArticle(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	article, err := h.svc.CreateArticle(r.Context(), domain.Article{
+	caller, err := callerFromContext(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	article, err := h.svc.CreateArticle(r.Context(), caller, domain.Article{
 		UserID:  req.UserID,
 		Title:   req.Title,
 		Content: req.Content,
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			_ = render.Render(w, r, response.ErrPermissionDenied(service.ErrPermissionDenied))
+
+			return
+		}
 		if errors.Is(err, service.ErrArticleDuplicated) {
 			_ = render.Render(w, r, response.ErrBadRequest(service.ErrArticleDuplicated))
 
 			return
 		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			_ = render.Render(w, r, response.ErrBadRequest(service.ErrUserNotFound))
+
+			return
+		}
 
 		err = fmt.Errorf("v1.HandleCreateArticle -> h.svc.CreateArticle -> %w", err)
 		_ = render.Render(w, r, response.ErrInternalServerError(err))
@@ -69,16 +115,75 @@ func (h *ArticleHandler) HandleCreateArticle(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	w.Header().Set("ETag", articleETag(article))
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, article)
 }
 
+// HandleImportArticles godoc
+// @Summary      Import articles in bulk
+// @Description  Rows are read from NDJSON with an article per line, or from CSV with a header of user_id, title and content.
+// @Description  Each row is validated like a created article, then rows are created in batches, a transaction per batch.
+// @Description  The outcome of each row is streamed back as NDJSON as soon as its batch is done, followed by a summary line.
+// @Description  With atomic=true, all rows are created in a single transaction, which is rolled back unless every row is created.
+// @Description  Rows of other users than the caller are rejected unless the caller is an admin.
+// @Tags         articles
+// @Accept       application/x-ndjson,text/csv
+// @Produce      application/x-ndjson
+// @Param        request  body       string  true   "articles as NDJSON or CSV"
+// @Param        atomic   query      bool    false  "whether to import all rows or none."
+// @Success      200      {object}   response.ImportRow
+// @Failure      400      {object}   response.Err
+// @Failure      401      {object}   response.Err
+// @Failure      415      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/bulk [post]
+func (h *ArticleHandler) HandleImportArticles(w http.ResponseWriter, r *http.Request) {
+	caller, err := callerFromContext(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	atomic, err := parseBoolQuery(r.URL.Query().Get("atomic"))
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("atomic", r.URL.Query().Get("atomic")))
+
+		return
+	}
+
+	rows, err := request.NewArticleImportReader(r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		if errors.Is(err, request.ErrUnsupportedImport) {
+			_ = render.Render(w, r, response.ErrUnsupportedMediaType(err))
+
+			return
+		}
+
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	report := response.NewImportReport(w, r)
+	err = h.svc.ImportArticles(r.Context(), caller, h.importBatchSize, atomic, rows.Next, report.Rows)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleImportArticles -> h.svc.ImportArticles -> %w", err)
+	}
+	report.Finish(err, atomic)
+}
+
 // HandleGetArticle godoc
 // @Summary      Get an article
 // @Tags         articles
 // @Produce      json
 // @Param        articleID   path    int  true "article ID"
+// @Param        fields   query      string  false  "comma separated fields to return, e.g. id,title. All fields are returned if empty."
+// @Param        include  query      string  false  "relations to embed, only author, whose public profile leaves out their email."
+// @Param        If-None-Match header string false "ETag of the cached copy, 304 is returned if it's still current"
 // @Success      200      {object}   domain.Article
+// @Success      304
 // @Failure      400      {object}   response.Err
 // @Failure      404      {object}   response.Err
 // @Failure      500      {object}   response.Err
@@ -98,7 +203,14 @@ func (h *ArticleHandler) HandleGetArticle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	article, err := h.svc.GetArticle(r.Context(), uint(articleID))
+	fields, err := request.ArticleFields.Parse(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	article, err := h.svc.GetArticle(r.Context(), uint(articleID), articleRelations(fields))
 	if err != nil {
 		if errors.Is(err, service.ErrArticleNotFound) {
 			_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
@@ -112,38 +224,160 @@ func (h *ArticleHandler) HandleGetArticle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	render.Status(r, http.StatusOK)
-	render.JSON(w, r, article)
+	body, err := fields.Select(article)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleGetArticle -> fields.Select -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	// The author may change without a new version of the article.
+	tag := articleETag(article)
+	if article.Author != nil {
+		if tag, err = articleContentETag(article, body); err != nil {
+			err = fmt.Errorf("v1.HandleGetArticle -> articleContentETag -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+			return
+		}
+	}
+
+	renderWithETag(w, r, tag, body)
 }
 
 // HandleListArticles godoc
 // @Summary      List all articles
+// @Description  Articles are paginated with offsets by default, which returns a bare array.
+// @Description  Sending the cursor or limit query switches to cursor pagination, which returns a page with the next and prev cursors to follow, also in the Link header.
 // @Tags         articles
 // @Produce      json
-// @Param        page     query      int  false  "which page to load. Default to 1 if empty."
-// @Param        per_page query      int  false  "how many items per page. Default to 10 if empty."
-// @Success      200      {object}   []domain.Article
+// @Param        cursor   query      string  false  "cursor of the page to load with cursor pagination, from a previous response. The first page is loaded if empty."
+// @Param        limit    query      int  false  "how many items per page with cursor pagination. Default to 10 if empty, at most 100."
+// @Param        page     query      int  false  "which page to load with offset pagination, can't be used with cursor or limit."
+// @Param        per_page query      int  false  "how many items per page with offset pagination. Default to 10 if empty, at most 100."
+// @Param        count    query      bool false  "whether to return the total count of articles, in total or the X-Total-Count header."
+// @Param        sort     query      string  false  "comma separated fields to sort by, prefixed by - for descending order, e.g. -created_at,title. Only created_at with cursor pagination."
+// @Param        filter[user_id]  query  int  false  "filters look like filter[field]=value or filter[field][operator]=value, see request.ArticleListQuery for fields and operators."
+// @Param        fields   query      string  false  "comma separated fields to return, e.g. id,title. All fields are returned if empty."
+// @Param        include  query      string  false  "relations to embed, only author, whose public profile leaves out their email. Authors are loaded with a single query for the whole page."
+// @Param        If-None-Match header string false "ETag of the cached copy, 304 is returned if it's still current"
+// @Success      200      {object}   response.Page[domain.Article]
+// @Success      304
+// @Failure      400      {object}   response.Err
 // @Failure      500      {object}   response.Err
 // @Router       /articles [get]
 func (h *ArticleHandler) HandleListArticles(w http.ResponseWriter, r *http.Request) {
-	pageVal := r.Context().Value(middleware.PageQueryKey)
-	page, ok := pageVal.(uint)
+	perPageVal := r.Context().Value(middleware.PerPageQueryKey)
+	perPage, ok := perPageVal.(uint)
 	if !ok {
-		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PageQueryKey, pageVal)
+		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PerPageQueryKey, perPageVal)
 		_ = render.Render(w, r, response.ErrInternalServerError(err))
 
 		return
 	}
-	perPageVal := r.Context().Value(middleware.PerPageQueryKey)
-	perPage, ok := perPageVal.(uint)
+	withCount, _ := r.Context().Value(middleware.CountQueryKey).(bool)
+	page, byOffset := r.Context().Value(middleware.PageQueryKey).(uint)
+
+	schema := request.ArticleCursorListQuery
+	if byOffset {
+		schema = request.ArticleListQuery
+	}
+	spec, err := schema.Parse(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+	fields, err := request.ArticleFields.Parse(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	if byOffset {
+		h.listArticlesByOffset(w, r, page, perPage, withCount, spec, fields)
+
+		return
+	}
+
+	cursorVal := r.Context().Value(middleware.CursorQueryKey)
+	from, ok := cursorVal.(*domain.Cursor)
 	if !ok {
-		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PerPageQueryKey, perPage)
+		err := fmt.Errorf("key %q's value %v cannot be casted into *domain.Cursor", middleware.CursorQueryKey, cursorVal)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	h.listArticlesByCursor(w, r, from, perPage, withCount, spec, fields)
+}
+
+func (h *ArticleHandler) listArticlesByCursor(w http.ResponseWriter, r *http.Request, from *domain.Cursor, perPage uint, withCount bool, spec listquery.Spec, fields fieldset.Spec) {
+	page, err := h.svc.ListArticlesByCursor(r.Context(), from, perPage, spec, articleRelations(fields))
+	if err != nil {
+		err = fmt.Errorf("v1.HandleListArticles -> h.svc.ListArticlesByCursor -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	data, err := fieldset.SelectAll(fields, page.Items)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleListArticles -> fieldset.SelectAll -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	resp := response.Page[json.RawMessage]{
+		Data: data,
+	}
+	if page.Next != nil {
+		if resp.Next, err = h.cursors.Encode(page.Next); err != nil {
+			err = fmt.Errorf("v1.HandleListArticles -> h.cursors.Encode -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+			return
+		}
+	}
+	if page.Prev != nil {
+		if resp.Prev, err = h.cursors.Encode(page.Prev); err != nil {
+			err = fmt.Errorf("v1.HandleListArticles -> h.cursors.Encode -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+			return
+		}
+	}
+
+	if withCount {
+		total, err := h.svc.CountArticles(r.Context(), spec)
+		if err != nil {
+			err = fmt.Errorf("v1.HandleListArticles -> h.svc.CountArticles -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+			return
+		}
+
+		resp.Total = &total
+		w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	}
+
+	tag, err := contentETag(resp)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleListArticles -> contentETag -> %w", err)
 		_ = render.Render(w, r, response.ErrInternalServerError(err))
 
 		return
 	}
 
-	articles, err := h.svc.ListArticles(r.Context(), page, perPage)
+	cursorLinks(r, perPage, resp.Prev, resp.Next).set(w)
+	renderWithETag(w, r, tag, resp)
+}
+
+func (h *ArticleHandler) listArticlesByOffset(w http.ResponseWriter, r *http.Request, page, perPage uint, withCount bool, spec listquery.Spec, fields fieldset.Spec) {
+	articles, err := h.svc.ListArticles(r.Context(), page, perPage, spec, articleRelations(fields))
 	if err != nil {
 		err = fmt.Errorf("v1.HandleListArticles -> h.svc.ListArticles -> %w", err)
 		_ = render.Render(w, r, response.ErrInternalServerError(err))
@@ -151,31 +385,372 @@ func (h *ArticleHandler) HandleListArticles(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	render.Status(r, http.StatusOK)
-	render.JSON(w, r, articles)
+	data, err := fieldset.SelectAll(fields, articles)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleListArticles -> fieldset.SelectAll -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	var total *int64
+	if withCount {
+		count, err := h.svc.CountArticles(r.Context(), spec)
+		if err != nil {
+			err = fmt.Errorf("v1.HandleListArticles -> h.svc.CountArticles -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+			return
+		}
+
+		total = &count
+		w.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	}
+
+	tag, err := contentETag(data)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleListArticles -> contentETag -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	offsetLinks(r, page, perPage, len(articles), total).set(w)
+	renderWithETag(w, r, tag, data)
+}
+
+// HandleExportArticles godoc
+// @Summary      Export articles
+// @Description  Streams all the articles matching the filters, for analytics. Articles are read from a database cursor
+// @Description  and written as they come, so exports take the same memory whatever their size.
+// @Description  An export failing midway is cut short, a JSON export then lacks its closing bracket.
+// @Description  q needs PostgreSQL, exports searching other databases are rejected.
+// @Tags         articles
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        format   query      string  false  "csv, ndjson or json. Default to json if empty."
+// @Param        sort     query      string  false  "comma separated fields to sort by, prefixed by - for descending order, e.g. -created_at,title. Default to id."
+// @Param        filter[user_id]  query  int  false  "filters look like filter[field]=value or filter[field][operator]=value, see request.ArticleListQuery for fields and operators."
+// @Param        q        query      string  false  "search terms to only export the matching articles, supporting quoted phrases, OR and -excluded words"
+// @Param        lang     query      string  false  "text search configuration of q, e.g. english or german. Default to english if empty."
+// @Success      200      {array}    domain.Article
+// @Failure      400      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/export [get]
+func (h *ArticleHandler) HandleExportArticles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := request.ExportArticlesRequest{
+		Format:   query.Get("format"),
+		Query:    query.Get("q"),
+		Language: query.Get("lang"),
+	}
+	if err := req.Validate(); err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+	if req.Format == "" {
+		req.Format = request.ExportJSON
+	}
+	if req.Language == "" {
+		req.Language = domain.DefaultSearchLanguage
+	}
+
+	spec, err := request.ArticleListQuery.Parse(query)
+	if err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	export := response.NewArticleExport(w, req.Format)
+	err = h.svc.ExportArticles(r.Context(), spec, domain.ArticleExport{
+		Query:    req.Query,
+		Language: req.Language,
+	}, export.Write)
+	if err == nil {
+		err = export.Close()
+	}
+	if err != nil {
+		err = fmt.Errorf("v1.HandleExportArticles -> h.svc.ExportArticles -> %w", err)
+		if export.Started() {
+			export.Fail(err)
+
+			return
+		}
+
+		if errors.Is(err, service.ErrSearchUnsupported) {
+			_ = render.Render(w, r, response.ErrBadRequest(err))
+
+			return
+		}
+
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+	}
 }
 
 // HandleSearchArticles godoc
 // @Summary      Search articles
+// @Description  Full-text search in titles and contents, the most relevant articles come first.
+// @Description  Fuzzy matching and facets need the bleve search backend.
+// @Description  Headlines are HTML-escaped fragments of the contents, only the matches are marked up with <b></b>.
 // @Tags         articles
 // @Produce      json
-// @Param        title    query     string  false  "search by title"
-// @Param        content  query     string  false  "search by content"
-// @Success      200      {object}   []domain.Article
+// @Param        q        query      string  true   "search terms, supporting quoted phrases, OR and -excluded words"
+// @Param        lang     query      string  false  "text search configuration, e.g. english or german. Default to english if empty."
+// @Param        fuzzy    query      bool false  "whether terms also match with a typo."
+// @Param        facets   query      bool false  "whether to count the matching articles by author and month."
+// @Param        page     query      int  false  "which page to load. Default to 1 if empty."
+// @Param        per_page query      int  false  "how many items per page. Default to 10 if empty, at most 100."
+// @Param        count    query      bool false  "whether to return the total count of matching articles."
+// @Success      200      {object}   response.ArticleSearchPage
+// @Failure      400      {object}   response.Err
 // @Failure      500      {object}   response.Err
 // @Router       /articles/search [get]
 func (h *ArticleHandler) HandleSearchArticles(w http.ResponseWriter, r *http.Request) {
-	titleParam := r.URL.Query().Get("title")
-	contentParam := r.URL.Query().Get("content")
+	query := r.URL.Query()
+	fuzzy, err := parseBoolQuery(query.Get("fuzzy"))
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("fuzzy", query.Get("fuzzy")))
+
+		return
+	}
+	facets, err := parseBoolQuery(query.Get("facets"))
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("facets", query.Get("facets")))
+
+		return
+	}
+
+	req := request.SearchArticlesRequest{
+		Query:    query.Get("q"),
+		Language: query.Get("lang"),
+	}
+	if err = req.Validate(); err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+	if req.Language == "" {
+		req.Language = domain.DefaultSearchLanguage
+	}
+
+	pageVal := r.Context().Value(middleware.PageQueryKey)
+	page, ok := pageVal.(uint)
+	if !ok {
+		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PageQueryKey, pageVal)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+	perPageVal := r.Context().Value(middleware.PerPageQueryKey)
+	perPage, ok := perPageVal.(uint)
+	if !ok {
+		err := fmt.Errorf("key %q's value %v cannot be casted into uint", middleware.PerPageQueryKey, perPageVal)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
 
-	articles, err := h.svc.SearchArticles(r.Context(), titleParam, contentParam)
+		return
+	}
+	withCount, _ := r.Context().Value(middleware.CountQueryKey).(bool)
+
+	result, err := h.svc.SearchArticles(r.Context(), domain.ArticleSearch{
+		Query:    req.Query,
+		Language: req.Language,
+		Page:     page,
+		PerPage:  perPage,
+		Fuzzy:    fuzzy,
+		Count:    withCount,
+		Facets:   facets,
+	})
 	if err != nil {
+		if errors.Is(err, service.ErrSearchUnsupported) {
+			_ = render.Render(w, r, response.ErrBadRequest(err))
+
+			return
+		}
+
 		err = fmt.Errorf("v1.HandleSearchArticles -> h.svc.SearchArticles -> %w", err)
 		_ = render.Render(w, r, response.ErrInternalServerError(err))
 
 		return
 	}
 
+	if result.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(*result.Total, 10))
+	}
+
+	offsetLinks(r, page, perPage, len(result.Matches), result.Total).set(w)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response.ArticleSearchPage{
+		Data:   result.Matches,
+		Total:  result.Total,
+		Facets: result.Facets,
+	})
+}
+
+// HandleUpdateArticle godoc
+// @Summary      Update an article
+// @Tags         articles
+// @Produce      json
+// @Param        articleID   path    int  true "article ID"
+// @Param        request   body      request.UpdateArticleRequest true "request body"
+// @Param        If-Match header string false "ETag of the article the change is based on"
+// @Success      200      {object}   domain.Article
+// @Failure      400      {object}   response.Err
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Failure      404      {object}   response.Err
+// @Failure      412      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/{articleID} [put]
+func (h *ArticleHandler) HandleUpdateArticle(w http.ResponseWriter, r *http.Request) {
+	rawArticleID := chi.URLParam(r, "articleID")
+	articleID, err := strconv.Atoi(rawArticleID)
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("articleID", rawArticleID))
+
+		return
+	}
+
+	if articleID <= 0 {
+		_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+		return
+	}
+
+	version, ok := versionFromIfMatch(r, uint(articleID))
+	if !ok {
+		_ = render.Render(w, r, response.ErrPreconditionFailed(service.ErrArticleModified))
+
+		return
+	}
+
+	req := request.UpdateArticleRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	caller, err := callerFromContext(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	article, err := h.svc.UpdateArticle(r.Context(), caller, domain.Article{
+		ID:      uint(articleID),
+		Title:   req.Title,
+		Content: req.Content,
+		Version: version,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrArticleNotFound):
+			_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+		case errors.Is(err, service.ErrPermissionDenied):
+			_ = render.Render(w, r, response.ErrPermissionDenied(service.ErrPermissionDenied))
+		case errors.Is(err, service.ErrArticleModified):
+			_ = render.Render(w, r, response.ErrPreconditionFailed(service.ErrArticleModified))
+		case errors.Is(err, service.ErrArticleDuplicated):
+			_ = render.Render(w, r, response.ErrBadRequest(service.ErrArticleDuplicated))
+		default:
+			err = fmt.Errorf("v1.HandleUpdateArticle -> h.svc.UpdateArticle -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+		}
+
+		return
+	}
+
+	w.Header().Set("ETag", articleETag(article))
 	render.Status(r, http.StatusOK)
-	render.JSON(w, r, articles)
+	render.JSON(w, r, article)
+}
+
+// HandleDeleteArticle godoc
+// @Summary      Delete an article
+// @Tags         articles
+// @Param        articleID   path    int  true "article ID"
+// @Param        If-Match header string false "ETag of the article the deletion is based on"
+// @Success      204
+// @Failure      400      {object}   response.Err
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Failure      404      {object}   response.Err
+// @Failure      412      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /articles/{articleID} [delete]
+func (h *ArticleHandler) HandleDeleteArticle(w http.ResponseWriter, r *http.Request) {
+	rawArticleID := chi.URLParam(r, "articleID")
+	articleID, err := strconv.Atoi(rawArticleID)
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInvalidInput("articleID", rawArticleID))
+
+		return
+	}
+
+	if articleID <= 0 {
+		_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+
+		return
+	}
+
+	version, ok := versionFromIfMatch(r, uint(articleID))
+	if !ok {
+		_ = render.Render(w, r, response.ErrPreconditionFailed(service.ErrArticleModified))
+
+		return
+	}
+
+	caller, err := callerFromContext(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	err = h.svc.DeleteArticle(r.Context(), caller, uint(articleID), version)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrArticleNotFound):
+			_ = render.Render(w, r, response.ErrNotFound("article", "ID", articleID))
+		case errors.Is(err, service.ErrPermissionDenied):
+			_ = render.Render(w, r, response.ErrPermissionDenied(service.ErrPermissionDenied))
+		case errors.Is(err, service.ErrArticleModified):
+			_ = render.Render(w, r, response.ErrPreconditionFailed(service.ErrArticleModified))
+		default:
+			err = fmt.Errorf("v1.HandleDeleteArticle -> h.svc.DeleteArticle -> %w", err)
+			_ = render.Render(w, r, response.ErrInternalServerError(err))
+		}
+
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// callerFromContext is the caller of a request authenticated by middleware.Authenticator.
+func callerFromContext(ctx context.Context) (domain.Caller, error) {
+	claims, err := jwthelper.RetrieveClaimsFromContext(ctx)
+	if err != nil {
+		return domain.Caller{}, err
+	}
+
+	return domain.Caller{UserID: claims.UserID, IsAdmin: claims.IsAdmin}, nil
+}
+
+// parseBoolQuery parses an optional boolean query, false when it's empty.
+func parseBoolQuery(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// articleRelations are the relations of articles to load for the include query.
+func articleRelations(fields fieldset.Spec) domain.ArticleRelations {
+	return domain.ArticleRelations{
+		Author: fields.Includes(request.ArticleAuthor),
+	}
 }
@@ -38,8 +38,11 @@ func NewAuthHandler(conf *config.APIConfig, svc AuthService) *AuthHandler {
 // @Tags         auth
 // @Produce      json
 // @Param        request   body      request.SignupRequest true "request body"
+// @Param        Idempotency-Key header string false "replays the stored response when the request is retried with the same key"
 // @Success      201      {object}   domain.User
 // @Failure      400      {object}   response.Err
+// @Failure      409      {object}   response.Err
+// @Failure      422      {object}   response.Err
 // @Failure      500      {object}   response.Err
 // @Router       /auth/signup [post]
 func (h *AuthHandler) HandleSignup(w http.ResponseWriter, r *http.Request) {
@@ -102,7 +105,7 @@ func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := jwthelper.GenerateToken([]byte(h.conf.JWTSigningKey), user.ID, r.UserAgent())
+	token, err := jwthelper.GenerateToken([]byte(h.conf.JWTSigningKey), user.ID, r.UserAgent(), user.IsAdmin)
 	if err != nil {
 		err = fmt.Errorf("v1.HandleSignup -> middleware.GenerateToken() -> %w", err)
 		_ = render.Render(w, r, response.ErrInternalServerError(err))
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/etag"
+)
+
+// articleETag is derived from the version, so it can be checked by the UPDATE itself when sent back in If-Match.
+func articleETag(article domain.Article) string {
+	return etag.New(fmt.Sprintf("%d-%d", article.ID, article.Version))
+}
+
+// articleContentETag is articleETag for representations embedding relations, which may change without a new
+// version of the article. A digest of body follows the version, e.g. "1-2-<digest>", which versionFromIfMatch ignores.
+func articleContentETag(article domain.Article, body any) (string, error) {
+	tag, err := contentETag(body)
+	if err != nil {
+		return "", err
+	}
+	digest, _ := etag.Value(tag)
+
+	return etag.New(fmt.Sprintf("%d-%d-%s", article.ID, article.Version, digest)), nil
+}
+
+// versionFromIfMatch returns the article version a write is based on, taken from the If-Match header.
+// Zero means the write is unconditional, because the header is absent or "*".
+// ok is false when none of the entity tags belongs to the article, so the precondition has already failed.
+func versionFromIfMatch(r *http.Request, articleID uint) (version uint, ok bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return 0, true
+	}
+
+	for _, tag := range etag.Parse(ifMatch) {
+		if tag == "*" {
+			return 0, true
+		}
+
+		value, ok := etag.Value(tag)
+		if !ok {
+			continue
+		}
+
+		var id uint
+		if _, err := fmt.Sscanf(value, "%d-%d", &id, &version); err == nil && id == articleID && version > 0 {
+			return version, true
+		}
+	}
+
+	return 0, false
+}
+
+// contentETag is derived from the JSON representation, for responses without a single version to rely on.
+func contentETag(v any) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal -> %w", err)
+	}
+
+	return etag.FromBytes(body), nil
+}
+
+// renderWithETag sends v along with its entity tag, or 304 Not Modified when the client's copy is still current.
+func renderWithETag(w http.ResponseWriter, r *http.Request, tag string, v any) {
+	w.Header().Set("ETag", tag)
+
+	if etag.Fresh(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, v)
+}
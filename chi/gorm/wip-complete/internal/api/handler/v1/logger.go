@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/logger"
+)
+
+// HandleGetLogLevel godoc
+// @Summary      Get the current log level
+// @Tags         admin
+// @Produce      json
+// @Success      200      {object}   response.LogLevelResponse
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Router       /admin/log/level [get]
+func HandleGetLogLevel(w http.ResponseWriter, r *http.Request) {
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response.LogLevelResponse{
+		Level: logger.Level().String(),
+	})
+}
+
+// HandleSetLogLevel godoc
+// @Summary      Change the log level at runtime
+// @Tags         admin
+// @Produce      json
+// @Param        request   body      request.SetLogLevelRequest true "request body"
+// @Success      200      {object}   response.LogLevelResponse
+// @Failure      400      {object}   response.Err
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /admin/log/level [put]
+func HandleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	req := request.SetLogLevelRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
+	previous := logger.Level()
+	if err := logger.SetLevel(req.Level); err != nil {
+		err = fmt.Errorf("v1.HandleSetLogLevel -> logger.SetLevel -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	zap.L().Warn(
+		"log level changed",
+		zap.Stringer("from", previous),
+		zap.Stringer("to", logger.Level()),
+	)
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response.LogLevelResponse{
+		Level: logger.Level().String(),
+	})
+}
@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/middleware"
+)
+
+// pageLinks builds a Link header from RFC 8288 pointing at other pages of the requested list,
+// see https://www.rfc-editor.org/rfc/rfc8288.
+type pageLinks struct {
+	r     *http.Request
+	links []string
+}
+
+// add links rel to the requested list with the given pagination queries, the other queries are kept.
+func (l *pageLinks) add(rel string, pagination map[string]string) {
+	query := l.r.URL.Query()
+	query.Del(middleware.PageQueryKey)
+	query.Del(middleware.PerPageQueryKey)
+	query.Del(middleware.CursorQueryKey)
+	query.Del(middleware.LimitQueryKey)
+	for key, value := range pagination {
+		query.Set(key, value)
+	}
+
+	target := url.URL{Path: l.r.URL.Path, RawQuery: query.Encode()}
+	l.links = append(l.links, fmt.Sprintf("<%s>; rel=%q", target.String(), rel))
+}
+
+func (l *pageLinks) set(w http.ResponseWriter) {
+	if len(l.links) > 0 {
+		w.Header().Set("Link", strings.Join(l.links, ", "))
+	}
+}
+
+// offsetLinks links the first, previous, next and last pages of an offset paginated list.
+// The last page is only known with the total count, total is nil otherwise.
+func offsetLinks(r *http.Request, page, perPage uint, found int, total *int64) *pageLinks {
+	links := &pageLinks{r: r}
+	pageQuery := func(page uint) map[string]string {
+		return map[string]string{
+			middleware.PageQueryKey:    strconv.FormatUint(uint64(page), 10),
+			middleware.PerPageQueryKey: strconv.FormatUint(uint64(perPage), 10),
+		}
+	}
+
+	links.add("first", pageQuery(1))
+	if page > 1 {
+		links.add("prev", pageQuery(page-1))
+	}
+
+	if total == nil {
+		if uint(found) == perPage {
+			links.add("next", pageQuery(page+1))
+		}
+
+		return links
+	}
+
+	lastPage := uint((*total + int64(perPage) - 1) / int64(perPage))
+	if lastPage == 0 {
+		lastPage = 1
+	}
+	if page < lastPage {
+		links.add("next", pageQuery(page+1))
+	}
+	links.add("last", pageQuery(lastPage))
+
+	return links
+}
+
+// cursorLinks links the first, previous and next pages of a cursor paginated list, empty cursors are skipped.
+// Links carry the limit, so that following them stays in cursor pagination.
+func cursorLinks(r *http.Request, limit uint, prev, next string) *pageLinks {
+	links := &pageLinks{r: r}
+	cursorQuery := func(token string) map[string]string {
+		query := map[string]string{middleware.LimitQueryKey: strconv.FormatUint(uint64(limit), 10)}
+		if token != "" {
+			query[middleware.CursorQueryKey] = token
+		}
+
+		return query
+	}
+
+	links.add("first", cursorQuery(""))
+	if prev != "" {
+		links.add("prev", cursorQuery(prev))
+	}
+	if next != "" {
+		links.add("next", cursorQuery(next))
+	}
+
+	return links
+}
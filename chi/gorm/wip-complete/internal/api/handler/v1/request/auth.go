@@ -1,13 +1,12 @@
 package request
 
 import (
-	"errors"
 	"net/http"
 
 	regexp "github.com/dlclark/regexp2"
 
-	validation "github.com/go-ozzo/ozzo-validation"
-	"github.com/go-ozzo/ozzo-validation/is"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
 const (
@@ -15,8 +14,8 @@ const (
 )
 
 var (
-	errInvalidPassword         = errors.New("the password must be at least 8 characters and contain 1 letter, 1 number and 1 symbol")
-	errConfirmPasswordMismatch = errors.New("confirm password doesn't match the password")
+	errInvalidPassword         = validation.NewError("validation_password_too_weak", "the password must be at least 8 characters and contain 1 letter, 1 number and 1 symbol")
+	errConfirmPasswordMismatch = validation.NewError("validation_password_mismatch", "confirm password doesn't match the password")
 )
 
 type SignupRequest struct {
@@ -26,18 +25,19 @@ type SignupRequest struct {
 }
 
 func (req *SignupRequest) Validate() error {
-	err := validation.ValidateStruct(
+	return validation.ValidateStruct(
 		req,
 		validation.Field(&req.Email, validation.Required, is.Email),
-		validation.Field(&req.Password, validation.Required),
-		validation.Field(&req.ConfirmPassword, validation.Required),
+		validation.Field(&req.Password, validation.Required, validation.By(strongPassword)),
+		validation.Field(&req.ConfirmPassword, validation.Required, validation.In(req.Password).ErrorObject(errConfirmPasswordMismatch)),
 	)
-	if err != nil {
-		return err
-	}
+}
+
+func strongPassword(value any) error {
+	password, _ := value.(string)
 
 	passwordExp := regexp.MustCompile(passwordRegexPattern, regexp.None)
-	ok, err := passwordExp.MatchString(req.Password)
+	ok, err := passwordExp.MatchString(password)
 	if err != nil {
 		return err
 	}
@@ -45,10 +45,6 @@ func (req *SignupRequest) Validate() error {
 		return errInvalidPassword
 	}
 
-	if req.Password != req.ConfirmPassword {
-		return errConfirmPasswordMismatch
-	}
-
 	return nil
 }
 
@@ -0,0 +1,26 @@
+package request
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type SetLogLevelRequest struct {
+	Level string `json:"level" validate:"required" enums:"debug,info,warn,error,dpanic,panic,fatal"`
+}
+
+func (req *SetLogLevelRequest) Validate() error {
+	return validation.ValidateStruct(
+		req,
+		validation.Field(&req.Level, validation.Required, validation.In("debug", "info", "warn", "error", "dpanic", "panic", "fatal")),
+	)
+}
+
+func (req *SetLogLevelRequest) Bind(r *http.Request) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
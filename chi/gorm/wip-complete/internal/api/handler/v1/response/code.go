@@ -0,0 +1,92 @@
+package response
+
+import (
+	"errors"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
+)
+
+// Code is a stable application error code. Clients should rely on it rather than on error messages,
+// which are meant for humans and may change.
+type Code string
+
+const (
+	CodeBadRequest               Code = "bad_request"
+	CodeInvalidInput             Code = "invalid_input"
+	CodeValidationFailed         Code = "validation_failed"
+	CodeMalformedJSON            Code = "malformed_json"
+	CodeNotFound                 Code = "not_found"
+	CodeInternal                 Code = "internal_error"
+	CodeWrongCredentials         Code = "wrong_credentials"
+	CodeUnauthenticated          Code = "unauthenticated"
+	CodePermissionDenied         Code = "permission_denied"
+	CodeTooManyRequests          Code = "too_many_requests"
+	CodePreconditionFailed       Code = "precondition_failed"
+	CodeIdempotencyKeyReused     Code = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress Code = "idempotency_key_in_progress"
+	CodeArticleNotFound          Code = "article_not_found"
+	CodeArticleDuplicated        Code = "article_duplicated"
+	CodeArticleModified          Code = "article_modified"
+	CodeUserNotFound             Code = "user_not_found"
+	CodeUserEmailExists          Code = "user_email_exists"
+	CodeSearchUnsupported        Code = "search_unsupported"
+	CodeUnsupportedMediaType     Code = "unsupported_media_type"
+)
+
+// typePrefix makes a problem type URI out of a code, see https://www.rfc-editor.org/rfc/rfc9457#section-3.1.1.
+const typePrefix = "urn:gab:error:"
+
+// titles are short summaries of each code, they are the same for every occurrence of the problem.
+var titles = map[Code]string{
+	CodeBadRequest:               "Bad request",
+	CodeInvalidInput:             "Invalid input",
+	CodeValidationFailed:         "Validation failed",
+	CodeMalformedJSON:            "Malformed JSON",
+	CodeNotFound:                 "Resource not found",
+	CodeInternal:                 "Internal server error",
+	CodeWrongCredentials:         "Wrong credentials",
+	CodeUnauthenticated:          "Authentication required",
+	CodePermissionDenied:         "Permission denied",
+	CodeTooManyRequests:          "Too many requests",
+	CodePreconditionFailed:       "Precondition failed",
+	CodeIdempotencyKeyReused:     "Idempotency key reused",
+	CodeIdempotencyKeyInProgress: "Idempotency key in progress",
+	CodeArticleNotFound:          "Article not found",
+	CodeArticleDuplicated:        "Article already exists",
+	CodeArticleModified:          "Article modified",
+	CodeUserNotFound:             "User not found",
+	CodeUserEmailExists:          "User already exists",
+	CodeSearchUnsupported:        "Search unsupported",
+	CodeUnsupportedMediaType:     "Unsupported media type",
+}
+
+// sentinelCodes maps the sentinel errors of the service layer to their codes.
+var sentinelCodes = []struct {
+	err  error
+	code Code
+}{
+	{err: service.ErrArticleNotFound, code: CodeArticleNotFound},
+	{err: service.ErrArticleDuplicated, code: CodeArticleDuplicated},
+	{err: service.ErrArticleModified, code: CodeArticleModified},
+	{err: service.ErrUserNotFound, code: CodeUserNotFound},
+	{err: service.ErrUserEmailExists, code: CodeUserEmailExists},
+	{err: service.ErrWrongPassword, code: CodeWrongCredentials},
+	{err: service.ErrSearchUnsupported, code: CodeSearchUnsupported},
+}
+
+// notFoundCodes are the codes of ErrNotFound per resource.
+var notFoundCodes = map[string]Code{
+	"article": CodeArticleNotFound,
+	"user":    CodeUserNotFound,
+}
+
+// codeOf returns the code of the sentinel error wrapped by err, or fallback if there isn't any.
+func codeOf(err error, fallback Code) Code {
+	for _, s := range sentinelCodes {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+
+	return fallback
+}
@@ -1,20 +1,52 @@
 package response
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/render"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
 )
 
-type Err struct {
-	statusCode int // http response status code
+// ContentType is the media type of error responses.
+const ContentType = "application/problem+json"
 
-	logFunc func() // a function used for logging if needed
+func init() {
+	// Errors are rendered by Respond wherever they come from, so the contract is the same for handlers and middlewares.
+	render.Respond = Respond
+}
 
-	ErrorCode int    `json:"error_code,omitempty"` // application-specific error code
-	ErrorMsg  string `json:"error"`                // user-facing error message
+// Err is a problem details object from RFC 9457, see https://www.rfc-editor.org/rfc/rfc9457.
+type Err struct {
+	logFunc func()         // a function used for logging if needed
+	params  map[string]any // params of the detail, for its translations
+
+	Type     string `json:"type" example:"urn:gab:error:article_duplicated"`   // URI identifying the problem type
+	Title    string `json:"title" example:"Article already exists"`            // short summary of the problem type
+	Status   int    `json:"status" example:"400"`                              // http response status code
+	Detail   string `json:"detail,omitempty" example:"article already exists"` // user-facing explanation of this occurrence
+	Instance string `json:"instance,omitempty" example:"/api/v1/articles"`     // URI of the request that caused the problem
+	Code     Code   `json:"code" example:"article_duplicated"`                 // stable application-specific error code
+
+	Errors []FieldError `json:"errors,omitempty"` // invalid fields, when code is validation_failed
+	Line   int          `json:"line,omitempty"`   // where decoding stopped, when code is malformed_json
+	Column int          `json:"column,omitempty"` // where decoding stopped, when code is malformed_json
+}
+
+func newErr(statusCode int, code Code, detail string) *Err {
+	return &Err{
+		Type:   typePrefix + string(code),
+		Title:  titles[code],
+		Status: statusCode,
+		Detail: detail,
+		Code:   code,
+	}
 }
 
 func (e *Err) Render(w http.ResponseWriter, r *http.Request) error {
@@ -22,72 +54,153 @@ func (e *Err) Render(w http.ResponseWriter, r *http.Request) error {
 		e.logFunc()
 	}
 
-	render.Status(r, e.statusCode)
+	if e.Instance == "" {
+		e.Instance = r.URL.RequestURI()
+	}
+
+	lang := e.localize(r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", lang.String())
+	w.Header().Add("Vary", "Accept-Language")
+
+	render.Status(r, e.Status)
 
 	return nil
 }
 
+// Respond writes problems as application/problem+json and delegates everything else to render.DefaultResponder.
+func Respond(w http.ResponseWriter, r *http.Request, v interface{}) {
+	e, ok := v.(*Err)
+	if !ok {
+		render.DefaultResponder(w, r, v)
+
+		return
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(e.Status)
+	_, _ = w.Write(body)
+}
+
+// ErrBadRequest reports invalid requests.
+// Validation errors and malformed JSON are detailed by ErrValidation and ErrMalformedJSON.
 func ErrBadRequest(err error) *Err {
-	return &Err{
-		statusCode: http.StatusBadRequest,
-		ErrorMsg:   err.Error(),
+	var syntaxErr *request.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return ErrMalformedJSON(syntaxErr)
+	}
+
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		return ErrValidation(validationErrs)
 	}
+
+	return newErr(http.StatusBadRequest, codeOf(err, CodeBadRequest), err.Error())
+}
+
+func ErrValidation(errs validation.Errors) *Err {
+	e := newErr(http.StatusBadRequest, CodeValidationFailed, errs.Error())
+	e.Errors = fieldErrors("", errs)
+
+	return e
+}
+
+func ErrMalformedJSON(err *request.SyntaxError) *Err {
+	e := newErr(http.StatusBadRequest, CodeMalformedJSON, err.Error())
+	e.Line = err.Line
+	e.Column = err.Column
+	e.params = map[string]any{"line": err.Line, "column": err.Column}
+
+	return e
 }
 
 func ErrInternalServerError(err error) *Err {
-	return &Err{
-		statusCode: http.StatusInternalServerError,
-		logFunc: func() {
-			zap.L().Error(err.Error())
-		},
-		ErrorMsg: "something went wrong",
+	e := newErr(http.StatusInternalServerError, CodeInternal, "something went wrong")
+	e.logFunc = func() {
+		zap.L().Error(err.Error())
 	}
+
+	return e
 }
 
 func ErrInvalidInput(fieldName string, fieldValue any) *Err {
 	err := fmt.Errorf("invalid input field %v=%v", fieldName, fieldValue)
 
-	return &Err{
-		statusCode: http.StatusBadRequest,
-		ErrorMsg:   err.Error(),
-	}
+	e := newErr(http.StatusBadRequest, CodeInvalidInput, err.Error())
+	e.params = map[string]any{"field": fieldName, "value": fieldValue}
+
+	return e
 }
 
 func ErrNotFound(resourceName, fieldName string, fieldValue any) *Err {
 	err := fmt.Errorf("%v not found (%v=%v)", resourceName, fieldName, fieldValue)
 
-	return &Err{
-		statusCode: http.StatusNotFound,
-		ErrorMsg:   err.Error(),
+	code, ok := notFoundCodes[resourceName]
+	if !ok {
+		code = CodeNotFound
 	}
+
+	e := newErr(http.StatusNotFound, code, err.Error())
+	e.params = map[string]any{"resource": resourceName, "field": fieldName, "value": fieldValue}
+
+	return e
 }
 
 func ErrWrongCredentials(err error) *Err {
-	return &Err{
-		statusCode: http.StatusUnauthorized,
-		logFunc: func() {
-			zap.L().Debug("wrong credentials: " + err.Error())
-		},
-		ErrorMsg: "wrong credentials",
+	e := newErr(http.StatusUnauthorized, CodeWrongCredentials, "wrong credentials")
+	e.logFunc = func() {
+		zap.L().Debug("wrong credentials: " + err.Error())
 	}
+
+	return e
 }
 
 func ErrJWTUnverified(err error) *Err {
-	return &Err{
-		statusCode: http.StatusUnauthorized,
-		logFunc: func() {
-			zap.L().Debug("unable to verify JWT: " + err.Error())
-		},
-		ErrorMsg: "please log in",
+	e := newErr(http.StatusUnauthorized, CodeUnauthenticated, "please log in")
+	e.logFunc = func() {
+		zap.L().Debug("unable to verify JWT: " + err.Error())
 	}
+
+	return e
 }
 
 func ErrPermissionDenied(err error) *Err {
-	return &Err{
-		statusCode: http.StatusForbidden,
-		logFunc: func() {
-			zap.L().Debug("permission denied: " + err.Error())
-		},
-		ErrorMsg: "permission denied",
+	e := newErr(http.StatusForbidden, CodePermissionDenied, "permission denied")
+	e.logFunc = func() {
+		zap.L().Debug("permission denied: " + err.Error())
 	}
+
+	return e
+}
+
+func ErrTooManyRequests(retryAfter time.Duration) *Err {
+	retryAfter = retryAfter.Round(time.Second)
+	detail := fmt.Sprintf("too many requests, please retry after %v", retryAfter)
+
+	e := newErr(http.StatusTooManyRequests, CodeTooManyRequests, detail)
+	e.params = map[string]any{"retry_after": retryAfter}
+
+	return e
+}
+
+func ErrIdempotencyKeyReused() *Err {
+	return newErr(http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, "idempotency key was already used for a different request")
+}
+
+func ErrIdempotencyKeyInProgress() *Err {
+	return newErr(http.StatusConflict, CodeIdempotencyKeyInProgress, "a request with the same idempotency key is still being processed")
+}
+
+func ErrUnsupportedMediaType(err error) *Err {
+	return newErr(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, err.Error())
+}
+
+func ErrPreconditionFailed(err error) *Err {
+	return newErr(http.StatusPreconditionFailed, codeOf(err, CodePreconditionFailed), err.Error())
 }
@@ -0,0 +1,140 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/render"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
+)
+
+func TestErr_Render(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Err
+		want Err
+	}{
+		{
+			name: "Wrapped sentinel error",
+			err:  ErrBadRequest(fmt.Errorf("s.repo.Create -> %w", service.ErrArticleDuplicated)),
+			want: Err{
+				Type:     "urn:gab:error:article_duplicated",
+				Title:    "Article already exists",
+				Status:   http.StatusBadRequest,
+				Detail:   "s.repo.Create -> article already exists",
+				Instance: "/api/v1/articles?page=1",
+				Code:     CodeArticleDuplicated,
+			},
+		},
+		{
+			name: "Unknown error",
+			err:  ErrBadRequest(errors.New("unexpected EOF")),
+			want: Err{
+				Type:     "urn:gab:error:bad_request",
+				Title:    "Bad request",
+				Status:   http.StatusBadRequest,
+				Detail:   "unexpected EOF",
+				Instance: "/api/v1/articles?page=1",
+				Code:     CodeBadRequest,
+			},
+		},
+		{
+			name: "Not found resource",
+			err:  ErrNotFound("user", "ID", 1),
+			want: Err{
+				Type:     "urn:gab:error:user_not_found",
+				Title:    "User not found",
+				Status:   http.StatusNotFound,
+				Detail:   "user not found (ID=1)",
+				Instance: "/api/v1/articles?page=1",
+				Code:     CodeUserNotFound,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?page=1", nil)
+			rr := httptest.NewRecorder()
+
+			err := render.Render(rr, req, tt.err)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.Status, rr.Code)
+			assert.Equal(t, ContentType, rr.Header().Get("Content-Type"))
+
+			var got Err
+			err = json.Unmarshal(rr.Body.Bytes(), &got)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestErrBadRequest_Validation(t *testing.T) {
+	type state struct {
+		Name       string `json:"name"`
+		Population uint   `json:"population"`
+	}
+	states := make([]state, 11)
+	states[2] = state{Name: "Texas"}
+	states[10] = state{Population: 1}
+
+	err := validation.Errors{
+		"title": validation.Validate("", validation.Required),
+		"states": validation.Validate(states, validation.Each(validation.By(func(value any) error {
+			s := value.(state)
+
+			return validation.ValidateStruct(&s,
+				validation.Field(&s.Name, validation.Required, validation.Length(1, 3)),
+				validation.Field(&s.Population, validation.Required),
+			)
+		}))),
+	}.Filter()
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, CodeValidationFailed, got.Code)
+	assert.Equal(t, http.StatusBadRequest, got.Status)
+	assert.Equal(t, []FieldError{
+		{Field: "states[0].name", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[0].population", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[1].name", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[1].population", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[2].name", Code: "validation_length_out_of_range", Message: "the length must be between 1 and 3", Params: map[string]any{"min": 1, "max": 3}},
+		{Field: "states[2].population", Code: "validation_required", Message: "cannot be blank"},
+	}, got.Errors[:6])
+	assert.Equal(t, FieldError{Field: "states[10].name", Code: "validation_required", Message: "cannot be blank"}, got.Errors[len(got.Errors)-2])
+	assert.Equal(t, FieldError{Field: "title", Code: "validation_required", Message: "cannot be blank"}, got.Errors[len(got.Errors)-1])
+}
+
+func TestErrBadRequest_MalformedJSON(t *testing.T) {
+	var v map[string]any
+	err := request.DecodeJSON(strings.NewReader("{\n  x\n}"), &v)
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, CodeMalformedJSON, got.Code)
+	assert.Equal(t, http.StatusBadRequest, got.Status)
+	assert.Equal(t, 2, got.Line)
+	assert.Equal(t, 3, got.Column)
+	assert.Empty(t, got.Errors)
+}
+
+func TestTitles(t *testing.T) {
+	for _, s := range sentinelCodes {
+		assert.NotEmpty(t, titles[s.code], s.code)
+	}
+	for _, code := range notFoundCodes {
+		assert.NotEmpty(t, titles[code], code)
+	}
+}
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+)
+
+func TestArticleExport(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	articles := []domain.Article{
+		{ID: 1, UserID: 123, Title: "title 1", Content: "content, 1", Version: 1, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, UserID: 123, Title: "title 2", Content: "content \"2\"", Version: 2, CreatedAt: created, UpdatedAt: created},
+	}
+	article1 := `{"id":1,"user_id":123,"title":"title 1","content":"content, 1","version":1,"created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z"}`
+	article2 := `{"id":2,"user_id":123,"title":"title 2","content":"content \"2\"","version":2,"created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z"}`
+
+	tests := []struct {
+		name            string
+		format          string
+		articles        []domain.Article
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "CSV",
+			format:          request.ExportCSV,
+			articles:        articles,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: "id,user_id,title,content,version,created_at,updated_at\n" +
+				"1,123,title 1,\"content, 1\",1,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z\n" +
+				"2,123,title 2,\"content \"\"2\"\"\",2,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z\n",
+		},
+		{
+			name:            "NDJSON",
+			format:          request.ExportNDJSON,
+			articles:        articles,
+			wantContentType: request.ContentTypeNDJSON,
+			wantBody:        article1 + "\n" + article2 + "\n",
+		},
+		{
+			name:            "JSON",
+			format:          request.ExportJSON,
+			articles:        articles,
+			wantContentType: "application/json",
+			wantBody:        "[" + article1 + "," + article2 + "]\n",
+		},
+		{
+			name:            "Empty JSON",
+			format:          request.ExportJSON,
+			wantContentType: "application/json",
+			wantBody:        "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			export := NewArticleExport(w, tt.format)
+			assert.False(t, export.Started())
+
+			for _, article := range tt.articles {
+				require.NoError(t, export.Write(article))
+			}
+			require.NoError(t, export.Close())
+
+			assert.True(t, export.Started())
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="articles.`+tt.format+`"`, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package response
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// codeInvalid is used for validation errors that don't carry a code of their own.
+const codeInvalid = "validation_invalid"
+
+// FieldError explains why a single field of the request is invalid.
+type FieldError struct {
+	Field   string         `json:"field" example:"states[2].population"`                   // path of the field, made of JSON names
+	Code    string         `json:"code" example:"validation_length_out_of_range"`          // stable code of the failed rule
+	Message string         `json:"message" example:"the length must be between 1 and 128"` // user-facing message
+	Params  map[string]any `json:"params,omitempty"`                                       // parameters of the failed rule, e.g. min and max
+}
+
+// fieldErrors flattens nested validation errors, e.g. of slices of structs, into a list sorted by field.
+func fieldErrors(prefix string, errs validation.Errors) []FieldError {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		// Keep slice indexes in numeric order, so states[2] comes before states[10].
+		x, errX := strconv.Atoi(keys[i])
+		y, errY := strconv.Atoi(keys[j])
+		if errX == nil && errY == nil {
+			return x < y
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	result := make([]FieldError, 0, len(errs))
+	for _, key := range keys {
+		field := fieldPath(prefix, key)
+
+		var nested validation.Errors
+		if errors.As(errs[key], &nested) {
+			result = append(result, fieldErrors(field, nested)...)
+
+			continue
+		}
+
+		var ruleErr validation.Error
+		if errors.As(errs[key], &ruleErr) {
+			result = append(result, FieldError{
+				Field:   field,
+				Code:    ruleErr.Code(),
+				Message: ruleErr.Error(),
+				Params:  ruleErr.Params(),
+			})
+
+			continue
+		}
+
+		result = append(result, FieldError{
+			Field:   field,
+			Code:    codeInvalid,
+			Message: errs[key].Error(),
+		})
+	}
+
+	return result
+}
+
+// fieldPath appends key to prefix, indexes of slices are put in brackets like states[2].
+func fieldPath(prefix, key string) string {
+	if _, err := strconv.Atoi(key); err == nil {
+		return prefix + "[" + key + "]"
+	}
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
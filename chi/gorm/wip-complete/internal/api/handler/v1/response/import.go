@@ -0,0 +1,130 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
+)
+
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+// ImportRow is the outcome of a row of a bulk import.
+type ImportRow struct {
+	Row     int             `json:"row" example:"1"`          // position in the import, starting from 1
+	Status  string          `json:"status" example:"created"` // created, duplicate or invalid
+	Article *domain.Article `json:"article,omitempty"`        // when created
+	Error   *Err            `json:"error,omitempty"`          // why the row isn't created
+}
+
+// ImportSummary ends the report of a bulk import.
+type ImportSummary struct {
+	Rows      int  `json:"rows"`
+	Created   int  `json:"created"`
+	Duplicate int  `json:"duplicate"`
+	Invalid   int  `json:"invalid"`
+	Committed bool `json:"committed"` // whether the created articles are kept, false when an atomic import is rolled back
+
+	Error *Err `json:"error,omitempty"` // what stopped the import before its end
+}
+
+// ImportReport streams the report of a bulk import as NDJSON: an ImportRow per row as soon as it's processed,
+// then {"summary": ImportSummary}. The status is 200 once the report has started, whatever happens next.
+type ImportReport struct {
+	w              http.ResponseWriter
+	instance       string
+	acceptLanguage string
+	summary        ImportSummary
+}
+
+func NewImportReport(w http.ResponseWriter, r *http.Request) *ImportReport {
+	w.Header().Set("Content-Type", request.ContentTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+
+	return &ImportReport{
+		w:              w,
+		instance:       r.URL.RequestURI(),
+		acceptLanguage: r.Header.Get("Accept-Language"),
+	}
+}
+
+// Rows writes the outcome of rows and flushes them to the client.
+func (rep *ImportReport) Rows(rows []domain.ArticleImportRow) error {
+	for _, row := range rows {
+		line := ImportRow{Row: row.Row}
+		switch {
+		case row.Err == nil:
+			line.Status = ImportCreated
+			line.Article = &row.Article
+			rep.summary.Created++
+		case errors.Is(row.Err, service.ErrArticleDuplicated):
+			line.Status = ImportDuplicate
+			line.Error = rep.problem(ErrBadRequest(row.Err))
+			rep.summary.Duplicate++
+		case errors.Is(row.Err, service.ErrPermissionDenied):
+			line.Status = ImportInvalid
+			line.Error = rep.problem(ErrPermissionDenied(row.Err))
+			rep.summary.Invalid++
+		default:
+			line.Status = ImportInvalid
+			line.Error = rep.problem(ErrBadRequest(row.Err))
+			rep.summary.Invalid++
+		}
+		rep.summary.Rows++
+
+		if err := rep.write(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Finish writes the summary of an import given the error it ended with, if any, and whether it was atomic.
+// Batches of non-atomic imports are committed as they go, so they're kept even if the import is stopped.
+func (rep *ImportReport) Finish(err error, atomic bool) {
+	rep.summary.Committed = err == nil || !atomic
+	if err != nil && !errors.Is(err, service.ErrImportRolledBack) {
+		rep.summary.Error = rep.problem(ErrInternalServerError(err))
+	}
+
+	_ = rep.write(struct {
+		Summary ImportSummary `json:"summary"`
+	}{rep.summary})
+}
+
+// problem completes and localizes an error like Err.Render does for error responses.
+func (rep *ImportReport) problem(e *Err) *Err {
+	if e.logFunc != nil {
+		e.logFunc()
+	}
+
+	e.Instance = rep.instance
+	e.localize(rep.acceptLanguage)
+
+	return e
+}
+
+func (rep *ImportReport) write(line any) error {
+	body, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("json.Marshal -> %w", err)
+	}
+
+	if _, err = rep.w.Write(append(body, '\n')); err != nil {
+		return fmt.Errorf("rep.w.Write -> %w", err)
+	}
+	if flusher, ok := rep.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
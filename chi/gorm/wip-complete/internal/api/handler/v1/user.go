@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/request"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/jwthelper"
@@ -35,6 +36,7 @@ func NewUserHandler(svc UserService) *UserHandler {
 // @Tags         users
 // @Produce      json
 // @Param        userID   path       int  true "user ID"
+// @Param        fields   query      string  false  "comma separated fields to return, e.g. id,email. All fields are returned if empty."
 // @Success      200      {object}   domain.User
 // @Failure      401      {object}   response.Err
 // @Failure      500      {object}   response.Err
@@ -67,6 +69,13 @@ func (h *UserHandler) HandleGetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := request.UserFields.Parse(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, response.ErrBadRequest(err))
+
+		return
+	}
+
 	user, err := h.svc.GetUser(r.Context(), uint(userID))
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
@@ -81,6 +90,14 @@ func (h *UserHandler) HandleGetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	body, err := fields.Select(user)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleGetUser -> fields.Select -> %w", err)
+		_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+		return
+	}
+
 	render.Status(r, http.StatusOK)
-	render.JSON(w, r, user)
+	render.JSON(w, r, body)
 }
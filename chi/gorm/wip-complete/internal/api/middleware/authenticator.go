@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -37,6 +38,26 @@ func (a *Authenticator) VerifyJWT(next http.Handler) http.Handler {
 	})
 }
 
+// RequireAdmin only lets admin users through. It must be mounted after VerifyJWT.
+func (a *Authenticator) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := jwthelper.RetrieveClaimsFromContext(r.Context())
+		if err != nil {
+			_ = render.Render(w, r, response.ErrJWTUnverified(err))
+
+			return
+		}
+
+		if !claims.IsAdmin {
+			_ = render.Render(w, r, response.ErrPermissionDenied(fmt.Errorf("user %v is not an admin", claims.UserID)))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (a *Authenticator) extractClaims(r *http.Request) (*jwthelper.Claims, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -3,4 +3,7 @@ package middleware
 const (
 	PageQueryKey    string = "page"
 	PerPageQueryKey string = "per_page"
+	CursorQueryKey  string = "cursor"
+	LimitQueryKey   string = "limit"
+	CountQueryKey   string = "count"
 )
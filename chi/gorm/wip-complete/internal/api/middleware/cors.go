@@ -7,14 +7,30 @@ import (
 	"time"
 
 	"github.com/go-chi/cors"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/idempotency"
 )
 
+// exposedHeaders are the response headers browsers allow scripts to read.
+var exposedHeaders = []string{
+	"Content-Length",
+	"ETag",
+	"Link",
+	"X-Total-Count",
+	"RateLimit-Policy",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"Retry-After",
+	idempotency.ReplayedHeader,
+}
+
 func ConfigCORS(environment string, allowedDomains []string) func(next http.Handler) http.Handler {
 	return cors.Handler(cors.Options{
 		AllowOriginFunc:  createAllowedOriginFunc(allowedDomains),
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Content-Length"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", idempotency.Header, "If-Match", "If-None-Match"},
+		ExposedHeaders:   exposedHeaders,
 		AllowCredentials: true,
 		MaxAge:           int((12 * time.Hour).Seconds()), // Maximum value not ignored by any of major browsers
 		Debug:            strings.EqualFold(environment, "development"),
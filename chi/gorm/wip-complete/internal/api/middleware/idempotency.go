@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/idempotency"
+)
+
+const maxIdempotentBodyBytes = 1 << 20
+
+type Idempotency struct {
+	store idempotency.Store
+	ttl   time.Duration
+}
+
+func NewIdempotency(store idempotency.Store, ttl time.Duration) *Idempotency {
+	if ttl <= 0 {
+		ttl = idempotency.DefaultTTL
+	}
+
+	return &Idempotency{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Handle replays the stored response when a request is retried with the same Idempotency-Key.
+// Keys are scoped per client identified by keyFunc.
+// Requests without the header are processed as usual.
+func (i *Idempotency) Handle(keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if i.store == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(idempotency.Header)
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+			if len(idempotencyKey) > idempotency.MaxKeyLength {
+				_ = render.Render(w, r, response.ErrInvalidInput(idempotency.Header, idempotencyKey))
+
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+			if err != nil {
+				_ = render.Render(w, r, response.ErrBadRequest(err))
+
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key := keyFunc(r) + ":" + idempotencyKey
+			requestHash := idempotency.HashRequest(r.Method, r.URL.Path, body)
+			reserved, record, err := i.store.Reserve(r.Context(), key, requestHash, i.ttl)
+			if err != nil {
+				err = fmt.Errorf("middleware.Idempotency -> i.store.Reserve -> %w", err)
+				_ = render.Render(w, r, response.ErrInternalServerError(err))
+
+				return
+			}
+
+			if !reserved {
+				i.replay(w, r, record, requestHash)
+
+				return
+			}
+
+			// Free the key when the handler panics, or retries would be refused until it expires.
+			defer func() {
+				if p := recover(); p != nil {
+					i.release(context.WithoutCancel(r.Context()), key)
+
+					panic(p)
+				}
+			}()
+
+			var buf bytes.Buffer
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+			next.ServeHTTP(ww, r)
+
+			i.save(r, key, requestHash, ww.Status(), w.Header(), buf.Bytes())
+		})
+	}
+}
+
+func (i *Idempotency) replay(w http.ResponseWriter, r *http.Request, record idempotency.Record, requestHash string) {
+	if record.RequestHash != requestHash {
+		_ = render.Render(w, r, response.ErrIdempotencyKeyReused())
+
+		return
+	}
+
+	if !record.Completed {
+		_ = render.Render(w, r, response.ErrIdempotencyKeyInProgress())
+
+		return
+	}
+
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(idempotency.ReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+func (i *Idempotency) save(r *http.Request, key, requestHash string, status int, header http.Header, body []byte) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	// Clients that time out disconnect before the response is saved, but they are the ones retrying.
+	ctx := context.WithoutCancel(r.Context())
+
+	// Server errors are not final, let the client retry them.
+	if status >= http.StatusInternalServerError {
+		i.release(ctx, key)
+
+		return
+	}
+
+	err := i.store.Complete(ctx, key, idempotency.Record{
+		RequestHash: requestHash,
+		StatusCode:  status,
+		Header:      idempotency.ReplayableHeader(header),
+		Body:        body,
+	}, i.ttl)
+	if err != nil {
+		zap.L().Error("saving idempotent response failed", zap.Error(err))
+	}
+}
+
+func (i *Idempotency) release(ctx context.Context, key string) {
+	if err := i.store.Release(ctx, key); err != nil {
+		zap.L().Error("releasing idempotency key failed", zap.Error(err))
+	}
+}
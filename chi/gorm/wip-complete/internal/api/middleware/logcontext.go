@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/logger"
+)
+
+const traceparentHeader = "traceparent"
+
+// LogContext stores the request ID and the trace ID into the request context,
+// so they can be attached to logs emitted further down, e.g. SQL logs from GORM.
+// It must be mounted after chimiddleware.RequestID.
+func LogContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if requestID := chimiddleware.GetReqID(ctx); requestID != "" {
+			ctx = logger.WithRequestID(ctx, requestID)
+		}
+		if traceID := parseTraceID(r.Header.Get(traceparentHeader)); traceID != "" {
+			ctx = logger.WithTraceID(ctx, traceID)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// parseTraceID extracts the trace ID from a W3C traceparent header,
+// which looks like "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func parseTraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+
+	return parts[1]
+}
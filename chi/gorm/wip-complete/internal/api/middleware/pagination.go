@@ -9,6 +9,8 @@ import (
 	"github.com/go-chi/render"
 
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/cursor"
 )
 
 const (
@@ -17,29 +19,90 @@ const (
 	maxPerPage     = 100
 )
 
-func Pagination(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		pageNumber := r.URL.Query().Get(PageQueryKey)
-		parsedPageNumber, err := parsePageNumber(pageNumber)
-		if err != nil {
-			render.Render(w, r, response.ErrInvalidInput(PageQueryKey, pageNumber))
-
-			return
-		}
-
-		perPage := r.URL.Query().Get(PerPageQueryKey)
-		parsedPerPage, err := parsePerPage(perPage)
-		if err != nil {
-			render.Render(w, r, response.ErrInvalidInput(PerPageQueryKey, perPage))
-
-			return
-		}
-
-		ctx := context.WithValue(r.Context(), PageQueryKey, parsedPageNumber)
-		ctx = context.WithValue(ctx, PerPageQueryKey, parsedPerPage)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+var errPageWithCursor = errors.New("page cannot be used with cursor or limit")
+
+// Pagination parses the pagination queries into the request context.
+//
+// Lists are paginated with offsets by default, PageQueryKey holds the page number and PerPageQueryKey the page size.
+// Sending the cursor or limit query switches to pagination with cursors decoded by codec instead:
+// CursorQueryKey holds the *domain.Cursor to start from, nil for the first page, and PerPageQueryKey holds the limit.
+// A nil codec disables cursors, for lists only paginated with offsets.
+// CountQueryKey tells whether the total count is requested in both modes.
+func Pagination(codec *cursor.Codec) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+
+			count := query.Get(CountQueryKey)
+			parsedCount, err := parseCount(count)
+			if err != nil {
+				render.Render(w, r, response.ErrInvalidInput(CountQueryKey, count))
+
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), CountQueryKey, parsedCount)
+
+			if !query.Has(CursorQueryKey) && !query.Has(LimitQueryKey) {
+				pageNumber := query.Get(PageQueryKey)
+				parsedPageNumber, err := parsePageNumber(pageNumber)
+				if err != nil {
+					render.Render(w, r, response.ErrInvalidInput(PageQueryKey, pageNumber))
+
+					return
+				}
+
+				perPage := query.Get(PerPageQueryKey)
+				parsedPerPage, err := parsePerPage(perPage)
+				if err != nil {
+					render.Render(w, r, response.ErrInvalidInput(PerPageQueryKey, perPage))
+
+					return
+				}
+
+				ctx = context.WithValue(ctx, PageQueryKey, parsedPageNumber)
+				ctx = context.WithValue(ctx, PerPageQueryKey, parsedPerPage)
+				next.ServeHTTP(w, r.WithContext(ctx))
+
+				return
+			}
+
+			token, limit := query.Get(CursorQueryKey), query.Get(LimitQueryKey)
+			if codec == nil {
+				if query.Has(CursorQueryKey) {
+					render.Render(w, r, response.ErrInvalidInput(CursorQueryKey, token))
+				} else {
+					render.Render(w, r, response.ErrInvalidInput(LimitQueryKey, limit))
+				}
+
+				return
+			}
+
+			if query.Has(PageQueryKey) {
+				render.Render(w, r, response.ErrBadRequest(errPageWithCursor))
+
+				return
+			}
+
+			parsedLimit, err := parsePerPage(limit)
+			if err != nil {
+				render.Render(w, r, response.ErrInvalidInput(LimitQueryKey, limit))
+
+				return
+			}
+
+			parsedCursor, err := parseCursor(codec, token)
+			if err != nil {
+				render.Render(w, r, response.ErrInvalidInput(CursorQueryKey, token))
+
+				return
+			}
+
+			ctx = context.WithValue(ctx, PerPageQueryKey, parsedLimit)
+			ctx = context.WithValue(ctx, CursorQueryKey, parsedCursor)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func parsePageNumber(number string) (uint, error) {
@@ -66,8 +129,34 @@ func parsePerPage(perPage string) (uint, error) {
 	}
 
 	if parsed > maxPerPage {
-		return defaultPerPage, nil
+		return maxPerPage, nil
 	}
 
 	return uint(parsed), nil
 }
+
+func parseCount(count string) (bool, error) {
+	if count == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(count)
+	if err != nil {
+		return false, errors.New("parse count query failed")
+	}
+
+	return parsed, nil
+}
+
+func parseCursor(codec *cursor.Codec, token string) (*domain.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var parsed domain.Cursor
+	if err := codec.Decode(token, &parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
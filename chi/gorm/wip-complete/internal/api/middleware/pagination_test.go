@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/cursor"
+)
+
+func TestPagination(t *testing.T) {
+	codec := cursor.NewCodec("test_key")
+	position := &domain.Cursor{CreatedAt: time.Date(2024, 1, 31, 15, 26, 31, 0, time.UTC), ID: 999}
+	token, err := codec.Encode(position)
+	require.NoError(t, err)
+	otherToken, err := cursor.NewCodec("other_key").Encode(position)
+	require.NoError(t, err)
+
+	type want struct {
+		respCode int
+		page     any
+		perPage  any
+		cursor   any
+		count    any
+	}
+	tests := []struct {
+		name  string
+		codec *cursor.Codec
+		query string
+		want  want
+	}{
+		{
+			name:  "Offset - first page by default",
+			codec: codec,
+			query: "",
+			want:  want{respCode: http.StatusOK, page: uint(defaultPage), perPage: uint(defaultPerPage), count: false},
+		},
+		{
+			name:  "Offset - per_page is capped",
+			codec: codec,
+			query: "?per_page=1000",
+			want:  want{respCode: http.StatusOK, page: uint(defaultPage), perPage: uint(maxPerPage), count: false},
+		},
+		{
+			name:  "Cursor - first page with limit",
+			codec: codec,
+			query: "?limit=5",
+			want:  want{respCode: http.StatusOK, perPage: uint(5), cursor: (*domain.Cursor)(nil), count: false},
+		},
+		{
+			name:  "Cursor - first page with empty cursor",
+			codec: codec,
+			query: "?cursor=&per_page=5",
+			want:  want{respCode: http.StatusOK, perPage: uint(defaultPerPage), cursor: (*domain.Cursor)(nil), count: false},
+		},
+		{
+			name:  "Cursor - with cursor and count",
+			codec: codec,
+			query: "?cursor=" + token + "&limit=5&count=true",
+			want:  want{respCode: http.StatusOK, perPage: uint(5), cursor: position, count: true},
+		},
+		{
+			name:  "Cursor - limit is capped",
+			codec: codec,
+			query: "?limit=1000",
+			want:  want{respCode: http.StatusOK, perPage: uint(maxPerPage), cursor: (*domain.Cursor)(nil), count: false},
+		},
+		{
+			name:  "Offset - with page",
+			codec: codec,
+			query: "?page=2&per_page=1",
+			want:  want{respCode: http.StatusOK, page: uint(2), perPage: uint(1), count: false},
+		},
+		{
+			name:  "Offset - empty page",
+			codec: codec,
+			query: "?page=",
+			want:  want{respCode: http.StatusOK, page: uint(defaultPage), perPage: uint(defaultPerPage), count: false},
+		},
+		{
+			name:  "Offset - cursors disabled",
+			query: "?per_page=5",
+			want:  want{respCode: http.StatusOK, page: uint(defaultPage), perPage: uint(5), count: false},
+		},
+		{
+			name:  "400 - Cursors disabled",
+			query: "?cursor=" + token,
+			want:  want{respCode: http.StatusBadRequest},
+		},
+		{
+			name:  "400 - Limit with cursors disabled",
+			query: "?limit=5",
+			want:  want{respCode: http.StatusBadRequest},
+		},
+		{
+			name:  "400 - Cursor signed with another key",
+			codec: codec,
+			query: "?cursor=" + otherToken,
+			want:  want{respCode: http.StatusBadRequest},
+		},
+		{
+			name:  "400 - Page with cursor",
+			codec: codec,
+			query: "?page=1&cursor=" + token,
+			want:  want{respCode: http.StatusBadRequest},
+		},
+		{
+			name:  "400 - Page with limit",
+			codec: codec,
+			query: "?page=1&limit=5",
+			want:  want{respCode: http.StatusBadRequest},
+		},
+		{
+			name:  "400 - Invalid limit",
+			codec: codec,
+			query: "?limit=abc",
+			want:  want{respCode: http.StatusBadRequest},
+		},
+		{
+			name:  "400 - Invalid count",
+			codec: codec,
+			query: "?count=abc",
+			want:  want{respCode: http.StatusBadRequest},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got want
+			handler := Pagination(tt.codec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.page = r.Context().Value(PageQueryKey)
+				got.perPage = r.Context().Value(PerPageQueryKey)
+				got.cursor = r.Context().Value(CursorQueryKey)
+				got.count = r.Context().Value(CountQueryKey)
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/articles"+tt.query, nil))
+
+			got.respCode = rr.Code
+			if c, ok := got.cursor.(*domain.Cursor); ok && c != nil {
+				assert.True(t, position.CreatedAt.Equal(c.CreatedAt))
+				got.cursor = &domain.Cursor{CreatedAt: position.CreatedAt, ID: c.ID, Backward: c.Backward}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
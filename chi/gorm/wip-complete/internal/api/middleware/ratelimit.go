@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/jwthelper"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/ratelimit"
+)
+
+// KeyFunc identifies who is making the request.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP identifies clients by their IP address.
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// KeyByUser identifies clients by the user ID in the JWT claims, or by IP if not logged in.
+// It must be mounted after Authenticator.VerifyJWT.
+func KeyByUser(r *http.Request) string {
+	claims, err := jwthelper.RetrieveClaimsFromContext(r.Context())
+	if err != nil {
+		return KeyByIP(r)
+	}
+
+	return fmt.Sprintf("user:%d", claims.UserID)
+}
+
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+}
+
+func NewRateLimiter(limiter ratelimit.Limiter) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+	}
+}
+
+// Limit rate limits requests within the route group, per client identified by keyFunc.
+// Responses carry the RateLimit-* headers from https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+// and rejected requests get a 429 with Retry-After.
+func (rl *RateLimiter) Limit(group string, limit ratelimit.Limit, keyFunc KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if rl.limiter == nil || limit.IsZero() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := rl.limiter.Allow(r.Context(), "ratelimit:"+group+":"+keyFunc(r), limit)
+			if err != nil {
+				// Fail open, an outage of the rate limit storage shouldn't take the API down.
+				zap.L().Error("rate limiting failed", zap.String("group", group), zap.Error(err))
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Rate, int(limit.Period.Seconds())))
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(result.ResetAfter))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", seconds(result.RetryAfter))
+				_ = render.Render(w, r, response.ErrTooManyRequests(result.RetryAfter))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds rounds d up, so clients never retry too early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -2,12 +2,15 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/redis/go-redis/v9"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -16,6 +19,9 @@ import (
 	v1 "github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/middleware"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/cursor"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/idempotency"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/ratelimit"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
@@ -24,13 +30,22 @@ import (
 type Server struct {
 	Config *config.AppConfig
 	Router *chi.Mux
+
+	rateLimiter *middleware.RateLimiter
+	idempotency *middleware.Idempotency
+	cursors     *cursor.Codec
+	searchIndex service.SearchIndex
 }
 
-func NewServer(conf *config.AppConfig, db *gorm.DB) *Server {
+// NewServer creates the API server. rdb is optional, it's only needed by Redis backed features.
+func NewServer(conf *config.AppConfig, db *gorm.DB, rdb *redis.Client) *Server {
 	s := &Server{
 		Config: conf,
 		Router: chi.NewRouter(),
 	}
+	s.rateLimiter = middleware.NewRateLimiter(s.initRateLimiter(rdb))
+	s.idempotency = s.initIdempotency(db, rdb)
+	s.cursors = s.initCursorCodec()
 
 	s.MountMiddlewares()
 
@@ -42,8 +57,18 @@ func NewServer(conf *config.AppConfig, db *gorm.DB) *Server {
 	return s
 }
 
+// Close releases the resources held by the server, like the bleve search index.
+func (s *Server) Close() error {
+	if closer, ok := s.searchIndex.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
 func (s *Server) MountMiddlewares() {
 	s.Router.Use(chimiddleware.RequestID)
+	s.Router.Use(middleware.LogContext)
 	s.Router.Use(chimiddleware.Logger)
 	s.Router.Use(chimiddleware.Recoverer)
 	s.Router.Use(chimiddleware.CleanPath)
@@ -59,22 +84,47 @@ func (s *Server) MountHandlers(authHandler *v1.AuthHandler, userHandler *v1.User
 	apiV1Router := chi.NewRouter()
 	apiV1Router.Route("/", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
-			r.Post("/auth/signup", authHandler.HandleSignup)
+			r.Use(s.rateLimit("auth", s.rateLimitConfig().Auth, middleware.KeyByIP))
+
+			r.With(s.idempotency.Handle(middleware.KeyByIP)).Post("/auth/signup", authHandler.HandleSignup)
 			r.Post("/auth/login", authHandler.HandleLogin)
 		})
 
 		r.Group(func(r chi.Router) {
 			authenticator := middleware.NewAuthenticator(s.Config.API.JWTSigningKey)
 			r.Use(authenticator.VerifyJWT)
+			r.Use(s.rateLimit("users", s.rateLimitConfig().Users, middleware.KeyByUser))
 
 			r.Get("/users/{userID}", userHandler.HandleGetUser)
 		})
 
 		r.Group(func(r chi.Router) {
-			r.With(middleware.Pagination).Get("/articles", articleHandler.HandleListArticles)
-			r.Post("/articles", articleHandler.HandleCreateArticle)
+			authenticator := middleware.NewAuthenticator(s.Config.API.JWTSigningKey)
+			r.Use(authenticator.VerifyJWT)
+			r.Use(authenticator.RequireAdmin)
+
+			r.Get("/admin/log/level", v1.HandleGetLogLevel)
+			r.Put("/admin/log/level", v1.HandleSetLogLevel)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(s.rateLimit("articles", s.rateLimitConfig().Articles, middleware.KeyByIP))
+
+			r.With(middleware.Pagination(s.cursors)).Get("/articles", articleHandler.HandleListArticles)
 			r.Get("/articles/{articleID}", articleHandler.HandleGetArticle)
-			r.Get("/articles/search", articleHandler.HandleSearchArticles)
+			r.With(middleware.Pagination(nil)).Get("/articles/search", articleHandler.HandleSearchArticles)
+			r.Get("/articles/export", articleHandler.HandleExportArticles)
+		})
+
+		r.Group(func(r chi.Router) {
+			authenticator := middleware.NewAuthenticator(s.Config.API.JWTSigningKey)
+			r.Use(authenticator.VerifyJWT)
+			r.Use(s.rateLimit("author_articles", s.rateLimitConfig().AuthorArticles, middleware.KeyByUser))
+
+			r.With(s.idempotency.Handle(middleware.KeyByUser)).Post("/articles", articleHandler.HandleCreateArticle)
+			r.Post("/articles/bulk", articleHandler.HandleImportArticles)
+			r.Put("/articles/{articleID}", articleHandler.HandleUpdateArticle)
+			r.Delete("/articles/{articleID}", articleHandler.HandleDeleteArticle)
 		})
 	})
 
@@ -84,18 +134,19 @@ func (s *Server) MountHandlers(authHandler *v1.AuthHandler, userHandler *v1.User
 	docs.SwaggerInfo.Host = s.Config.API.BaseURL
 	docs.SwaggerInfo.BasePath = basePath
 	docs.SwaggerInfo.Title = "API for chi/crud-gorm"
-	docs.SwaggerInfo.Description = "This is an example of Go API with Chi router."
+	docs.SwaggerInfo.Description = "This is an example of Go API with Chi router.\n\n" +
+		"Errors are returned as application/problem+json (RFC 9457), their `code` is stable and listed in response.Code.\n\n" +
+		"Titles and messages are translated into the language negotiated from Accept-Language (en, de, es)."
 	docs.SwaggerInfo.Version = "1.0"
 	s.Router.Get("/swagger/*", httpSwagger.WrapHandler)
-
-	s.printAllRoutes()
 }
 
 func (s *Server) initArticleHandler(db *gorm.DB) *v1.ArticleHandler {
 	articleDAO := dao.NewArticleDAO(db)
 	articleRepo := repository.NewArticleRepository(articleDAO)
-	articleSvc := service.NewArticleService(articleRepo)
-	articleHandler := v1.NewArticleHandler(articleSvc)
+	s.searchIndex = s.initSearchIndex(articleRepo)
+	articleSvc := service.NewArticleService(articleRepo, s.searchIndex, s.initTxManager(db))
+	articleHandler := v1.NewArticleHandler(articleSvc, s.cursors, s.Config.Import)
 
 	return articleHandler
 }
@@ -118,18 +169,145 @@ func (s *Server) initUserHandler(db *gorm.DB) *v1.UserHandler {
 	return handler
 }
 
-func (s *Server) printAllRoutes() {
-	zap.L().Info("printing all routes...")
+func (s *Server) initRateLimiter(rdb *redis.Client) ratelimit.Limiter {
+	conf := s.rateLimitConfig()
+
+	switch conf.Backend {
+	case config.RateLimitBackendMemory:
+		return ratelimit.NewMemoryLimiter()
+	case config.RateLimitBackendRedis:
+		if rdb == nil {
+			zap.L().Error("rate limiting is disabled because Redis isn't connected")
+
+			return nil
+		}
+
+		return ratelimit.NewRedisLimiter(rdb)
+	default:
+		return nil
+	}
+}
+
+func (s *Server) initIdempotency(db *gorm.DB, rdb *redis.Client) *middleware.Idempotency {
+	conf := s.Config.Idempotency
+	if conf == nil {
+		return middleware.NewIdempotency(nil, 0)
+	}
+
+	var store idempotency.Store
+	switch conf.Backend {
+	case config.IdempotencyBackendPostgres:
+		store = dao.NewIdempotencyDAO(db)
+	case config.IdempotencyBackendRedis:
+		if rdb == nil {
+			zap.L().Error("Idempotency-Key support is disabled because Redis isn't connected")
+
+			break
+		}
+
+		store = idempotency.NewRedisStore(rdb)
+	}
+
+	return middleware.NewIdempotency(store, conf.TTL)
+}
+
+func (s *Server) initSearchIndex(articleRepo *repository.ArticleRepository) service.SearchIndex {
+	conf := s.Config.Search
+	if conf != nil && conf.Backend == config.SearchBackendBleve {
+		index, err := repository.OpenBleveSearchIndex(conf.BlevePath)
+		if err == nil {
+			return index
+		}
+
+		zap.L().Error("falling back to postgres search because the bleve index can't be opened", zap.Error(err))
+	}
+
+	return repository.NewPostgresSearchIndex(articleRepo)
+}
+
+func (s *Server) initTxManager(db *gorm.DB) *dao.TxManager {
+	conf := s.Config.SQL()
+
+	return dao.NewTxManager(db, dao.TxConfig{
+		Isolation:    conf.TxIsolationLevel(),
+		MaxRetries:   conf.TxMaxRetries,
+		RetryBackoff: conf.TxRetryBackoff,
+	})
+}
+
+// initCursorCodec signs cursors with CursorSigningKey, or with a key derived from JWTSigningKey when it's empty,
+// so that cursors and JWTs are never signed with the same key.
+func (s *Server) initCursorCodec() *cursor.Codec {
+	key := s.Config.API.CursorSigningKey
+	if key == "" {
+		key = cursor.DeriveKey(s.Config.API.JWTSigningKey)
+	}
+
+	return cursor.NewCodec(key)
+}
+
+func (s *Server) rateLimitConfig() *config.RateLimitConfig {
+	if s.Config.RateLimit == nil {
+		return &config.RateLimitConfig{}
+	}
+
+	return s.Config.RateLimit
+}
+
+func (s *Server) rateLimit(group, rawLimit string, keyFunc middleware.KeyFunc) func(http.Handler) http.Handler {
+	limit, err := ratelimit.ParseLimit(rawLimit)
+	if err != nil {
+		zap.L().Error("rate limiting is disabled because of invalid limit", zap.String("group", group), zap.Error(err))
+	}
+
+	return s.rateLimiter.Limit(group, limit, keyFunc)
+}
+
+// Route is a method and path served by the router.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Routes lists all the routes mounted on the router, sorted by path and method.
+func (s *Server) Routes() ([]Route, error) {
+	var routes []Route
 
 	walkFunc := func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
 		route = strings.Replace(route, "/*/", "/", -1)
 
-		zap.L().Info(fmt.Sprintf("%v\t%v", method, route))
+		routes = append(routes, Route{Method: method, Path: route})
 
 		return nil
 	}
 
 	if err := chi.Walk(s.Router, walkFunc); err != nil {
+		return nil, fmt.Errorf("chi.Walk -> %w", err)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes, nil
+}
+
+// PrintAllRoutes logs all the routes, see Routes to get them instead.
+func (s *Server) PrintAllRoutes() {
+	zap.L().Info("printing all routes...")
+
+	routes, err := s.Routes()
+	if err != nil {
 		zap.L().Error("printing all routes failed", zap.Error(err))
+
+		return
+	}
+
+	for _, route := range routes {
+		zap.L().Info(fmt.Sprintf("%v\t%v", route.Method, route.Path))
 	}
 }
@@ -1,24 +1,71 @@
 package config
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
-	validation "github.com/go-ozzo/ozzo-validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/ratelimit"
 )
 
 type AppConfig struct {
-	API      *APIConfig      `mapstructure:"API"`
-	Postgres *PostgresConfig `mapstructure:"POSTGRES"`
+	API       *APIConfig       `mapstructure:"API"`
+	Log       *LogConfig       `mapstructure:"LOG"`
+	Database  *DatabaseConfig  `mapstructure:"DATABASE"`
+	Postgres  *PostgresConfig  `mapstructure:"POSTGRES"`
+	MySQL     *MySQLConfig     `mapstructure:"MYSQL"`
+	SQLite    *SQLiteConfig    `mapstructure:"SQLITE"`
+	RateLimit *RateLimitConfig `mapstructure:"RATE_LIMIT"`
+	Redis     *RedisConfig     `mapstructure:"REDIS"`
+	Search    *SearchConfig    `mapstructure:"SEARCH"`
+	Import    *ImportConfig    `mapstructure:"IMPORT"`
+
+	Idempotency *IdempotencyConfig `mapstructure:"IDEMPOTENCY"`
 }
 
 func (c *AppConfig) validate() error {
+	driver := c.DatabaseDriver()
+
 	return validation.ValidateStruct(
 		c,
 		validation.Field(&c.API, validation.Required),
-		validation.Field(&c.Postgres, validation.Required),
+		validation.Field(&c.Postgres, validation.When(driver == DatabaseDriverPostgres, validation.Required)),
+		validation.Field(&c.MySQL, validation.When(driver == DatabaseDriverMySQL, validation.Required)),
+		validation.Field(&c.SQLite, validation.When(driver == DatabaseDriverSQLite, validation.Required)),
 	)
 }
 
+// DatabaseDriver returns the driver of the database, postgres by default.
+func (c *AppConfig) DatabaseDriver() string {
+	if c.Database == nil || c.Database.Driver == "" {
+		return DatabaseDriverPostgres
+	}
+
+	return c.Database.Driver
+}
+
+// RedisNeeded reports whether the rate limit or idempotency backend is Redis, which is only connected then.
+func (c *AppConfig) RedisNeeded() bool {
+	return (c.RateLimit != nil && c.RateLimit.Backend == RateLimitBackendRedis) ||
+		(c.Idempotency != nil && c.Idempotency.Backend == IdempotencyBackendRedis)
+}
+
+// SQL returns the settings shared by all the drivers, of the database of DatabaseDriver.
+func (c *AppConfig) SQL() *SQLConfig {
+	switch c.DatabaseDriver() {
+	case DatabaseDriverMySQL:
+		return &c.MySQL.SQLConfig
+	case DatabaseDriverSQLite:
+		return &c.SQLite.SQLConfig
+	default:
+		return &c.Postgres.SQLConfig
+	}
+}
+
 func (c *AppConfig) validateConfig() error {
 	if err := c.validate(); err != nil {
 		return fmt.Errorf("c.validate() -> %w", err)
@@ -28,8 +75,68 @@ func (c *AppConfig) validateConfig() error {
 		return fmt.Errorf("c.API.validate() -> %w", err)
 	}
 
-	if err := c.Postgres.validate(); err != nil {
-		return fmt.Errorf("c.Postgres.validate() -> %w", err)
+	if c.Log != nil {
+		if err := c.Log.validate(); err != nil {
+			return fmt.Errorf("c.Log.validate() -> %w", err)
+		}
+	}
+
+	if c.Database != nil {
+		if err := c.Database.validate(); err != nil {
+			return fmt.Errorf("c.Database.validate() -> %w", err)
+		}
+	}
+
+	switch c.DatabaseDriver() {
+	case DatabaseDriverPostgres:
+		if err := c.Postgres.validate(); err != nil {
+			return fmt.Errorf("c.Postgres.validate() -> %w", err)
+		}
+	case DatabaseDriverMySQL:
+		if err := c.MySQL.validate(); err != nil {
+			return fmt.Errorf("c.MySQL.validate() -> %w", err)
+		}
+	case DatabaseDriverSQLite:
+		if err := c.SQLite.validate(); err != nil {
+			return fmt.Errorf("c.SQLite.validate() -> %w", err)
+		}
+	}
+
+	if c.RateLimit != nil {
+		if err := c.RateLimit.validate(); err != nil {
+			return fmt.Errorf("c.RateLimit.validate() -> %w", err)
+		}
+
+		if c.RateLimit.Backend == RateLimitBackendRedis && (c.Redis == nil || c.Redis.Addr == "") {
+			return errors.New("redis must be configured to use it as rate limit backend")
+		}
+	}
+
+	if c.Idempotency != nil {
+		if err := c.Idempotency.validate(); err != nil {
+			return fmt.Errorf("c.Idempotency.validate() -> %w", err)
+		}
+
+		if c.Idempotency.Backend == IdempotencyBackendRedis && (c.Redis == nil || c.Redis.Addr == "") {
+			return errors.New("redis must be configured to use it as idempotency backend")
+		}
+	}
+
+	if c.Search != nil {
+		if err := c.Search.validate(); err != nil {
+			return fmt.Errorf("c.Search.validate() -> %w", err)
+		}
+	}
+
+	// Only Postgres has full-text search, articles are searched with bleve otherwise.
+	if c.DatabaseDriver() != DatabaseDriverPostgres && (c.Search == nil || c.Search.Backend != SearchBackendBleve) {
+		return fmt.Errorf("search backend must be bleve with the %v database driver", c.DatabaseDriver())
+	}
+
+	if c.Import != nil {
+		if err := c.Import.validate(); err != nil {
+			return fmt.Errorf("c.Import.validate() -> %w", err)
+		}
 	}
 
 	return nil
@@ -41,6 +148,9 @@ type APIConfig struct {
 	BaseURL            string   `mapstructure:"BASE_URL"`
 	AllowedCORSDomains []string `mapstructure:"ALLOWED_CORS_DOMAINS"`
 	JWTSigningKey      string   `mapstructure:"JWT_SIGNING_KEY"`
+
+	// CursorSigningKey signs pagination cursors, a key is derived from JWTSigningKey when it's empty.
+	CursorSigningKey string `mapstructure:"CURSOR_SIGNING_KEY"`
 }
 
 func (c *APIConfig) validate() error {
@@ -53,22 +163,293 @@ func (c *APIConfig) validate() error {
 	)
 }
 
+// LogConfig configures the application logger.
+// Empty values fall back to defaults based on APIConfig.Environment.
+type LogConfig struct {
+	Level       string   `mapstructure:"LEVEL"`        // debug, info, warn, error, dpanic, panic or fatal
+	Encoding    string   `mapstructure:"ENCODING"`     // json or console
+	OutputPaths []string `mapstructure:"OUTPUT_PATHS"` // stdout, stderr or file paths
+
+	// File rotation, only applies to file paths in OutputPaths.
+	MaxSizeMB  int  `mapstructure:"MAX_SIZE_MB"`
+	MaxBackups int  `mapstructure:"MAX_BACKUPS"`
+	MaxAgeDays int  `mapstructure:"MAX_AGE_DAYS"`
+	Compress   bool `mapstructure:"COMPRESS"`
+
+	// Sampling logs the first SamplingInitial entries with the same level and message
+	// in each second, then every SamplingThereafter-th entry. Disabled when 0.
+	SamplingInitial    int `mapstructure:"SAMPLING_INITIAL"`
+	SamplingThereafter int `mapstructure:"SAMPLING_THEREAFTER"`
+}
+
+func (c *LogConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Level, validation.In("debug", "info", "warn", "error", "dpanic", "panic", "fatal")),
+		validation.Field(&c.Encoding, validation.In("json", "console")),
+		validation.Field(&c.MaxSizeMB, validation.Min(0)),
+		validation.Field(&c.MaxBackups, validation.Min(0)),
+		validation.Field(&c.MaxAgeDays, validation.Min(0)),
+		validation.Field(&c.SamplingInitial, validation.Min(0)),
+		validation.Field(&c.SamplingThereafter, validation.Min(0)),
+	)
+}
+
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverMySQL    = "mysql"
+	DatabaseDriverSQLite   = "sqlite"
+)
+
+// DatabaseConfig selects the database, which is configured by the section of its driver.
+type DatabaseConfig struct {
+	Driver string `mapstructure:"DRIVER"` // postgres, mysql or sqlite, postgres by default
+}
+
+func (c *DatabaseConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Driver, validation.In(DatabaseDriverPostgres, DatabaseDriverMySQL, DatabaseDriverSQLite)),
+	)
+}
+
+const (
+	TxIsolationReadCommitted  = "read committed"
+	TxIsolationRepeatableRead = "repeatable read"
+	TxIsolationSerializable   = "serializable"
+)
+
+// SQLConfig is shared by the configs of all the database drivers.
+type SQLConfig struct {
+	LogLevel string `mapstructure:"LOG_LEVEL"`
+
+	// SlowThreshold is how long a query can take before it's logged as a slow query.
+	SlowThreshold time.Duration `mapstructure:"SLOW_THRESHOLD"`
+
+	// TxIsolation is the isolation level of transactions, empty uses the one of the database.
+	TxIsolation string `mapstructure:"TX_ISOLATION"`
+	// TxMaxRetries is how many times a transaction is run again after a serialization failure (SQLSTATE 40001).
+	TxMaxRetries int `mapstructure:"TX_MAX_RETRIES"`
+	// TxRetryBackoff is how long to wait before retrying a transaction, it's doubled before each next retry.
+	TxRetryBackoff time.Duration `mapstructure:"TX_RETRY_BACKOFF"`
+
+	// Connection pool of the database and of each replica, 0 keeps the defaults of database/sql.
+	MaxOpenConns    int           `mapstructure:"MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `mapstructure:"MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `mapstructure:"CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `mapstructure:"CONN_MAX_IDLE_TIME"`
+
+	// ConnectRetries is how many times connecting is tried again when the database isn't ready at startup.
+	ConnectRetries int `mapstructure:"CONNECT_RETRIES"`
+	// ConnectRetryBackoff is how long to wait before connecting again, it's doubled before each next retry.
+	ConnectRetryBackoff time.Duration `mapstructure:"CONNECT_RETRY_BACKOFF"`
+}
+
+func (c *SQLConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.TxIsolation, validation.In(TxIsolationReadCommitted, TxIsolationRepeatableRead, TxIsolationSerializable)),
+		validation.Field(&c.TxMaxRetries, validation.Min(0)),
+		validation.Field(&c.TxRetryBackoff, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxOpenConns, validation.Min(0)),
+		validation.Field(&c.MaxIdleConns, validation.Min(0)),
+		validation.Field(&c.ConnMaxLifetime, validation.Min(time.Duration(0))),
+		validation.Field(&c.ConnMaxIdleTime, validation.Min(time.Duration(0))),
+		validation.Field(&c.ConnectRetries, validation.Min(0)),
+		validation.Field(&c.ConnectRetryBackoff, validation.Min(time.Duration(0))),
+	)
+}
+
+// TxIsolationLevel returns the sql.IsolationLevel of TxIsolation, sql.LevelDefault when it's empty.
+func (c *SQLConfig) TxIsolationLevel() sql.IsolationLevel {
+	switch c.TxIsolation {
+	case TxIsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case TxIsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case TxIsolationSerializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
+
 type PostgresConfig struct {
 	Host     string `mapstructure:"HOST"`
 	Port     string `mapstructure:"PORT"`
 	User     string `mapstructure:"USER"`
 	Password string `mapstructure:"PASSWORD"`
 	DB       string `mapstructure:"DB"`
-	LogLevel string `mapstructure:"LOG_LEVEL"`
+
+	SQLConfig `mapstructure:",squash"`
+
+	// TLS, SSLMode is disable, allow, prefer, require, verify-ca or verify-full, disable by default.
+	// Certificates and keys are paths of PEM files, SSLCert and SSLKey authenticate the client.
+	SSLMode     string `mapstructure:"SSL_MODE"`
+	SSLRootCert string `mapstructure:"SSL_ROOT_CERT"`
+	SSLCert     string `mapstructure:"SSL_CERT"`
+	SSLKey      string `mapstructure:"SSL_KEY"`
+
+	// StatementTimeout aborts statements running longer than it, including exports. Disabled when 0.
+	StatementTimeout time.Duration `mapstructure:"STATEMENT_TIMEOUT"`
+
+	// ReplicaHosts are the host:port of read replicas, which listing, searching and exporting articles read from.
+	// They share the user, password, database and TLS settings of the primary.
+	ReplicaHosts []string `mapstructure:"REPLICA_HOSTS"`
 }
 
 func (c *PostgresConfig) validate() error {
-	return validation.ValidateStruct(
+	err := validation.ValidateStruct(
 		c,
 		validation.Field(&c.Host, validation.Required),
 		validation.Field(&c.Port, validation.Required),
 		validation.Field(&c.User, validation.Required),
 		validation.Field(&c.Password, validation.Required),
 		validation.Field(&c.DB, validation.Required),
+		validation.Field(&c.SSLMode, validation.In("disable", "allow", "prefer", "require", "verify-ca", "verify-full")),
+		validation.Field(&c.SSLCert, validation.When(c.SSLKey != "", validation.Required)),
+		validation.Field(&c.SSLKey, validation.When(c.SSLCert != "", validation.Required)),
+		validation.Field(&c.StatementTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.ReplicaHosts, validation.Each(is.DialString)),
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.SQLConfig.validate()
+}
+
+type MySQLConfig struct {
+	Host     string `mapstructure:"HOST"`
+	Port     string `mapstructure:"PORT"`
+	User     string `mapstructure:"USER"`
+	Password string `mapstructure:"PASSWORD"`
+	DB       string `mapstructure:"DB"`
+
+	// TLS is false, true, skip-verify or preferred, false by default.
+	TLS string `mapstructure:"TLS"`
+
+	SQLConfig `mapstructure:",squash"`
+}
+
+func (c *MySQLConfig) validate() error {
+	err := validation.ValidateStruct(
+		c,
+		validation.Field(&c.Host, validation.Required),
+		validation.Field(&c.Port, validation.Required),
+		validation.Field(&c.User, validation.Required),
+		validation.Field(&c.DB, validation.Required),
+		validation.Field(&c.TLS, validation.In("false", "true", "skip-verify", "preferred")),
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.SQLConfig.validate()
+}
+
+type SQLiteConfig struct {
+	Path string `mapstructure:"PATH"` // file of the database, created along with its directory if it doesn't exist
+
+	SQLConfig `mapstructure:",squash"`
+}
+
+func (c *SQLiteConfig) validate() error {
+	err := validation.ValidateStruct(
+		c,
+		validation.Field(&c.Path, validation.Required),
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.SQLConfig.validate()
+}
+
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// RateLimitConfig configures the rate limit of each route group.
+// Limits look like "10/1m", empty means unlimited.
+type RateLimitConfig struct {
+	Backend        string `mapstructure:"BACKEND"` // memory or redis, empty disables rate limiting
+	Auth           string `mapstructure:"AUTH"`
+	Users          string `mapstructure:"USERS"`
+	Articles       string `mapstructure:"ARTICLES"`        // anonymous reads, per IP
+	AuthorArticles string `mapstructure:"AUTHOR_ARTICLES"` // writes of logged in authors, per user
+}
+
+func (c *RateLimitConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Backend, validation.In(RateLimitBackendMemory, RateLimitBackendRedis)),
+		validation.Field(&c.Auth, validation.By(validateLimit)),
+		validation.Field(&c.Users, validation.By(validateLimit)),
+		validation.Field(&c.Articles, validation.By(validateLimit)),
+		validation.Field(&c.AuthorArticles, validation.By(validateLimit)),
+	)
+}
+
+func validateLimit(value any) error {
+	_, err := ratelimit.ParseLimit(value.(string))
+
+	return err
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"ADDR"`
+	Password string `mapstructure:"PASSWORD"`
+	DB       int    `mapstructure:"DB"`
+}
+
+const (
+	IdempotencyBackendPostgres = "postgres"
+	IdempotencyBackendRedis    = "redis"
+)
+
+// IdempotencyConfig configures where responses of requests with an Idempotency-Key are stored.
+type IdempotencyConfig struct {
+	Backend string        `mapstructure:"BACKEND"` // postgres or redis, empty disables Idempotency-Key support
+	TTL     time.Duration `mapstructure:"TTL"`     // how long responses are kept, 24h by default
+}
+
+func (c *IdempotencyConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Backend, validation.In(IdempotencyBackendPostgres, IdempotencyBackendRedis)),
+		validation.Field(&c.TTL, validation.Min(time.Duration(0))),
+	)
+}
+
+const (
+	SearchBackendPostgres = "postgres"
+	SearchBackendBleve    = "bleve"
+)
+
+// SearchConfig configures the full-text search of articles.
+type SearchConfig struct {
+	Backend   string `mapstructure:"BACKEND"`    // postgres or bleve, postgres by default
+	BlevePath string `mapstructure:"BLEVE_PATH"` // directory of the bleve index, created if it doesn't exist
+}
+
+func (c *SearchConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Backend, validation.In(SearchBackendPostgres, SearchBackendBleve)),
+		validation.Field(&c.BlevePath, validation.When(c.Backend == SearchBackendBleve, validation.Required)),
+	)
+}
+
+// ImportConfig configures the bulk import of articles.
+type ImportConfig struct {
+	BatchSize int `mapstructure:"BATCH_SIZE"` // rows created in each transaction, 100 by default
+}
+
+func (c *ImportConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.BatchSize, validation.Min(0)),
 	)
 }
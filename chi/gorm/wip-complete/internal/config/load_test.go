@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -15,15 +16,56 @@ const (
 	apiBaseURL            = "localhost:" + apiPort
 	apiAllowedCORSDomains = "my-domain1.com,my-domain2.com"
 	apiJWTSigningKey      = "test_jwt_key"
+	apiCursorSigningKey   = "test_cursor_key"
 
 	ginMode = "debug"
 
-	postgresHost     = "pg"
-	postgresPort     = "5678"
-	postgresUsername = "root"
-	postgresPassword = "pass123"
-	postgresDB       = "testDB"
-	postgresLogLevel = "error"
+	logLevel              = "info"
+	logEncoding           = "json"
+	logOutputPaths        = "stdout,/var/log/app.log"
+	logMaxSizeMB          = "50"
+	logSamplingInitial    = "100"
+	logSamplingThereafter = "10"
+
+	databaseDriver = "postgres"
+
+	postgresHost          = "pg"
+	postgresPort          = "5678"
+	postgresUsername      = "root"
+	postgresPassword      = "pass123"
+	postgresDB            = "testDB"
+	postgresLogLevel      = "error"
+	postgresSlowThreshold = "500ms"
+	postgresTxIsolation   = "serializable"
+	postgresTxMaxRetries  = "3"
+	postgresTxBackoff     = "10ms"
+	postgresSSLMode       = "verify-full"
+	postgresSSLRootCert   = "/etc/ssl/pg/root.crt"
+	postgresSSLCert       = "/etc/ssl/pg/client.crt"
+	postgresSSLKey        = "/etc/ssl/pg/client.key"
+	postgresMaxOpenConns  = "20"
+	postgresMaxIdleConns  = "10"
+	postgresConnLifetime  = "30m"
+	postgresConnIdleTime  = "5m"
+	postgresStmtTimeout   = "15s"
+	postgresRetries       = "5"
+	postgresRetryBackoff  = "1s"
+	postgresReplicaHosts  = "pg-replica1:5678,pg-replica2:5678"
+
+	rateLimitBackend        = "redis"
+	rateLimitAuth           = "10/1m"
+	rateLimitArticles       = "100/1m"
+	rateLimitAuthorArticles = "30/1m"
+
+	redisAddr = "redis:6379"
+
+	idempotencyBackend = "postgres"
+	idempotencyTTL     = "1h"
+
+	searchBackend   = "bleve"
+	searchBlevePath = "/var/lib/app/articles.bleve"
+
+	importBatchSize = "50"
 )
 
 func TestLoad(t *testing.T) {
@@ -53,6 +95,18 @@ func TestLoad(t *testing.T) {
 					BaseURL:            apiBaseURL,
 					AllowedCORSDomains: strings.Split(apiAllowedCORSDomains, ","),
 					JWTSigningKey:      apiJWTSigningKey,
+					CursorSigningKey:   apiCursorSigningKey,
+				},
+				Log: &LogConfig{
+					Level:              logLevel,
+					Encoding:           logEncoding,
+					OutputPaths:        strings.Split(logOutputPaths, ","),
+					MaxSizeMB:          50,
+					SamplingInitial:    100,
+					SamplingThereafter: 10,
+				},
+				Database: &DatabaseConfig{
+					Driver: databaseDriver,
 				},
 				Postgres: &PostgresConfig{
 					Host:     postgresHost,
@@ -60,7 +114,45 @@ func TestLoad(t *testing.T) {
 					User:     postgresUsername,
 					Password: postgresPassword,
 					DB:       postgresDB,
-					LogLevel: postgresLogLevel,
+					SQLConfig: SQLConfig{
+						LogLevel:            postgresLogLevel,
+						SlowThreshold:       500 * time.Millisecond,
+						TxIsolation:         postgresTxIsolation,
+						TxMaxRetries:        3,
+						TxRetryBackoff:      10 * time.Millisecond,
+						MaxOpenConns:        20,
+						MaxIdleConns:        10,
+						ConnMaxLifetime:     30 * time.Minute,
+						ConnMaxIdleTime:     5 * time.Minute,
+						ConnectRetries:      5,
+						ConnectRetryBackoff: time.Second,
+					},
+					SSLMode:          postgresSSLMode,
+					SSLRootCert:      postgresSSLRootCert,
+					SSLCert:          postgresSSLCert,
+					SSLKey:           postgresSSLKey,
+					StatementTimeout: 15 * time.Second,
+					ReplicaHosts:     strings.Split(postgresReplicaHosts, ","),
+				},
+				RateLimit: &RateLimitConfig{
+					Backend:        rateLimitBackend,
+					Auth:           rateLimitAuth,
+					Articles:       rateLimitArticles,
+					AuthorArticles: rateLimitAuthorArticles,
+				},
+				Redis: &RedisConfig{
+					Addr: redisAddr,
+				},
+				Idempotency: &IdempotencyConfig{
+					Backend: idempotencyBackend,
+					TTL:     time.Hour,
+				},
+				Search: &SearchConfig{
+					Backend:   searchBackend,
+					BlevePath: searchBlevePath,
+				},
+				Import: &ImportConfig{
+					BatchSize: 50,
 				},
 			},
 			wantErr:    false,
@@ -111,6 +203,81 @@ func TestLoad(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: `conf.validateConfig -> c.API.validate() -> Port: cannot be blank.`,
 		},
+		{
+			name: "Invalid Log configs - unknown level",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("LOG_LEVEL", "verbose")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Log.validate() -> Level: must be a valid value.`,
+		},
+		{
+			name: "Invalid RateLimit configs - invalid limit",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("RATE_LIMIT_AUTH", "10 per minute")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.RateLimit.validate() -> Auth: limit must look like "10/1m".`,
+		},
+		{
+			name: "Invalid RateLimit configs - Redis backend without Redis",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Unsetenv("REDIS_ADDR")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> redis must be configured to use it as rate limit backend`,
+		},
+		{
+			name: "Invalid Idempotency configs - unknown backend",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("IDEMPOTENCY_BACKEND", "memcached")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Idempotency.validate() -> Backend: must be a valid value.`,
+		},
+		{
+			name: "Invalid Search configs - bleve without path",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Unsetenv("SEARCH_BLEVE_PATH")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Search.validate() -> BlevePath: cannot be blank.`,
+		},
 		{
 			name: "Invalid Postgres configs - missing DB",
 			setupENV: func() {
@@ -126,6 +293,100 @@ func TestLoad(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: `conf.validateConfig -> c.Postgres.validate() -> DB: cannot be blank.`,
 		},
+		{
+			name: "Invalid Postgres configs - unknown isolation level",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("POSTGRES_TX_ISOLATION", "snapshot")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Postgres.validate() -> TxIsolation: must be a valid value.`,
+		},
+		{
+			name: "Invalid Database configs - unknown driver",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("DATABASE_DRIVER", "oracle")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Database.validate() -> Driver: must be a valid value.`,
+		},
+		{
+			name: "Missing SQLite configs",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("DATABASE_DRIVER", "sqlite")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.validate() -> SQLite: cannot be blank.`,
+		},
+		{
+			name: "Invalid Search configs - Postgres search without Postgres",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("DATABASE_DRIVER", "sqlite")
+				require.NoError(t, err)
+				err = os.Setenv("SQLITE_PATH", "/var/lib/app/app.db")
+				require.NoError(t, err)
+				err = os.Setenv("SEARCH_BACKEND", "postgres")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> search backend must be bleve with the sqlite database driver`,
+		},
+		{
+			name: "Invalid Postgres configs - client certificate without key",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Unsetenv("POSTGRES_SSL_KEY")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Postgres.validate() -> SSLKey: cannot be blank.`,
+		},
+		{
+			name: "Invalid Postgres configs - replica without port",
+			setupENV: func() {
+				setENVs(t)
+
+				err := os.Setenv("POSTGRES_REPLICA_HOSTS", "pg-replica1")
+				require.NoError(t, err)
+			},
+			args: args{
+				configFile: "testdata/good.yml",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantErrMsg: `conf.validateConfig -> c.Postgres.validate() -> ReplicaHosts: (0: must be a valid dial string.).`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -152,18 +413,52 @@ func TestLoad(t *testing.T) {
 
 func setENVs(t *testing.T) {
 	m := map[string]string{
-		"API_ENV":                  apiENV,
-		"API_PORT":                 apiPort,
-		"API_BASE_URL":             apiBaseURL,
-		"API_ALLOWED_CORS_DOMAINS": apiAllowedCORSDomains,
-		"API_JWT_SIGNING_KEY":      apiJWTSigningKey,
-		"GIN_MODE":                 ginMode,
-		"POSTGRES_HOST":            postgresHost,
-		"POSTGRES_PORT":            postgresPort,
-		"POSTGRES_USER":            postgresUsername,
-		"POSTGRES_PASSWORD":        postgresPassword,
-		"POSTGRES_DB":              postgresDB,
-		"POSTGRES_LOG_LEVEL":       postgresLogLevel,
+		"API_ENV":                        apiENV,
+		"API_PORT":                       apiPort,
+		"API_BASE_URL":                   apiBaseURL,
+		"API_ALLOWED_CORS_DOMAINS":       apiAllowedCORSDomains,
+		"API_JWT_SIGNING_KEY":            apiJWTSigningKey,
+		"API_CURSOR_SIGNING_KEY":         apiCursorSigningKey,
+		"GIN_MODE":                       ginMode,
+		"LOG_LEVEL":                      logLevel,
+		"LOG_ENCODING":                   logEncoding,
+		"LOG_OUTPUT_PATHS":               logOutputPaths,
+		"LOG_MAX_SIZE_MB":                logMaxSizeMB,
+		"LOG_SAMPLING_INITIAL":           logSamplingInitial,
+		"LOG_SAMPLING_THEREAFTER":        logSamplingThereafter,
+		"DATABASE_DRIVER":                databaseDriver,
+		"POSTGRES_HOST":                  postgresHost,
+		"POSTGRES_PORT":                  postgresPort,
+		"POSTGRES_USER":                  postgresUsername,
+		"POSTGRES_PASSWORD":              postgresPassword,
+		"POSTGRES_DB":                    postgresDB,
+		"POSTGRES_LOG_LEVEL":             postgresLogLevel,
+		"POSTGRES_SLOW_THRESHOLD":        postgresSlowThreshold,
+		"POSTGRES_TX_ISOLATION":          postgresTxIsolation,
+		"POSTGRES_TX_MAX_RETRIES":        postgresTxMaxRetries,
+		"POSTGRES_TX_RETRY_BACKOFF":      postgresTxBackoff,
+		"POSTGRES_SSL_MODE":              postgresSSLMode,
+		"POSTGRES_SSL_ROOT_CERT":         postgresSSLRootCert,
+		"POSTGRES_SSL_CERT":              postgresSSLCert,
+		"POSTGRES_SSL_KEY":               postgresSSLKey,
+		"POSTGRES_MAX_OPEN_CONNS":        postgresMaxOpenConns,
+		"POSTGRES_MAX_IDLE_CONNS":        postgresMaxIdleConns,
+		"POSTGRES_CONN_MAX_LIFETIME":     postgresConnLifetime,
+		"POSTGRES_CONN_MAX_IDLE_TIME":    postgresConnIdleTime,
+		"POSTGRES_STATEMENT_TIMEOUT":     postgresStmtTimeout,
+		"POSTGRES_CONNECT_RETRIES":       postgresRetries,
+		"POSTGRES_CONNECT_RETRY_BACKOFF": postgresRetryBackoff,
+		"POSTGRES_REPLICA_HOSTS":         postgresReplicaHosts,
+		"RATE_LIMIT_BACKEND":             rateLimitBackend,
+		"RATE_LIMIT_AUTH":                rateLimitAuth,
+		"RATE_LIMIT_ARTICLES":            rateLimitArticles,
+		"RATE_LIMIT_AUTHOR_ARTICLES":     rateLimitAuthorArticles,
+		"REDIS_ADDR":                     redisAddr,
+		"IDEMPOTENCY_BACKEND":            idempotencyBackend,
+		"IDEMPOTENCY_TTL":                idempotencyTTL,
+		"SEARCH_BACKEND":                 searchBackend,
+		"SEARCH_BLEVE_PATH":              searchBlevePath,
+		"IMPORT_BATCH_SIZE":              importBatchSize,
 	}
 
 	for k, v := range m {
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	applogger "github.com/yizeng/gab/chi/gorm/wip-complete/internal/logger"
+)
+
+func Test_zapLogger_Trace(t *testing.T) {
+	const sql = `SELECT * FROM "articles" WHERE id = 1`
+
+	type args struct {
+		level   logger.LogLevel
+		elapsed time.Duration
+		err     error
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantLevel zapcore.Level
+		wantMsg   string
+		wantLogs  int
+	}{
+		{
+			name: "Info level logs every query",
+			args: args{
+				level: logger.Info,
+			},
+			wantLevel: zapcore.InfoLevel,
+			wantMsg:   "sql",
+			wantLogs:  1,
+		},
+		{
+			name: "Warn level logs slow queries",
+			args: args{
+				level:   logger.Warn,
+				elapsed: time.Second,
+			},
+			wantLevel: zapcore.WarnLevel,
+			wantMsg:   "slow sql",
+			wantLogs:  1,
+		},
+		{
+			name: "Warn level skips fast queries",
+			args: args{
+				level: logger.Warn,
+			},
+			wantLogs: 0,
+		},
+		{
+			name: "Error level logs failed queries",
+			args: args{
+				level: logger.Error,
+				err:   errors.New("DB error"),
+			},
+			wantLevel: zapcore.ErrorLevel,
+			wantMsg:   "sql failed",
+			wantLogs:  1,
+		},
+		{
+			name: "Error level ignores record not found",
+			args: args{
+				level: logger.Error,
+				err:   gorm.ErrRecordNotFound,
+			},
+			wantLogs: 0,
+		},
+		{
+			name: "Silent level logs nothing",
+			args: args{
+				level: logger.Silent,
+				err:   errors.New("DB error"),
+			},
+			wantLogs: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			defer zap.ReplaceGlobals(zap.New(core))()
+
+			l := newZapLogger(tt.args.level, 100*time.Millisecond, true)
+			ctx := applogger.WithRequestID(context.Background(), "req-1")
+			l.Trace(ctx, time.Now().Add(-tt.args.elapsed), func() (string, int64) {
+				return sql, 1
+			}, tt.args.err)
+
+			assert.Equal(t, tt.wantLogs, logs.Len())
+			if tt.wantLogs == 0 {
+				return
+			}
+
+			entry := logs.All()[0]
+			assert.Equal(t, tt.wantLevel, entry.Level)
+			assert.Equal(t, tt.wantMsg, entry.Message)
+			assert.Equal(t, sql, entry.ContextMap()["sql"])
+			assert.Equal(t, "req-1", entry.ContextMap()["request_id"])
+		})
+	}
+}
+
+func Test_zapLogger_ParamsFilter(t *testing.T) {
+	const sql = `SELECT * FROM "users" WHERE email = $1`
+
+	redacted := newZapLogger(logger.Info, 0, true)
+	_, params := redacted.ParamsFilter(context.Background(), sql, "123@test.com")
+	assert.Nil(t, params)
+
+	plain := newZapLogger(logger.Info, 0, false)
+	_, params = plain.ParamsFilter(context.Background(), sql, "123@test.com")
+	assert.Equal(t, []interface{}{"123@test.com"}, params)
+}
@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
+)
+
+// Migrate applies the pending migrations.
+// Replicas starting together take turns, the later ones find nothing left to apply.
+func Migrate(ctx context.Context, db *gorm.DB) error {
+	migrator, err := dao.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("dao.NewMigrator -> %w", err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		zap.L().Info("applied migration", zap.Uint64("version", m.Version), zap.String("name", m.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("migrator.Up -> %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
+)
+
+func Test_buildMySQLDSN(t *testing.T) {
+	dsn, err := mysqldriver.ParseDSN(buildMySQLDSN(&config.MySQLConfig{
+		Host:     "::1",
+		Port:     "3306",
+		User:     "mysql",
+		Password: "p@ss:word/",
+		DB:       "app",
+		TLS:      "skip-verify",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "tcp", dsn.Net)
+	assert.Equal(t, "[::1]:3306", dsn.Addr)
+	assert.Equal(t, "mysql", dsn.User)
+	assert.Equal(t, "p@ss:word/", dsn.Passwd)
+	assert.Equal(t, "app", dsn.DBName)
+	assert.Equal(t, "skip-verify", dsn.TLSConfig)
+	assert.True(t, dsn.ParseTime)
+	assert.True(t, dsn.MultiStatements)
+	assert.Equal(t, time.UTC, dsn.Loc)
+}
@@ -1,53 +1,117 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
 )
 
-func OpenPostgres(conf *config.PostgresConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%v port=%v user=%v password=%v dbname=%v sslmode=disable",
-		conf.Host, conf.Port, conf.User, conf.Password, conf.DB,
-	)
+const defaultSSLMode = "disable"
 
-	gormLogger := createLogger(conf.LogLevel)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+// OpenPostgres connects to the primary and the read replicas of conf.
+func OpenPostgres(conf *config.PostgresConfig, environment string) (*gorm.DB, error) {
+	return openWithRetries(&conf.SQLConfig, "postgres", func() (*gorm.DB, error) {
+		db, err := openGorm(postgres.Open(buildDSN(conf, conf.Host, conf.Port)), &conf.SQLConfig, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = useReplicas(db, conf); err != nil {
+			closeDB(db)
+
+			return nil, err
+		}
+
+		return db, nil
+	})
+}
+
+// useReplicas registers the read replicas, which get the same pool limits as the primary.
+func useReplicas(db *gorm.DB, conf *config.PostgresConfig) error {
+	if len(conf.ReplicaHosts) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(conf.ReplicaHosts))
+	for _, replicaHost := range conf.ReplicaHosts {
+		host, port, err := net.SplitHostPort(replicaHost)
+		if err != nil {
+			return fmt.Errorf("net.SplitHostPort -> %w", err)
+		}
+
+		replicas = append(replicas, postgres.Open(buildDSN(conf, host, port)))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, dao.ReplicasResolver)
+	err := resolver.Call(func(pool gorm.ConnPool) error {
+		if sqlDB, ok := pool.(*sql.DB); ok {
+			setPoolLimits(sqlDB, &conf.SQLConfig)
+		}
+
+		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("gorm.Open -> %w", err)
+		return fmt.Errorf("resolver.Call -> %w", err)
 	}
 
-	if err = dao.InitTables(db); err != nil {
-		return nil, fmt.Errorf("dao.InitTables -> %w", err)
+	if err = db.Use(resolver); err != nil {
+		return fmt.Errorf("db.Use -> %w", err)
 	}
 
-	return db, nil
+	return nil
 }
 
-func createLogger(logLevel string) logger.Interface {
-	var l logger.LogLevel
+// buildDSN returns the keyword/value connection string of the database at host:port.
+// The statement timeout is sent as a run-time parameter when connecting.
+func buildDSN(conf *config.PostgresConfig, host, port string) string {
+	sslMode := conf.SSLMode
+	if sslMode == "" {
+		sslMode = defaultSSLMode
+	}
+
+	params := [][2]string{
+		{"host", host},
+		{"port", port},
+		{"user", conf.User},
+		{"password", conf.Password},
+		{"dbname", conf.DB},
+		{"sslmode", sslMode},
+	}
+	if conf.SSLRootCert != "" {
+		params = append(params, [2]string{"sslrootcert", conf.SSLRootCert})
+	}
+	if conf.SSLCert != "" {
+		params = append(params, [2]string{"sslcert", conf.SSLCert}, [2]string{"sslkey", conf.SSLKey})
+	}
+	if conf.StatementTimeout > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.FormatInt(conf.StatementTimeout.Milliseconds(), 10)})
+	}
+
+	pairs := make([]string, 0, len(params))
+	for _, param := range params {
+		pairs = append(pairs, param[0]+"="+quoteDSNValue(param[1]))
+	}
+
+	return strings.Join(pairs, " ")
+}
 
-	switch strings.ToLower(logLevel) {
-	case "silent":
-		l = logger.Silent
-	case "error":
-		l = logger.Error
-	case "warn":
-		l = logger.Warn
-	case "info":
-		l = logger.Info
-	default:
-		l = logger.Error
+// quoteDSNValue quotes values that are empty or contain spaces, quotes or backslashes, like passwords may.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
 	}
 
-	return logger.Default.LogMode(l)
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
 }
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
+)
+
+func Test_buildDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.PostgresConfig
+		want string
+	}{
+		{
+			name: "TLS is disabled by default",
+			conf: config.PostgresConfig{User: "postgres", Password: "secret", DB: "app"},
+			want: "host=replica port=5433 user=postgres password=secret dbname=app sslmode=disable",
+		},
+		{
+			name: "Certificates and statement timeout",
+			conf: config.PostgresConfig{
+				User:             "postgres",
+				Password:         "secret",
+				DB:               "app",
+				SSLMode:          "verify-full",
+				SSLRootCert:      "/certs/root.crt",
+				SSLCert:          "/certs/client.crt",
+				SSLKey:           "/certs/client.key",
+				StatementTimeout: 15 * time.Second,
+			},
+			want: "host=replica port=5433 user=postgres password=secret dbname=app sslmode=verify-full " +
+				"sslrootcert=/certs/root.crt sslcert=/certs/client.crt sslkey=/certs/client.key statement_timeout=15000",
+		},
+		{
+			name: "Values with spaces, quotes or backslashes are quoted",
+			conf: config.PostgresConfig{User: "postgres", Password: `it's a \secret`, DB: "app"},
+			want: `host=replica port=5433 user=postgres password='it\'s a \\secret' dbname=app sslmode=disable`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildDSN(&tt.conf, "replica", "5433")
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_buildDSN_Parse(t *testing.T) {
+	conf := config.PostgresConfig{
+		User:             "postgres",
+		Password:         `it's a \secret`,
+		DB:               "app",
+		SSLMode:          "require",
+		StatementTimeout: 1500 * time.Millisecond,
+	}
+
+	got, err := pgconn.ParseConfig(buildDSN(&conf, "replica", "5433"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "replica", got.Host)
+	assert.EqualValues(t, 5433, got.Port)
+	assert.Equal(t, conf.Password, got.Password)
+	assert.Equal(t, "app", got.Database)
+	assert.NotNil(t, got.TLSConfig)
+	assert.Equal(t, "1500", got.RuntimeParams["statement_timeout"])
+}
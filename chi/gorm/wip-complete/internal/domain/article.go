@@ -9,6 +9,73 @@ type Article struct {
 	Title   string `json:"title"`
 	Content string `json:"content"`
 
+	Version uint `json:"version"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Author *Author `json:"author,omitempty"` // only when requested with include=author
+}
+
+// ArticleRelations are the relations loaded along with articles.
+type ArticleRelations struct {
+	Author bool
+}
+
+// ArticleImportRow is a row of a bulk import of articles.
+type ArticleImportRow struct {
+	Row     int     // position in the import, starting from 1
+	Article Article // the created article once it's imported
+	Err     error   // why the row isn't imported, set beforehand for invalid rows
+}
+
+// ArticleExport narrows the articles of an export beyond the filters of a list.
+type ArticleExport struct {
+	Query    string // search terms as in ArticleSearch, no full-text filter when empty
+	Language string // one of SearchLanguages
+}
+
+// ArticleMatch is an article found by a full-text search.
+type ArticleMatch struct {
+	Article
+
+	Rank     float32 `json:"rank"`     // relevance of the article, higher is better
+	Headline string  `json:"headline"` // HTML-escaped fragments of the content with the matches in <b></b>
+}
+
+// DefaultSearchLanguage is the text search configuration articles are indexed with.
+const DefaultSearchLanguage = "english"
+
+// SearchLanguages are the text search configurations articles can be searched in.
+var SearchLanguages = []string{"simple", "english", "german", "spanish", "french", "italian", "portuguese", "dutch"}
+
+// ArticleSearch is a full-text search of articles.
+type ArticleSearch struct {
+	Query    string // search terms in the syntax of web search engines
+	Language string // one of SearchLanguages
+	Page     uint   // starting from 1
+	PerPage  uint
+
+	Fuzzy  bool // whether terms match with typos
+	Count  bool // whether the total count of matches is needed
+	Facets bool // whether the matches are counted by author and month
+}
+
+// ArticleSearchResult is a page of the articles matching an ArticleSearch.
+type ArticleSearchResult struct {
+	Matches []ArticleMatch
+	Total   *int64         // only when requested by ArticleSearch.Count
+	Facets  *ArticleFacets // only when requested by ArticleSearch.Facets
+}
+
+// ArticleFacets counts the articles matching a search by some of their properties.
+type ArticleFacets struct {
+	Authors []FacetCount `json:"authors"` // by user ID, the most frequent first
+	Months  []FacetCount `json:"months"`  // by month of creation like 2024-01, the oldest first
+}
+
+// FacetCount is how many articles have a value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
 }
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Cursor is a position in a list sorted by (created_at, id).
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+
+	// Backward pages end right before the position instead of starting right after it.
+	Backward bool `json:"b,omitempty"`
+}
+
+// Page is a page of a list paginated with cursors.
+type Page[T any] struct {
+	Items []T
+	Next  *Cursor // nil on the last page
+	Prev  *Cursor // nil on the first page
+}
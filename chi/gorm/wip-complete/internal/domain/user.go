@@ -7,7 +7,34 @@ type User struct {
 
 	Email    string `json:"email"`
 	Password string `json:"-"`
+	IsAdmin  bool   `json:"is_admin"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// Author is the public profile of a user, embedded in the articles they wrote.
+// Articles are public, so the email and admin flag of users are left out.
+type Author struct {
+	ID uint `json:"id"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewAuthor(user User) *Author {
+	return &Author{
+		ID:        user.ID,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// Caller is the user a request is made by, as authenticated by their JWT.
+type Caller struct {
+	UserID  uint
+	IsAdmin bool
+}
+
+// Owns tells whether the caller may change what belongs to the user of userID, admins may change anything.
+func (c Caller) Owns(userID uint) bool {
+	return c.IsAdmin || c.UserID == userID
+}
@@ -2,69 +2,50 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/fixtures"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/testdb"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/listquery"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
-	"github.com/yizeng/gab/chi/gorm/wip-complete/pkg/dockertester"
 )
 
 type ArticleDBTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
+	db *gorm.DB
 
-	articleDAO *dao.ArticleDAO
-}
-
-func (s *ArticleDBTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
+	// template is cloned into db for each test, with the seeded fixtures.
+	template *testdb.Template
+	seeded   fixtures.Set
 
-func (s *ArticleDBTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	articleDAO *dao.ArticleDAO
+	factory    *fixtures.Factory
 }
 
 func (s *ArticleDBTestSuite) SetupTest() {
-	// Run migrations.
-	err := dao.InitTables(s.db)
-	require.NoError(s.T(), err)
-
-	// Seed database.
-	script, err := os.ReadFile("../scripts/seed_db.sql")
-	require.NoError(s.T(), err)
-
-	err = s.db.Exec(string(script)).Error
-	require.NoError(s.T(), err)
+	s.db = s.template.Clone(s.T())
 
 	// Initialize repository.
 	s.articleDAO = dao.NewArticleDAO(s.db)
-}
-
-func (s *ArticleDBTestSuite) TearDownTest() {
-	s.cleanDB()
+	s.factory = fixtures.NewFactory(s.db)
 }
 
 func (s *ArticleDBTestSuite) cleanDB() {
-	script, err := os.ReadFile("../scripts/clean_db.sql")
+	script, err := os.ReadFile(testdb.CleanScript(s.db))
 	require.NoError(s.T(), err)
 
 	err = s.db.Exec(string(script)).Error
@@ -72,58 +53,194 @@ func (s *ArticleDBTestSuite) cleanDB() {
 }
 
 func TestArticleDB(t *testing.T) {
-	suite.Run(t, new(ArticleDBTestSuite))
+	t.Parallel()
+	suite.Run(t, &ArticleDBTestSuite{template: template, seeded: seeded})
+}
+
+// TestArticleDB_SQLite also runs the suite against SQLite when another database is available.
+func TestArticleDB_SQLite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ArticleDBTestSuite{template: sqliteTemplate, seeded: sqliteSeeded})
+}
+
+func (s *ArticleDBTestSuite) sqlite() bool {
+	return s.db.Dialector.Name() == "sqlite"
 }
 
 func (s *ArticleDBTestSuite) TestArticleDB_FindByID() {
+	beta := s.seeded.Articles["beta"]
+
 	result, err := s.articleDAO.FindByID(context.TODO(), 99999)
 	assert.Error(s.T(), gorm.ErrRecordNotFound)
 
-	result, err = s.articleDAO.FindByID(context.TODO(), 999)
+	result, err = s.articleDAO.FindByID(context.TODO(), beta.ID)
 	assert.NoError(s.T(), err)
 
 	assert.NotNil(s.T(), result)
-	assert.EqualValues(s.T(), 123, result.UserID)
-	assert.Equal(s.T(), "seeded title 999", result.Title)
-	assert.Equal(s.T(), "seeded content 999", result.Content)
+	assert.Equal(s.T(), beta.UserID, result.UserID)
+	assert.Equal(s.T(), beta.Title, result.Title)
+	assert.Equal(s.T(), beta.Content, result.Content)
 }
 
 func (s *ArticleDBTestSuite) TestArticleDB_FindAll() {
-	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10)
+	// Unsorted articles come in the order they're stored, which is the order they were seeded in.
+	first, second := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10, listquery.Spec{})
 	assert.NoError(s.T(), err)
 
 	assert.Equal(s.T(), len(result), 2)
-	assert.EqualValues(s.T(), 123, result[0].UserID)
-	assert.Equal(s.T(), "seeded title 999", result[0].Title)
-	assert.Equal(s.T(), "seeded content 999", result[0].Content)
+	assert.Equal(s.T(), first.UserID, result[0].UserID)
+	assert.Equal(s.T(), first.Title, result[0].Title)
+	assert.Equal(s.T(), first.Content, result[0].Content)
 
-	result, err = s.articleDAO.FindAll(context.TODO(), 2, 1)
+	result, err = s.articleDAO.FindAll(context.TODO(), 2, 1, listquery.Spec{})
 	assert.NoError(s.T(), err)
 
 	assert.Equal(s.T(), 1, len(result))
-	assert.EqualValues(s.T(), 123, result[0].UserID)
-	assert.Equal(s.T(), "seeded title 888", result[0].Title)
-	assert.Equal(s.T(), "seeded content 888", result[0].Content)
+	assert.Equal(s.T(), second.UserID, result[0].UserID)
+	assert.Equal(s.T(), second.Title, result[0].Title)
+	assert.Equal(s.T(), second.Content, result[0].Content)
 }
 
 func (s *ArticleDBTestSuite) TestArticleDB_FindAll_Empty() {
 	s.cleanDB()
 
-	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10)
+	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10, listquery.Spec{})
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_FindAfter() {
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	result, err := s.articleDAO.FindAfter(context.TODO(), nil, 10, false, listquery.Spec{})
+	assert.NoError(s.T(), err)
+
+	// The seeded articles were created at the same time, so they're sorted by ID.
+	assert.Equal(s.T(), 2, len(result))
+	assert.Equal(s.T(), alpha.ID, result[0].ID)
+	assert.Equal(s.T(), beta.ID, result[1].ID)
+
+	key := &dao.Keyset{CreatedAt: result[0].CreatedAt, ID: result[0].ID}
+	result, err = s.articleDAO.FindAfter(context.TODO(), key, 10, false, listquery.Spec{})
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), beta.ID, result[0].ID)
+
+	key = &dao.Keyset{CreatedAt: result[0].CreatedAt, ID: result[0].ID}
+	result, err = s.articleDAO.FindAfter(context.TODO(), key, 10, true, listquery.Spec{})
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), alpha.ID, result[0].ID)
+
+	result, err = s.articleDAO.FindAfter(context.TODO(), nil, 1, true, listquery.Spec{})
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), beta.ID, result[0].ID)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_FindAll_Spec() {
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	spec := listquery.Spec{
+		Sorts: []listquery.Sort{{Field: "title", Desc: true}},
+		Filters: []listquery.Filter{
+			{Field: "user_id", Operator: listquery.Eq, Value: int64(alpha.UserID)},
+			{Field: "title", Operator: listquery.Contains, Value: "TITLE"},
+		},
+	}
+	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10, spec)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 2, len(result))
+	assert.Equal(s.T(), beta.Title, result[0].Title)
+	assert.Equal(s.T(), alpha.Title, result[1].Title)
+
+	spec = listquery.Spec{
+		Filters: []listquery.Filter{
+			{Field: "id", Operator: listquery.In, Value: []any{int64(alpha.ID), int64(99999)}},
+		},
+	}
+	result, err = s.articleDAO.FindAll(context.TODO(), 1, 10, spec)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), alpha.ID, result[0].ID)
+
+	// Wildcards are matched literally.
+	spec = listquery.Spec{
+		Filters: []listquery.Filter{{Field: "title", Operator: listquery.Contains, Value: "%"}},
+	}
+	result, err = s.articleDAO.FindAll(context.TODO(), 1, 10, spec)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result)
+
+	spec = listquery.Spec{
+		Filters: []listquery.Filter{{Field: "version; DROP TABLE articles", Operator: listquery.Eq, Value: "1"}},
+	}
+	_, err = s.articleDAO.FindAll(context.TODO(), 1, 10, spec)
+	assert.Error(s.T(), err)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_FindAfter_Spec() {
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	spec := listquery.Spec{
+		Sorts: []listquery.Sort{{Field: "created_at", Desc: true}},
+	}
+	result, err := s.articleDAO.FindAfter(context.TODO(), nil, 1, false, spec)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), beta.ID, result[0].ID)
+
+	key := &dao.Keyset{CreatedAt: result[0].CreatedAt, ID: result[0].ID}
+	result, err = s.articleDAO.FindAfter(context.TODO(), key, 10, false, spec)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), alpha.ID, result[0].ID)
+
+	spec = listquery.Spec{
+		Filters: []listquery.Filter{{Field: "created_at", Operator: listquery.Gte, Value: result[0].CreatedAt.Add(time.Second)}},
+	}
+	result, err = s.articleDAO.FindAfter(context.TODO(), nil, 10, false, spec)
 	assert.NoError(s.T(), err)
 	assert.Empty(s.T(), result)
+
+	count, err := s.articleDAO.Count(context.TODO(), spec)
+	assert.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 0, count)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Count() {
+	result, err := s.articleDAO.Count(context.TODO(), listquery.Spec{})
+	assert.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 2, result)
+
+	s.cleanDB()
+
+	result, err = s.articleDAO.Count(context.TODO(), listquery.Spec{})
+	assert.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 0, result)
 }
 
 func (s *ArticleDBTestSuite) TestArticleDB_Insert() {
+	alpha := s.seeded.Articles["alpha"]
+
 	result, err := s.articleDAO.Insert(context.TODO(), dao.Article{
-		UserID:  999,
-		Title:   "seeded title 99",
+		UserID:  alpha.UserID,
+		Title:   alpha.Title,
 		Content: "duplicated",
 	})
-	assert.Error(s.T(), dao.ErrArticleDuplicated)
+	assert.ErrorIs(s.T(), err, dao.ErrArticleDuplicated)
 
 	result, err = s.articleDAO.Insert(context.TODO(), dao.Article{
-		UserID:  123,
+		UserID:  alpha.UserID,
 		Title:   "new title",
 		Content: "new content",
 	})
@@ -134,20 +251,385 @@ func (s *ArticleDBTestSuite) TestArticleDB_Insert() {
 	assert.Equal(s.T(), "new content", result.Content)
 }
 
+func (s *ArticleDBTestSuite) TestArticleDB_Insert_UnknownUser() {
+	_, err := s.articleDAO.Insert(context.TODO(), dao.Article{
+		UserID:  456,
+		Title:   "new title",
+		Content: "new content",
+	})
+	assert.ErrorIs(s.T(), err, dao.ErrUserNotFound)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Export() {
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	export := func(ctx context.Context, spec listquery.Spec, query string) ([]uint, error) {
+		var ids []uint
+		err := s.articleDAO.Export(ctx, spec, query, "english", func(article dao.Article) error {
+			ids = append(ids, article.ID)
+
+			return nil
+		})
+
+		return ids, err
+	}
+
+	// By id unless sorted otherwise.
+	ids, err := export(context.TODO(), listquery.Spec{}, "")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uint{alpha.ID, beta.ID}, ids)
+
+	ids, err = export(context.TODO(), listquery.Spec{Sorts: []listquery.Sort{{Field: "title", Desc: true}}}, "")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uint{beta.ID, alpha.ID}, ids)
+
+	ids, err = export(context.TODO(), listquery.Spec{}, "beta")
+	if s.sqlite() {
+		// Only Postgres has full-text search.
+		assert.ErrorIs(s.T(), err, dao.ErrSearchUnsupported)
+	} else {
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), []uint{beta.ID}, ids)
+	}
+
+	ids, err = export(context.TODO(), listquery.Spec{Filters: []listquery.Filter{{Field: "user_id", Operator: listquery.Eq, Value: int64(456)}}}, "")
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), ids)
+
+	// The query stops as soon as fn fails.
+	errStop := errors.New("stop")
+	calls := 0
+	err = s.articleDAO.Export(context.TODO(), listquery.Spec{}, "", "english", func(article dao.Article) error {
+		calls++
+
+		return errStop
+	})
+	assert.ErrorIs(s.T(), err, errStop)
+	assert.Equal(s.T(), 1, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = export(ctx, listquery.Spec{}, "")
+	assert.ErrorIs(s.T(), err, context.Canceled)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_WithinTx() {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+	txm := dao.NewTxManager(s.db, dao.TxConfig{})
+	userDAO := dao.NewUserDAO(s.db)
+	author := s.seeded.Users["author"]
+
+	// A failed nested call is rolled back to its savepoint, the rest of the transaction is committed.
+	err := txm.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.articleDAO.Insert(ctx, dao.Article{UserID: author.ID, Title: "committed", Content: "content"})
+		require.NoError(s.T(), err)
+
+		err = txm.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := s.articleDAO.Insert(ctx, dao.Article{UserID: 456, Title: "unknown user", Content: "content"})
+
+			return err
+		})
+		assert.ErrorIs(s.T(), err, dao.ErrUserNotFound)
+
+		return nil
+	})
+	require.NoError(s.T(), err)
+
+	count, err := s.articleDAO.Count(ctx, listquery.Spec{})
+	require.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 3, count)
+
+	// Everything done by the DAOs within a transaction is rolled back together.
+	err = txm.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := userDAO.Insert(ctx, dao.User{Email: "rolled-back@test.com", Password: "password"})
+		require.NoError(s.T(), err)
+
+		_, err = s.articleDAO.Insert(ctx, dao.Article{UserID: user.ID, Title: "rolled back", Content: "content"})
+		require.NoError(s.T(), err)
+
+		return errRollback
+	})
+	assert.ErrorIs(s.T(), err, errRollback)
+
+	count, err = s.articleDAO.Count(ctx, listquery.Spec{})
+	require.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 3, count)
+
+	_, err = userDAO.FindByEmail(ctx, "rolled-back@test.com")
+	assert.ErrorIs(s.T(), err, dao.ErrUserNotFound)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_WithinTx_Retry() {
+	// SQLite transactions don't fail to serialize, writers wait for each other.
+	testdb.SkipUnlessPostgres(s.T(), s.db)
+
+	ctx := context.Background()
+	txm := dao.NewTxManager(s.db, dao.TxConfig{Isolation: sql.LevelSerializable, MaxRetries: 3, RetryBackoff: time.Millisecond})
+	author := s.seeded.Users["author"]
+
+	// Both transactions count the articles before inserting one, so only the first to commit can succeed
+	// when they overlap, and the other one fails to serialize. It's run again and succeeds then.
+	var (
+		attempts atomic.Int32
+		counted  sync.WaitGroup
+		wg       sync.WaitGroup
+	)
+	counted.Add(2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			first := true
+			err := txm.WithinTx(ctx, func(ctx context.Context) error {
+				attempts.Add(1)
+
+				count, err := s.articleDAO.Count(ctx, listquery.Spec{})
+				if err != nil {
+					return err
+				}
+				if first {
+					first = false
+					counted.Done()
+					counted.Wait()
+				}
+
+				_, err = s.articleDAO.Insert(ctx, dao.Article{
+					UserID:  author.ID,
+					Title:   fmt.Sprintf("title %d after %d articles", i, count),
+					Content: "content",
+				})
+
+				return err
+			})
+			assert.NoError(s.T(), err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.EqualValues(s.T(), 3, attempts.Load())
+
+	count, err := s.articleDAO.Count(ctx, listquery.Spec{})
+	require.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 4, count)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_PreloadUser() {
+	// An article of another author, so the articles aren't all written by the same user.
+	author := s.seeded.Users["author"]
+	otherAuthor, err := s.factory.User(context.TODO())
+	require.NoError(s.T(), err)
+	other, err := s.factory.Article(context.TODO(), fixtures.WrittenBy(otherAuthor))
+	require.NoError(s.T(), err)
+	emails := map[uint]string{author.ID: author.Email, otherAuthor.ID: otherAuthor.Email}
+
+	queries := &queryCounter{Interface: logger.Discard}
+	articleDAO := dao.NewArticleDAO(s.db.Session(&gorm.Session{Logger: queries}))
+
+	assertUsers := func(articles []dao.Article) {
+		s.T().Helper()
+
+		require.Equal(s.T(), 3, len(articles))
+		for _, article := range articles {
+			require.NotNil(s.T(), article.User)
+			assert.Equal(s.T(), article.UserID, article.User.ID)
+			assert.Equal(s.T(), emails[article.UserID], article.User.Email)
+		}
+	}
+
+	// One query loads the articles and another one all of their authors.
+	result, err := articleDAO.FindAll(context.TODO(), 1, 10, listquery.Spec{}, dao.ArticleUser)
+	require.NoError(s.T(), err)
+	assertUsers(result)
+	assert.Equal(s.T(), 2, queries.reset())
+
+	result, err = articleDAO.FindAfter(context.TODO(), nil, 10, false, listquery.Spec{}, dao.ArticleUser)
+	require.NoError(s.T(), err)
+	assertUsers(result)
+	assert.Equal(s.T(), 2, queries.reset())
+
+	article, err := articleDAO.FindByID(context.TODO(), other.ID, dao.ArticleUser)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), article.User)
+	assert.Equal(s.T(), otherAuthor.Email, article.User.Email)
+
+	// Authors aren't loaded unless asked for.
+	article, err = articleDAO.FindByID(context.TODO(), other.ID)
+	require.NoError(s.T(), err)
+	assert.Nil(s.T(), article.User)
+}
+
 func (s *ArticleDBTestSuite) TestArticleDB_Search() {
-	result, err := s.articleDAO.Search(context.TODO(), "999", "")
+	testdb.SkipUnlessPostgres(s.T(), s.db)
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	result, err := s.articleDAO.Search(context.TODO(), "Title beta", "english", 1, 10)
 	assert.NoError(s.T(), err)
 
-	assert.NotNil(s.T(), result)
-	assert.EqualValues(s.T(), 123, result[0].UserID)
-	assert.Equal(s.T(), "seeded title 999", result[0].Title)
-	assert.Equal(s.T(), "seeded content 999", result[0].Content)
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), beta.UserID, result[0].UserID)
+	assert.Equal(s.T(), beta.Title, result[0].Title)
+	assert.Equal(s.T(), beta.Content, result[0].Content)
+	assert.Equal(s.T(), "seeded content <b>beta</b>", result[0].Headline)
+	assert.Greater(s.T(), result[0].Rank, float32(0))
 
-	result, err = s.articleDAO.Search(context.TODO(), "", "999")
+	result, err = s.articleDAO.Search(context.TODO(), `"seeded content" -beta`, "english", 1, 10)
 	assert.NoError(s.T(), err)
 
-	assert.NotNil(s.T(), result)
-	assert.EqualValues(s.T(), 123, result[0].UserID)
-	assert.Equal(s.T(), "seeded title 999", result[0].Title)
-	assert.Equal(s.T(), "seeded content 999", result[0].Content)
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), alpha.ID, result[0].ID)
+
+	result, err = s.articleDAO.Search(context.TODO(), "no-title", "english", 1, 10)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Search_EscapedHeadline() {
+	testdb.SkipUnlessPostgres(s.T(), s.db)
+	alpha := s.seeded.Articles["alpha"]
+
+	_, err := s.articleDAO.Insert(context.TODO(), dao.Article{
+		UserID:  alpha.UserID,
+		Title:   "markup",
+		Content: `<script>alert("xss")</script> & injected markup`,
+	})
+	require.NoError(s.T(), err)
+
+	// The content is escaped, only the marks of the matches are HTML.
+	result, err := s.articleDAO.Search(context.TODO(), "injected", "english", 1, 10)
+	assert.NoError(s.T(), err)
+
+	require.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), "&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt; &amp; <b>injected</b> markup", result[0].Headline)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Search_Unsupported() {
+	if !s.sqlite() {
+		s.T().Skip("only databases without full-text search reject searches")
+	}
+
+	_, err := s.articleDAO.Search(context.TODO(), "beta", "english", 1, 10)
+	assert.ErrorIs(s.T(), err, dao.ErrSearchUnsupported)
+
+	_, err = s.articleDAO.CountMatches(context.TODO(), "beta", "english")
+	assert.ErrorIs(s.T(), err, dao.ErrSearchUnsupported)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Search_Ranking() {
+	testdb.SkipUnlessPostgres(s.T(), s.db)
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	article, err := s.articleDAO.Insert(context.TODO(), dao.Article{
+		UserID:  alpha.UserID,
+		Title:   "contents",
+		Content: "seeded contents",
+	})
+	require.NoError(s.T(), err)
+
+	// Matches in titles weigh more than those in contents, ties are broken by ID.
+	result, err := s.articleDAO.Search(context.TODO(), "content", "english", 1, 10)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 3, len(result))
+	assert.Equal(s.T(), article.ID, result[0].ID)
+	assert.Equal(s.T(), alpha.ID, result[1].ID)
+	assert.Equal(s.T(), beta.ID, result[2].ID)
+	assert.Greater(s.T(), result[0].Rank, result[1].Rank)
+
+	result, err = s.articleDAO.Search(context.TODO(), "content", "english", 2, 2)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.Equal(s.T(), beta.ID, result[0].ID)
+
+	count, err := s.articleDAO.CountMatches(context.TODO(), "content", "english")
+	assert.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 3, count)
+
+	// The simple configuration doesn't stem words.
+	count, err = s.articleDAO.CountMatches(context.TODO(), "content", "simple")
+	assert.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 2, count)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Update() {
+	alpha, beta := s.seeded.Articles["alpha"], s.seeded.Articles["beta"]
+
+	_, err := s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      99999,
+		Title:   "new title",
+		Content: "new content",
+	})
+	assert.ErrorIs(s.T(), err, dao.ErrArticleNotFound)
+
+	_, err = s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      beta.ID,
+		Title:   alpha.Title,
+		Content: "duplicated",
+	})
+	assert.ErrorIs(s.T(), err, dao.ErrArticleDuplicated)
+
+	result, err := s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      beta.ID,
+		Title:   "new title",
+		Content: "new content",
+		Version: 1,
+	})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "new title", result.Title)
+	assert.Equal(s.T(), "new content", result.Content)
+	assert.EqualValues(s.T(), 2, result.Version)
+
+	// The version has moved on, so the stale update is rejected.
+	_, err = s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      beta.ID,
+		Title:   "stale title",
+		Content: "stale content",
+		Version: 1,
+	})
+	assert.ErrorIs(s.T(), err, dao.ErrArticleModified)
+
+	result, err = s.articleDAO.FindByID(context.TODO(), beta.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "new title", result.Title)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Delete() {
+	beta := s.seeded.Articles["beta"]
+
+	err := s.articleDAO.Delete(context.TODO(), 99999, 0)
+	assert.ErrorIs(s.T(), err, dao.ErrArticleNotFound)
+
+	err = s.articleDAO.Delete(context.TODO(), beta.ID, 2)
+	assert.ErrorIs(s.T(), err, dao.ErrArticleModified)
+
+	err = s.articleDAO.Delete(context.TODO(), beta.ID, 1)
+	assert.NoError(s.T(), err)
+
+	_, err = s.articleDAO.FindByID(context.TODO(), beta.ID)
+	assert.ErrorIs(s.T(), err, dao.ErrArticleNotFound)
+}
+
+// queryCounter is a GORM logger counting the SQL statements it's traced with.
+type queryCounter struct {
+	logger.Interface
+
+	queries int
+}
+
+func (c *queryCounter) LogMode(logger.LogLevel) logger.Interface {
+	return c
+}
+
+func (c *queryCounter) Trace(context.Context, time.Time, func() (string, int64), error) {
+	c.queries++
+}
+
+// reset returns how many statements were counted since the last reset.
+func (c *queryCounter) reset() int {
+	queries := c.queries
+	c.queries = 0
+
+	return queries
 }
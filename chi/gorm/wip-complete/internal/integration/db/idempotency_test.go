@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/testdb"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/idempotency"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
+)
+
+type IdempotencyDBTestSuite struct {
+	suite.Suite
+
+	db *gorm.DB
+
+	// template is cloned into db for each test.
+	template *testdb.Template
+
+	idempotencyDAO *dao.IdempotencyDAO
+}
+
+func (s *IdempotencyDBTestSuite) SetupTest() {
+	s.db = s.template.Clone(s.T())
+
+	// Initialize DAO.
+	s.idempotencyDAO = dao.NewIdempotencyDAO(s.db)
+}
+
+func TestIdempotencyDB(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &IdempotencyDBTestSuite{template: template})
+}
+
+// TestIdempotencyDB_SQLite also runs the suite against SQLite when another database is available.
+func TestIdempotencyDB_SQLite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &IdempotencyDBTestSuite{template: sqliteTemplate})
+}
+
+func (s *IdempotencyDBTestSuite) TestIdempotencyDB_Reserve() {
+	reserved, _, err := s.idempotencyDAO.Reserve(context.TODO(), "user:1:key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), reserved)
+
+	// The second request finds the first one in progress.
+	reserved, record, err := s.idempotencyDAO.Reserve(context.TODO(), "user:1:key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), reserved)
+	assert.Equal(s.T(), idempotency.Record{RequestHash: "hash"}, record)
+
+	// Keys of other clients are distinct.
+	reserved, _, err = s.idempotencyDAO.Reserve(context.TODO(), "user:2:key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), reserved)
+}
+
+func (s *IdempotencyDBTestSuite) TestIdempotencyDB_Complete() {
+	reserved, _, err := s.idempotencyDAO.Reserve(context.TODO(), "key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved)
+
+	want := idempotency.Record{
+		RequestHash: "hash",
+		StatusCode:  http.StatusCreated,
+		Header:      http.Header{"Content-Type": {"application/json"}},
+		Body:        []byte(`{"id":1}`),
+	}
+	err = s.idempotencyDAO.Complete(context.TODO(), "key", want, time.Hour)
+	require.NoError(s.T(), err)
+
+	// Retries get the stored response.
+	reserved, record, err := s.idempotencyDAO.Reserve(context.TODO(), "key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), reserved)
+
+	want.Completed = true
+	assert.Equal(s.T(), want, record)
+}
+
+func (s *IdempotencyDBTestSuite) TestIdempotencyDB_Release() {
+	reserved, _, err := s.idempotencyDAO.Reserve(context.TODO(), "key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved)
+
+	err = s.idempotencyDAO.Release(context.TODO(), "key")
+	require.NoError(s.T(), err)
+
+	// The request can be retried, even with another payload.
+	reserved, _, err = s.idempotencyDAO.Reserve(context.TODO(), "key", "other hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), reserved)
+
+	// Releasing unknown keys is a no-op.
+	err = s.idempotencyDAO.Release(context.TODO(), "unknown")
+	assert.NoError(s.T(), err)
+}
+
+func (s *IdempotencyDBTestSuite) TestIdempotencyDB_Expired() {
+	reserved, _, err := s.idempotencyDAO.Reserve(context.TODO(), "key", "hash", time.Hour)
+	require.NoError(s.T(), err)
+	require.True(s.T(), reserved)
+
+	err = s.idempotencyDAO.Complete(context.TODO(), "key", idempotency.Record{
+		RequestHash: "hash",
+		StatusCode:  http.StatusCreated,
+	}, -time.Second)
+	require.NoError(s.T(), err)
+
+	// The expired record is replaced by a new reservation.
+	reserved, _, err = s.idempotencyDAO.Reserve(context.TODO(), "key", "other hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), reserved)
+
+	reserved, record, err := s.idempotencyDAO.Reserve(context.TODO(), "key", "other hash", time.Hour)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), reserved)
+	assert.Equal(s.T(), idempotency.Record{RequestHash: "other hash"}, record)
+}
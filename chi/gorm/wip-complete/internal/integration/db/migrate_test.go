@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/testdb"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/migrate"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
+)
+
+type MigrateDBTestSuite struct {
+	suite.Suite
+
+	db *gorm.DB
+
+	migrator *migrate.Migrator
+}
+
+func (s *MigrateDBTestSuite) SetupSuite() {
+	// Migrators taking turns rely on the locks of PostgreSQL.
+	s.db = testdb.Open(s.T(), "postgres")
+
+	var err error
+	s.migrator, err = dao.NewMigrator(s.db)
+	require.NoError(s.T(), err)
+}
+
+// SetupTest rolls back all the migrations, so each test starts from an empty database.
+func (s *MigrateDBTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	statuses, err := s.migrator.Status(ctx)
+	require.NoError(s.T(), err)
+
+	_, err = s.migrator.Down(ctx, len(statuses))
+	require.NoError(s.T(), err)
+}
+
+func TestMigrateDB(t *testing.T) {
+	suite.Run(t, new(MigrateDBTestSuite))
+}
+
+func (s *MigrateDBTestSuite) TestMigrateDB_UpAndDown() {
+	ctx := context.Background()
+
+	// Concurrent migrators take turns, only one of them applies each migration.
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		applied []migrate.Migration
+	)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			migrations, err := s.migrator.Up(ctx)
+			assert.NoError(s.T(), err)
+
+			mu.Lock()
+			applied = append(applied, migrations...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	statuses, err := s.migrator.Status(ctx)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), applied, len(statuses))
+	for _, status := range statuses {
+		assert.False(s.T(), status.Pending(), status.Name)
+	}
+	assert.True(s.T(), s.db.Migrator().HasTable(&dao.Article{}))
+
+	// Rolling back all the migrations drops all the tables.
+	rolledBack, err := s.migrator.Down(ctx, len(statuses))
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), rolledBack, len(statuses))
+	assert.Equal(s.T(), statuses[0].Version, rolledBack[len(rolledBack)-1].Version)
+	assert.False(s.T(), s.db.Migrator().HasTable(&dao.User{}))
+
+	statuses, err = s.migrator.Status(ctx)
+	require.NoError(s.T(), err)
+	for _, status := range statuses {
+		assert.True(s.T(), status.Pending(), status.Name)
+	}
+
+	// And they can be applied again.
+	applied, err = s.migrator.Up(ctx)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), applied, len(statuses))
+}
+
+// baselineUser and baselineArticle are the models databases were created from by AutoMigrate,
+// before there were migrations.
+type baselineUser struct {
+	ID uint `gorm:"primaryKey"`
+
+	Email    string `gorm:"unique;not null"`
+	Password string `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineArticle struct {
+	ID uint `gorm:"primaryKey"`
+
+	UserID  uint   `gorm:"uniqueIndex:idx_user_id_title,not null"`
+	Title   string `gorm:"uniqueIndex:idx_user_id_title,not null"`
+	Content string `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (baselineArticle) TableName() string { return "articles" }
+
+func (s *MigrateDBTestSuite) TestMigrateDB_AdoptBaseline() {
+	ctx := context.Background()
+
+	require.NoError(s.T(), s.db.AutoMigrate(&baselineUser{}, &baselineArticle{}))
+	user := baselineUser{Email: "baseline@example.com", Password: "secret"}
+	require.NoError(s.T(), s.db.Create(&user).Error)
+	require.NoError(s.T(), s.db.Create(&baselineArticle{UserID: user.ID, Title: "Baseline", Content: "Adopted"}).Error)
+
+	applied, err := s.migrator.Up(ctx)
+	require.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), applied)
+
+	// The tables are adopted with what AutoMigrate didn't create, and keep their rows.
+	migrator := s.db.Migrator()
+	assert.True(s.T(), migrator.HasColumn(&dao.User{}, "is_admin"))
+	assert.True(s.T(), migrator.HasColumn(&dao.Article{}, "version"))
+	assert.True(s.T(), migrator.HasColumn("articles", "search_vector"))
+	assert.True(s.T(), migrator.HasConstraint("users", "uni_users_email"))
+	assert.True(s.T(), migrator.HasConstraint("articles", "fk_articles_user"))
+	for _, index := range []string{"idx_user_id_title", "idx_articles_created_at_id", "idx_articles_search_vector"} {
+		assert.True(s.T(), migrator.HasIndex("articles", index), index)
+	}
+
+	var article dao.Article
+	require.NoError(s.T(), s.db.First(&article, "title = ?", "Baseline").Error)
+	assert.Equal(s.T(), user.ID, article.UserID)
+	assert.EqualValues(s.T(), 1, article.Version)
+
+	var matches int64
+	err = s.db.Table("articles").Where("search_vector @@ plainto_tsquery('english', ?)", "adopted").Count(&matches).Error
+	require.NoError(s.T(), err)
+	assert.EqualValues(s.T(), 1, matches)
+}
@@ -2,106 +2,78 @@ package db
 
 import (
 	"context"
-	"os"
 	"testing"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/fixtures"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/testdb"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
-	"github.com/yizeng/gab/chi/gorm/wip-complete/pkg/dockertester"
 )
 
 type UserDBTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
+	db *gorm.DB
 
-	userDAO *dao.UserDAO
-}
-
-func (s *UserDBTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
+	// template is cloned into db for each test, with the seeded fixtures.
+	template *testdb.Template
+	seeded   fixtures.Set
 
-	s.db = db
-}
-
-func (s *UserDBTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	userDAO *dao.UserDAO
 }
 
 func (s *UserDBTestSuite) SetupTest() {
-	// Run migrations.
-	err := dao.InitTables(s.db)
-	require.NoError(s.T(), err)
-
-	// Seed database.
-	script, err := os.ReadFile("../scripts/seed_db.sql")
-	require.NoError(s.T(), err)
-
-	err = s.db.Exec(string(script)).Error
-	require.NoError(s.T(), err)
+	s.db = s.template.Clone(s.T())
 
 	// Initialize DAO.
 	s.userDAO = dao.NewUserDAO(s.db)
 }
 
-func (s *UserDBTestSuite) TearDownTest() {
-	s.cleanDB()
-}
-
-func (s *UserDBTestSuite) cleanDB() {
-	script, err := os.ReadFile("../scripts/clean_db.sql")
-	require.NoError(s.T(), err)
-
-	err = s.db.Exec(string(script)).Error
-	require.NoError(s.T(), err)
+func TestUserDB(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &UserDBTestSuite{template: template, seeded: seeded})
 }
 
-func TestUserDB(t *testing.T) {
-	suite.Run(t, new(UserDBTestSuite))
+// TestUserDB_SQLite also runs the suite against SQLite when another database is available.
+func TestUserDB_SQLite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &UserDBTestSuite{template: sqliteTemplate, seeded: sqliteSeeded})
 }
 
 func (s *UserDBTestSuite) TestUserDB_FindByID() {
+	author := s.seeded.Users["author"]
+
 	result, err := s.userDAO.FindByID(context.TODO(), 12345)
 	assert.Error(s.T(), gorm.ErrRecordNotFound)
 
-	result, err = s.userDAO.FindByID(context.TODO(), 123)
+	result, err = s.userDAO.FindByID(context.TODO(), author.ID)
 	assert.NoError(s.T(), err)
 
 	assert.NotNil(s.T(), result)
-	assert.EqualValues(s.T(), 123, result.ID)
-	assert.Equal(s.T(), "123@test.com", result.Email)
+	assert.Equal(s.T(), author.ID, result.ID)
+	assert.Equal(s.T(), author.Email, result.Email)
 }
 
 func (s *UserDBTestSuite) TestUserDB_FindByEmail() {
+	author := s.seeded.Users["author"]
+
 	result, err := s.userDAO.FindByEmail(context.TODO(), "non-exist@test.com")
 	assert.Error(s.T(), gorm.ErrRecordNotFound)
 
-	result, err = s.userDAO.FindByEmail(context.TODO(), "123@test.com")
+	result, err = s.userDAO.FindByEmail(context.TODO(), author.Email)
 	assert.NoError(s.T(), err)
 
 	assert.NotNil(s.T(), result)
-	assert.EqualValues(s.T(), 123, result.ID)
-	assert.Equal(s.T(), "123@test.com", result.Email)
+	assert.Equal(s.T(), author.ID, result.ID)
+	assert.Equal(s.T(), author.Email, result.Email)
 }
 
 func (s *UserDBTestSuite) TestUserDB_Insert() {
 	result, err := s.userDAO.Insert(context.TODO(), dao.User{
-		Email:    "123@test.com",
+		Email:    s.seeded.Users["author"].Email,
 		Password: "any",
 	})
 	assert.Error(s.T(), dao.ErrUserEmailExists)
@@ -1,14 +1,13 @@
 package e2e
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
-	"os"
 	"strings"
 	"testing"
 
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -19,91 +18,54 @@ import (
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/integration/fixtures"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
-	"github.com/yizeng/gab/chi/gorm/wip-complete/pkg/dockertester"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
 )
 
-var (
-	testSignupReq = request.SignupRequest{
-		Email:           "000@test.com",
-		Password:        "hello@123",
-		ConfirmPassword: "hello@123",
-	}
-	testLoginReq = request.SignupRequest{
-		Email:    "123@test.com",
-		Password: "hello@123",
-	}
-)
+var testSignupReq = request.SignupRequest{
+	Email:           "000@test.com",
+	Password:        "hello@123",
+	ConfirmPassword: "hello@123",
+}
 
 type AuthHandlerTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
-	server   *api.Server
+	db     *gorm.DB
+	server *api.Server
 }
 
 func (s *AuthHandlerTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *AuthHandlerTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	skipUnlessResolvable(s.T(), "test.com")
 }
 
 func (s *AuthHandlerTestSuite) SetupTest() {
-	// Run migrations.
-	err := dao.InitTables(s.db)
-	require.NoError(s.T(), err)
-
-	// Seed database.
-	script, err := os.ReadFile("../scripts/seed_db.sql")
-	require.NoError(s.T(), err)
-
-	err = s.db.Exec(string(script)).Error
-	require.NoError(s.T(), err)
+	s.db = template.Clone(s.T())
 
 	// Create API server.
 	s.server = api.NewServer(&config.AppConfig{
-		API:      &config.APIConfig{},
+		API: &config.APIConfig{
+			JWTSigningKey: jwtSigningKey,
+		},
 		Postgres: &config.PostgresConfig{},
-	}, s.db)
-}
-
-func (s *AuthHandlerTestSuite) TearDownTest() {
-	s.cleanDB()
-}
-
-func (s *AuthHandlerTestSuite) cleanDB() {
-	script, err := os.ReadFile("../scripts/clean_db.sql")
-	require.NoError(s.T(), err)
-
-	err = s.db.Exec(string(script)).Error
-	require.NoError(s.T(), err)
+	}, s.db, nil)
 }
 
 func (s *AuthHandlerTestSuite) createDBError() {
-	// Create/fake a DB error by dropping the users table.
-	err := s.db.Exec(`DROP TABLE "users"`).Error
+	// Create/fake a DB error by dropping the users table, along with the foreign key of articles.
+	err := s.db.Migrator().DropTable(&dao.User{})
 	require.NoError(s.T(), err)
 }
 
 func TestAuthHandler(t *testing.T) {
+	t.Parallel()
 	suite.Run(t, new(AuthHandlerTestSuite))
 }
 
 func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
+	author := seeded.Users["author"]
+
 	type args struct {
 		buildReqBody func() string
 	}
@@ -153,7 +115,15 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("confirm_password: cannot be blank; email: cannot be blank; password: cannot be blank.")),
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "confirm_password: cannot be blank; email: cannot be blank; password: cannot be blank.",
+					Errors: []response.FieldError{
+						{Field: "confirm_password", Code: "validation_required"},
+						{Field: "email", Code: "validation_required"},
+						{Field: "password", Code: "validation_required"},
+					},
+				},
 			},
 			wantErr: true,
 		},
@@ -175,7 +145,13 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("the password must be at least 8 characters and contain 1 letter, 1 number and 1 symbol")),
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "password: the password must be at least 8 characters and contain 1 letter, 1 number and 1 symbol.",
+					Errors: []response.FieldError{
+						{Field: "password", Code: "validation_password_too_weak"},
+					},
+				},
 			},
 			wantErr: true,
 		},
@@ -197,7 +173,13 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("confirm password doesn't match the password")),
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "confirm_password: confirm password doesn't match the password.",
+					Errors: []response.FieldError{
+						{Field: "confirm_password", Code: "validation_password_mismatch"},
+					},
+				},
 			},
 			wantErr: true,
 		},
@@ -207,7 +189,7 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 			args: args{
 				buildReqBody: func() string {
 					body, err := json.Marshal(request.SignupRequest{
-						Email:           "123@test.com",
+						Email:           author.Email,
 						Password:        "Test!123",
 						ConfirmPassword: "Test!123",
 					})
@@ -219,7 +201,7 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("user already exists")),
+				err:      response.ErrBadRequest(service.ErrUserEmailExists),
 			},
 			wantErr: true,
 		},
@@ -234,7 +216,7 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("unexpected EOF")),
+				err:      response.ErrBadRequest(&request.SyntaxError{Line: 1, Column: 2, Msg: "unexpected end of JSON input"}),
 			},
 			wantErr: true,
 		},
@@ -280,8 +262,9 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
-				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
 			} else {
 				var result domain.User
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
@@ -295,6 +278,8 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleSignup() {
 }
 
 func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin() {
+	author := seeded.Users["author"]
+
 	type args struct {
 		buildReqBody func() string
 	}
@@ -315,18 +300,17 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin() {
 			setup: func() {},
 			args: args{
 				buildReqBody: func() string {
-					body, err := json.Marshal(testLoginReq)
+					body, err := json.Marshal(request.LoginRequest{
+						Email:    author.Email,
+						Password: author.Password,
+					})
 					require.NoError(s.T(), err)
 
 					return string(body)
 				},
 			},
 			want: want{
-				user: domain.User{
-					ID:       123,
-					Email:    "123@test.com",
-					Password: "Test@123",
-				},
+				user:     author,
 				respCode: http.StatusOK,
 				err:      nil,
 			},
@@ -346,7 +330,14 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("email: cannot be blank; password: cannot be blank.")),
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "email: cannot be blank; password: cannot be blank.",
+					Errors: []response.FieldError{
+						{Field: "email", Code: "validation_required"},
+						{Field: "password", Code: "validation_required"},
+					},
+				},
 			},
 			wantErr: true,
 		},
@@ -403,7 +394,7 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin() {
 			want: want{
 				user:     domain.User{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("unexpected EOF")),
+				err:      response.ErrBadRequest(&request.SyntaxError{Line: 1, Column: 2, Msg: "unexpected end of JSON input"}),
 			},
 			wantErr: true,
 		},
@@ -449,8 +440,9 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin() {
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
-				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
 			} else {
 				var result response.LoginResponse
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
@@ -463,3 +455,36 @@ func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin() {
 		})
 	}
 }
+
+func (s *AuthHandlerTestSuite) TestAuthHandler_HandleLogin_Admin() {
+	set, err := fixtures.NewFactory(s.db).Load(context.TODO(), "testdata/admin.yml")
+	require.NoError(s.T(), err)
+
+	login := func(user domain.User) string {
+		body, err := json.Marshal(request.LoginRequest{Email: user.Email, Password: user.Password})
+		require.NoError(s.T(), err)
+
+		req, err := http.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(string(body)))
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, s.server)
+		require.Equal(s.T(), http.StatusOK, resp.Code)
+
+		var result response.LoginResponse
+		require.NoError(s.T(), json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(s.T(), user.IsAdmin, result.User.IsAdmin)
+
+		return result.Token
+	}
+	getLogLevel := func(token string) int {
+		req, err := http.NewRequest("GET", "/api/v1/admin/log/level", nil)
+		require.NoError(s.T(), err)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		return executeRequest(req, s.server).Code
+	}
+
+	// Only admins get through to the admin endpoints.
+	assert.Equal(s.T(), http.StatusOK, getLogLevel(login(set.Users["admin"])))
+	assert.Equal(s.T(), http.StatusForbidden, getLogLevel(login(set.Users["member"])))
+}
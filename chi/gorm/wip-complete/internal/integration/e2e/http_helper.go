@@ -1,12 +1,23 @@
 package e2e
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/jwthelper"
 )
 
+// jwtSigningKey signs the JWTs of the suites.
+const jwtSigningKey = "test_key"
+
 // executeRequest, creates a new ResponseRecorder
 // then executes the request by calling ServeHTTP in the router
 // after which the handler writes the response to the response recorder
@@ -17,3 +28,38 @@ func executeRequest(req *http.Request, s *api.Server) *httptest.ResponseRecorder
 
 	return rr
 }
+
+// skipUnlessResolvable skips tests of requests with emails at host, whose domain is looked up
+// by the validation of emails, so they can't be validated offline.
+func skipUnlessResolvable(t *testing.T, host string) {
+	t.Helper()
+
+	if _, err := net.LookupIP(host); err != nil {
+		t.Skipf("emails at %v can't be validated without DNS: %v", host, err)
+	}
+}
+
+// authorize signs req with a JWT of user, which the servers of the suites accept.
+func authorize(t *testing.T, req *http.Request, user domain.User) {
+	t.Helper()
+
+	token, err := jwthelper.GenerateToken([]byte(jwtSigningKey), user.ID, req.Header.Get("User-Agent"), user.IsAdmin)
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// assertFieldErrors compares the fields and codes of validation errors.
+// Messages are covered by the problem detail and params lose their Go types in JSON.
+func assertFieldErrors(t *testing.T, want, got []response.FieldError) {
+	t.Helper()
+
+	if !assert.Equal(t, len(want), len(got)) {
+		return
+	}
+
+	for i := range want {
+		assert.Equal(t, want[i].Field, got[i].Field)
+		assert.Equal(t, want[i].Code, got[i].Code)
+	}
+}
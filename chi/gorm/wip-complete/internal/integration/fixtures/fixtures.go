@@ -0,0 +1,127 @@
+// Package fixtures creates users and articles in test databases through the DAOs,
+// either one at a time with a Factory or as sets described in YAML files.
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dchest/uniuri"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
+)
+
+// sequence numbers the generated values. It's shared by all the factories of a test binary,
+// so the values don't collide with the ones the template databases were seeded with.
+var sequence atomic.Uint64
+
+// Factory creates users and articles with generated values, which tests override as they need.
+type Factory struct {
+	userDAO    *dao.UserDAO
+	articleDAO *dao.ArticleDAO
+}
+
+func NewFactory(db *gorm.DB) *Factory {
+	return &Factory{
+		userDAO:    dao.NewUserDAO(db),
+		articleDAO: dao.NewArticleDAO(db),
+	}
+}
+
+// User creates a user, the fields that aren't overridden are generated.
+// The password of the returned user is in plaintext, so tests can log in with it, only its hash is stored.
+func (f *Factory) User(ctx context.Context, overrides ...func(*domain.User)) (domain.User, error) {
+	n := sequence.Add(1)
+	user := domain.User{
+		Email:    fmt.Sprintf("user%d@test.com", n),
+		Password: fmt.Sprintf("Pass@%d%s", n, uniuri.NewLen(8)),
+	}
+	for _, override := range overrides {
+		override(&user)
+	}
+
+	// The minimum cost is as good as any for tests, and much faster.
+	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.MinCost)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("bcrypt.GenerateFromPassword -> %w", err)
+	}
+
+	created, err := f.userDAO.Insert(ctx, dao.User{
+		ID:        user.ID,
+		Email:     user.Email,
+		Password:  string(hash),
+		IsAdmin:   user.IsAdmin,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		return domain.User{}, fmt.Errorf("f.userDAO.Insert -> %w", err)
+	}
+
+	return domain.User{
+		ID:        created.ID,
+		Email:     created.Email,
+		Password:  user.Password,
+		IsAdmin:   created.IsAdmin,
+		CreatedAt: created.CreatedAt,
+		UpdatedAt: created.UpdatedAt,
+	}, nil
+}
+
+// Article creates an article, the fields that aren't overridden are generated.
+// It's written by a new user unless its author is overridden with WrittenBy, the author is returned along with it.
+func (f *Factory) Article(ctx context.Context, overrides ...func(*domain.Article)) (domain.Article, error) {
+	n := sequence.Add(1)
+	article := domain.Article{
+		Title:   fmt.Sprintf("article %d", n),
+		Content: fmt.Sprintf("content of article %d", n),
+	}
+	for _, override := range overrides {
+		override(&article)
+	}
+
+	if article.UserID == 0 {
+		author, err := f.User(ctx)
+		if err != nil {
+			return domain.Article{}, err
+		}
+
+		article.UserID = author.ID
+		article.Author = domain.NewAuthor(author)
+	}
+
+	created, err := f.articleDAO.Insert(ctx, dao.Article{
+		ID:        article.ID,
+		UserID:    article.UserID,
+		Title:     article.Title,
+		Content:   article.Content,
+		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+	})
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("f.articleDAO.Insert -> %w", err)
+	}
+
+	return domain.Article{
+		ID:        created.ID,
+		UserID:    created.UserID,
+		Title:     created.Title,
+		Content:   created.Content,
+		Version:   created.Version,
+		CreatedAt: created.CreatedAt,
+		UpdatedAt: created.UpdatedAt,
+		Author:    article.Author,
+	}, nil
+}
+
+// WrittenBy overrides the author of an article.
+func WrittenBy(author domain.User) func(*domain.Article) {
+	return func(article *domain.Article) {
+		article.UserID = author.ID
+		article.Author = domain.NewAuthor(author)
+	}
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	traceIDKey
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID,
+// so that loggers further down the call chain can attach it to every entry.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// WithTraceID returns a copy of ctx carrying the trace ID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// FieldsFromContext returns the request/trace ID fields found in ctx.
+func FieldsFromContext(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if requestID, ok := ctx.Value(requestIDKey).(string); ok && requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if traceID, ok := ctx.Value(traceIDKey).(string); ok && traceID != "" {
+		fields = append(fields, zap.String("trace_id", traceID))
+	}
+
+	return fields
+}
@@ -1,28 +1,122 @@
 package logger
 
 import (
+	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
 )
 
-func Init(environment string) error {
-	logger, err := zap.NewProduction()
+// level is shared by the global logger, so it can be changed at runtime without a restart.
+var level = zap.NewAtomicLevel()
+
+func Init(conf *config.LogConfig, environment string) error {
+	isDevelopment := strings.EqualFold(environment, "development")
+	if conf == nil {
+		conf = &config.LogConfig{}
+	}
+
+	if err := SetLevel(withDefault(conf.Level, isDevelopment, "debug", "info")); err != nil {
+		return err
+	}
+
+	encoder, err := newEncoder(withDefault(conf.Encoding, isDevelopment, "console", "json"), isDevelopment)
 	if err != nil {
 		return err
 	}
 
-	if strings.EqualFold(environment, "development") {
-		logger, err = zap.NewDevelopment()
+	outputPaths := conf.OutputPaths
+	if len(outputPaths) == 0 {
+		outputPaths = []string{"stderr"}
+	}
+	writers := make([]zapcore.WriteSyncer, 0, len(outputPaths))
+	for _, path := range outputPaths {
+		writers = append(writers, newWriter(path, conf))
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(writers...), level)
+	if conf.SamplingInitial > 0 || conf.SamplingThereafter > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, conf.SamplingInitial, conf.SamplingThereafter)
+	}
 
-		if err != nil {
-			return err
-		}
+	opts := []zap.Option{zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	if isDevelopment {
+		opts = append(opts, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
+	} else {
+		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
 	}
 
+	logger := zap.New(core, opts...)
 	defer logger.Sync()
 
 	zap.ReplaceGlobals(logger)
 
 	return nil
 }
+
+// Level returns the current level of the global logger.
+func Level() zapcore.Level {
+	return level.Level()
+}
+
+// SetLevel changes the level of the global logger, e.g. "debug".
+func SetLevel(text string) error {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(text)); err != nil {
+		return fmt.Errorf("l.UnmarshalText -> %w", err)
+	}
+
+	level.SetLevel(l)
+
+	return nil
+}
+
+func newEncoder(encoding string, isDevelopment bool) (zapcore.Encoder, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	if isDevelopment {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	switch encoding {
+	case "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown log encoding %q", encoding)
+	}
+}
+
+func newWriter(path string, conf *config.LogConfig) zapcore.WriteSyncer {
+	switch path {
+	case "stdout":
+		return zapcore.Lock(os.Stdout)
+	case "stderr":
+		return zapcore.Lock(os.Stderr)
+	default:
+		return zapcore.AddSync(&lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    conf.MaxSizeMB,
+			MaxBackups: conf.MaxBackups,
+			MaxAge:     conf.MaxAgeDays,
+			Compress:   conf.Compress,
+		})
+	}
+}
+
+func withDefault(value string, isDevelopment bool, developmentDefault, productionDefault string) string {
+	switch {
+	case value != "":
+		return value
+	case isDevelopment:
+		return developmentDefault
+	default:
+		return productionDefault
+	}
+}
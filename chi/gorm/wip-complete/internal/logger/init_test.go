@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/config"
+)
+
+func TestInit(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+
+	err := Init(&config.LogConfig{
+		Level:       "warn",
+		Encoding:    "json",
+		OutputPaths: []string{logFile},
+	}, "production")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.WarnLevel, Level())
+
+	zap.L().Info("dropped")
+	zap.L().Warn("kept")
+
+	// Lower the level at runtime, no need to rebuild the logger.
+	err = SetLevel("info")
+	require.NoError(t, err)
+	zap.L().Info("kept after level change")
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "kept", entry["msg"])
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "kept after level change", entry["msg"])
+}
+
+func TestInit_Defaults(t *testing.T) {
+	err := Init(nil, "development")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, Level())
+
+	err = Init(nil, "production")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.InfoLevel, Level())
+}
+
+func TestSetLevel_Invalid(t *testing.T) {
+	err := SetLevel("verbose")
+	assert.Error(t, err)
+}
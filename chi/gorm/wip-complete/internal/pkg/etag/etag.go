@@ -0,0 +1,57 @@
+// Package etag builds entity tags and parses the conditional request headers
+// If-Match and If-None-Match, see https://www.rfc-editor.org/rfc/rfc9110#section-13.
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const weakPrefix = "W/"
+
+// New returns a strong entity tag for an opaque value.
+func New(value string) string {
+	return `"` + value + `"`
+}
+
+// FromBytes returns a strong entity tag derived from the content of a representation.
+func FromBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+
+	return New(hex.EncodeToString(sum[:16]))
+}
+
+// Value returns the opaque value of a strong entity tag.
+func Value(tag string) (string, bool) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return "", false
+	}
+
+	return tag[1 : len(tag)-1], true
+}
+
+// Parse splits a If-Match or If-None-Match header into entity tags.
+func Parse(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// Fresh reports whether the If-None-Match header matches tag, in which case the client's copy
+// is still current and 304 Not Modified can be sent. It uses the weak comparison.
+func Fresh(ifNoneMatch, tag string) bool {
+	for _, t := range Parse(ifNoneMatch) {
+		if t == "*" || strings.TrimPrefix(t, weakPrefix) == strings.TrimPrefix(tag, weakPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
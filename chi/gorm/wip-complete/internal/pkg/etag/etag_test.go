@@ -0,0 +1,81 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromBytes(t *testing.T) {
+	tag := FromBytes([]byte(`{"id":1}`))
+
+	assert.Equal(t, tag, FromBytes([]byte(`{"id":1}`)))
+	assert.NotEqual(t, tag, FromBytes([]byte(`{"id":2}`)))
+
+	value, ok := Value(tag)
+	assert.True(t, ok)
+	assert.Len(t, value, 32)
+}
+
+func TestValue(t *testing.T) {
+	value, ok := Value(`"1-2"`)
+	assert.True(t, ok)
+	assert.Equal(t, "1-2", value)
+
+	_, ok = Value(`W/"1-2"`)
+	assert.False(t, ok)
+
+	_, ok = Value(`1-2`)
+	assert.False(t, ok)
+}
+
+func TestFresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		tag         string
+		want        bool
+	}{
+		{
+			name:        "Empty header",
+			ifNoneMatch: "",
+			tag:         `"a"`,
+			want:        false,
+		},
+		{
+			name:        "Same tag",
+			ifNoneMatch: `"a"`,
+			tag:         `"a"`,
+			want:        true,
+		},
+		{
+			name:        "One of many",
+			ifNoneMatch: `"b", "a"`,
+			tag:         `"a"`,
+			want:        true,
+		},
+		{
+			name:        "Weak comparison",
+			ifNoneMatch: `W/"a"`,
+			tag:         `"a"`,
+			want:        true,
+		},
+		{
+			name:        "Wildcard",
+			ifNoneMatch: `*`,
+			tag:         `"a"`,
+			want:        true,
+		},
+		{
+			name:        "Different tag",
+			ifNoneMatch: `"b"`,
+			tag:         `"a"`,
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Fresh(tt.ifNoneMatch, tt.tag))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package fieldset
+
+import (
+	"net/url"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = Schema{
+	Fields:    []string{"id", "title", "content"},
+	Relations: []string{"author"},
+}
+
+type testAuthor struct {
+	Email string `json:"email"`
+}
+
+type testArticle struct {
+	ID      uint        `json:"id"`
+	Title   string      `json:"title"`
+	Content string      `json:"content"`
+	Author  *testAuthor `json:"author,omitempty"`
+}
+
+func TestSchema_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     Spec
+		wantErrs map[string]string // codes by query name
+	}{
+		{
+			name:  "Empty",
+			query: "page=2&fields=",
+			want:  Spec{},
+		},
+		{
+			name:  "Fields and relations",
+			query: "fields=title,id,title&include=author",
+			want:  Spec{Fields: []string{"title", "id"}, Include: []string{"author"}},
+		},
+		{
+			name:  "Invalid queries",
+			query: "fields=id,password&include=comments",
+			wantErrs: map[string]string{
+				"fields":  "query_unknown_field",
+				"include": "query_unknown_relation",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			got, err := testSchema.Parse(values)
+			if tt.wantErrs == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+
+				return
+			}
+
+			var errs validation.Errors
+			require.ErrorAs(t, err, &errs)
+
+			codes := map[string]string{}
+			for key, fieldErr := range errs {
+				codes[key] = fieldErr.(validation.Error).Code()
+			}
+			assert.Equal(t, tt.wantErrs, codes)
+		})
+	}
+}
+
+func TestSpec_Select(t *testing.T) {
+	article := testArticle{ID: 1, Title: "title", Content: "content", Author: &testAuthor{Email: "123@test.com"}}
+
+	tests := []struct {
+		name string
+		spec Spec
+		v    any
+		want string
+	}{
+		{
+			name: "All fields",
+			spec: Spec{},
+			v:    article,
+			want: `{"id":1,"title":"title","content":"content","author":{"email":"123@test.com"}}`,
+		},
+		{
+			name: "Fields in the order of the query",
+			spec: Spec{Fields: []string{"title", "id"}},
+			v:    article,
+			want: `{"title":"title","id":1}`,
+		},
+		{
+			name: "Included relation",
+			spec: Spec{Fields: []string{"id"}, Include: []string{"author"}},
+			v:    article,
+			want: `{"id":1,"author":{"email":"123@test.com"}}`,
+		},
+		{
+			name: "Missing relation",
+			spec: Spec{Fields: []string{"id"}, Include: []string{"author"}},
+			v:    testArticle{ID: 2},
+			want: `{"id":2}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Select(tt.v)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+			if len(tt.spec.Fields) > 0 {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	got, err := SelectAll(Spec{Fields: []string{"id"}}, []testArticle{{ID: 1}, {ID: 2}})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(got))
+	assert.Equal(t, `{"id":1}`, string(got[0]))
+	assert.Equal(t, `{"id":2}`, string(got[1]))
+}
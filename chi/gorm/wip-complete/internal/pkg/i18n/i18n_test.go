@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func newTestTranslator(t *testing.T) *Translator {
+	t.Helper()
+
+	fsys := fstest.MapFS{
+		"de.yaml":    {Data: []byte("titles:\n  not_found: Nicht gefunden\nfields:\n  too_long: höchstens {{.max}} Zeichen\n")},
+		"de-CH.yaml": {Data: []byte("titles:\n  greeting: Grüezi\n")},
+		"es.yaml":    {Data: []byte("titles:\n  not_found: No encontrado\n")},
+	}
+
+	tr, err := New(fsys, language.English)
+	require.NoError(t, err)
+
+	return tr
+}
+
+func TestTranslator_Match(t *testing.T) {
+	tr := newTestTranslator(t)
+
+	tests := []struct {
+		acceptLanguage string
+		want           language.Tag
+	}{
+		{acceptLanguage: "", want: language.English},
+		{acceptLanguage: "de", want: language.German},
+		{acceptLanguage: "de-AT", want: language.German},
+		{acceptLanguage: "de-CH", want: language.MustParse("de-CH")},
+		{acceptLanguage: "fr, es;q=0.8, en;q=0.5", want: language.Spanish},
+		{acceptLanguage: "en-GB, de;q=0.5", want: language.English},
+		{acceptLanguage: "ja", want: language.English},
+		{acceptLanguage: "not a language", want: language.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.want, tr.Match(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	tr := newTestTranslator(t)
+	deCH := language.MustParse("de-CH")
+
+	tests := []struct {
+		name   string
+		lang   language.Tag
+		key    string
+		params map[string]any
+		want   string
+		wantOK bool
+	}{
+		{name: "Message of the language", lang: language.German, key: "titles.not_found", want: "Nicht gefunden", wantOK: true},
+		{name: "Message of the regional language", lang: deCH, key: "titles.greeting", want: "Grüezi", wantOK: true},
+		{name: "Falls back to the parent language", lang: deCH, key: "titles.not_found", want: "Nicht gefunden", wantOK: true},
+		{name: "Renders params", lang: language.German, key: "fields.too_long", params: map[string]any{"max": 128}, want: "höchstens 128 Zeichen", wantOK: true},
+		{name: "Missing param", lang: language.German, key: "fields.too_long"},
+		{name: "Missing message", lang: language.Spanish, key: "fields.too_long"},
+		{name: "Source language", lang: language.English, key: "titles.not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tr.Translate(tt.lang, tt.key, tt.params)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
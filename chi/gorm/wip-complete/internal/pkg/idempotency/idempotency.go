@@ -0,0 +1,85 @@
+// Package idempotency stores the first response of a request made with an Idempotency-Key header,
+// so retries of the same request get the same response instead of being processed again.
+// See https://datatracker.ietf.org/doc/draft-ietf-httpapi-idempotency-key-header/
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Header is the request header carrying the key.
+	Header = "Idempotency-Key"
+
+	// ReplayedHeader is set on responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// MaxKeyLength keeps keys reasonable, UUIDs are 36 characters long.
+	MaxKeyLength = 255
+
+	// DefaultTTL is how long responses are kept when no TTL is configured.
+	DefaultTTL = 24 * time.Hour
+)
+
+// Record is what's stored for a key.
+// It's reserved before the request is processed and completed with the response afterwards.
+type Record struct {
+	RequestHash string
+
+	Completed  bool
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type Store interface {
+	// Reserve stores an uncompleted record for key unless there is one already.
+	// It returns whether the key was reserved, or the existing record otherwise.
+	Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, Record, error)
+
+	// Complete stores the response of the request that reserved key.
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+
+	// Release deletes key, so that the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// HashRequest fingerprints a request, so a key can't be reused for a different request.
+func HashRequest(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReplayableHeader returns the headers of a response worth replaying.
+// Headers describing the current request, like rate limits, are left out.
+func ReplayableHeader(header http.Header) http.Header {
+	replayable := make(http.Header, len(header))
+	for name, values := range header {
+		canonical := http.CanonicalHeaderKey(name)
+		if strings.HasPrefix(canonical, "Ratelimit-") || skippedHeaders[canonical] {
+			continue
+		}
+
+		replayable[canonical] = values
+	}
+
+	return replayable
+}
+
+var skippedHeaders = map[string]bool{
+	"Content-Length": true,
+	"Date":           true,
+	"Retry-After":    true,
+	"X-Request-Id":   true,
+}
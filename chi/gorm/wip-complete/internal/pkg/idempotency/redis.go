@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps records in Redis, they expire by themselves with the TTL.
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: "idempotency:",
+	}
+}
+
+func (s *RedisStore) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, Record, error) {
+	reserved := Record{RequestHash: requestHash}
+	value, err := json.Marshal(reserved)
+	if err != nil {
+		return false, Record{}, fmt.Errorf("json.Marshal -> %w", err)
+	}
+
+	ok, err := s.client.SetNX(ctx, s.prefix+key, value, ttl).Result()
+	if err != nil {
+		return false, Record{}, fmt.Errorf("s.client.SetNX -> %w", err)
+	}
+	if ok {
+		return true, Record{}, nil
+	}
+
+	existing, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// Expired in between, just try again.
+			return s.Reserve(ctx, key, requestHash, ttl)
+		}
+
+		return false, Record{}, fmt.Errorf("s.client.Get -> %w", err)
+	}
+
+	var record Record
+	if err = json.Unmarshal(existing, &record); err != nil {
+		return false, Record{}, fmt.Errorf("json.Unmarshal -> %w", err)
+	}
+
+	return false, record, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	record.Completed = true
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("json.Marshal -> %w", err)
+	}
+
+	if err = s.client.Set(ctx, s.prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("s.client.Set -> %w", err)
+	}
+
+	return nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("s.client.Del -> %w", err)
+	}
+
+	return nil
+}
@@ -20,15 +20,17 @@ type Claims struct {
 
 	UserID    uint
 	UserAgent string
+	IsAdmin   bool
 }
 
-func GenerateToken(signingKey []byte, userID uint, userAgent string) (string, error) {
+func GenerateToken(signingKey []byte, userID uint, userAgent string, isAdmin bool) (string, error) {
 	claims := Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expirationTime)),
 		},
 		UserID:    userID,
 		UserAgent: userAgent,
+		IsAdmin:   isAdmin,
 	}
 
 	token := jwt.NewWithClaims(signingMethod, claims)
@@ -0,0 +1,253 @@
+// Package listquery parses the sorting and filtering queries of list endpoints, e.g.
+// ?sort=-created_at,title&filter[user_id]=123&filter[created_at][gte]=2024-01-01,
+// into a typed Spec. Only the fields and operators whitelisted by a Schema are accepted.
+package listquery
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	SortQueryKey   = "sort"
+	FilterQueryKey = "filter"
+)
+
+// Type is the type of the values of a field.
+type Type string
+
+const (
+	String Type = "string"
+	Int    Type = "integer"
+	Time   Type = "time"
+)
+
+// Operator compares a field to the value of a filter.
+type Operator string
+
+const (
+	Eq       Operator = "eq"
+	Ne       Operator = "ne"
+	Gt       Operator = "gt"
+	Gte      Operator = "gte"
+	Lt       Operator = "lt"
+	Lte      Operator = "lte"
+	In       Operator = "in"       // the value is a comma separated list
+	Contains Operator = "contains" // case-insensitive substring, strings only
+)
+
+var (
+	errMalformed           = validation.NewError("query_malformed", "must look like filter[field] or filter[field][operator]")
+	errUnknownField        = validation.NewError("query_unknown_field", "unknown field {{.field}}")
+	errNotSortable         = validation.NewError("query_field_not_sortable", "field {{.field}} can't be sorted by")
+	errUnsupportedOperator = validation.NewError("query_unsupported_operator", "operator {{.operator}} isn't supported by field {{.field}}")
+	errInvalidValue        = validation.NewError("query_invalid_value", "must be of type {{.type}}")
+)
+
+// Field is a field clients may sort or filter by.
+type Field struct {
+	Type      Type
+	Sortable  bool
+	Operators []Operator // allowed filter operators, the field can't be filtered by when empty
+}
+
+func (f Field) supports(op Operator) bool {
+	for _, supported := range f.Operators {
+		if supported == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Schema whitelists fields by their names in the queries.
+type Schema map[string]Field
+
+// Sortable returns a copy of the schema where only the given fields can be sorted by.
+func (s Schema) Sortable(names ...string) Schema {
+	result := make(Schema, len(s))
+	for name, field := range s {
+		field.Sortable = false
+		for _, sortable := range names {
+			if name == sortable {
+				field.Sortable = true
+			}
+		}
+
+		result[name] = field
+	}
+
+	return result
+}
+
+// Sort orders a list by a field.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter compares a field to a value.
+// Value is a string, an int64 or a time.Time depending on the field type, and a slice of them for In.
+type Filter struct {
+	Field    string
+	Operator Operator
+	Value    any
+}
+
+// Spec is how a list is sorted and filtered, in the order of the queries.
+type Spec struct {
+	Sorts   []Sort
+	Filters []Filter
+}
+
+// Parse reads the sort and filter queries of values, other queries are ignored.
+// Invalid queries are reported as validation.Errors keyed by the query name, e.g. filter[user_id][gte].
+func (s Schema) Parse(values url.Values) (Spec, error) {
+	var spec Spec
+	errs := validation.Errors{}
+
+	if raw := values.Get(SortQueryKey); raw != "" {
+		sorts, err := s.parseSort(raw)
+		if err != nil {
+			errs[SortQueryKey] = err
+		}
+
+		spec.Sorts = sorts
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key == FilterQueryKey || strings.HasPrefix(key, FilterQueryKey+"[") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		filter, err := s.parseFilter(key, values.Get(key))
+		if err != nil {
+			errs[key] = err
+
+			continue
+		}
+
+		spec.Filters = append(spec.Filters, filter)
+	}
+
+	if len(errs) > 0 {
+		return Spec{}, errs
+	}
+
+	return spec, nil
+}
+
+func (s Schema) parseSort(raw string) ([]Sort, error) {
+	var sorts []Sort
+	for _, name := range strings.Split(raw, ",") {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		field, ok := s[name]
+		if !ok {
+			return nil, errUnknownField.SetParams(map[string]any{"field": name})
+		}
+		if !field.Sortable {
+			return nil, errNotSortable.SetParams(map[string]any{"field": name})
+		}
+
+		sorts = append(sorts, Sort{Field: name, Desc: desc})
+	}
+
+	return sorts, nil
+}
+
+func (s Schema) parseFilter(key, raw string) (Filter, error) {
+	name, op, ok := parseFilterKey(key)
+	if !ok {
+		return Filter{}, errMalformed
+	}
+
+	field, ok := s[name]
+	if !ok {
+		return Filter{}, errUnknownField.SetParams(map[string]any{"field": name})
+	}
+	if !field.supports(op) {
+		return Filter{}, errUnsupportedOperator.SetParams(map[string]any{"field": name, "operator": op})
+	}
+
+	if op != In {
+		value, err := field.Type.parse(raw)
+		if err != nil {
+			return Filter{}, err
+		}
+
+		return Filter{Field: name, Operator: op, Value: value}, nil
+	}
+
+	var values []any
+	for _, item := range strings.Split(raw, ",") {
+		value, err := field.Type.parse(item)
+		if err != nil {
+			return Filter{}, err
+		}
+
+		values = append(values, value)
+	}
+
+	return Filter{Field: name, Operator: op, Value: values}, nil
+}
+
+// parseFilterKey splits filter[field] and filter[field][operator], the operator defaults to Eq.
+func parseFilterKey(key string) (string, Operator, bool) {
+	rest, ok := strings.CutPrefix(key, FilterQueryKey+"[")
+	if !ok {
+		return "", "", false
+	}
+
+	name, rest, ok := strings.Cut(rest, "]")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	if rest == "" {
+		return name, Eq, true
+	}
+
+	op, ok := strings.CutPrefix(rest, "[")
+	if !ok || !strings.HasSuffix(op, "]") || len(op) == 1 {
+		return "", "", false
+	}
+
+	return name, Operator(strings.TrimSuffix(op, "]")), true
+}
+
+func (t Type) parse(raw string) (any, error) {
+	switch t {
+	case Int:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errInvalidValue.SetParams(map[string]any{"type": t})
+		}
+
+		return value, nil
+	case Time:
+		// Dates are accepted as the start of the day in UTC.
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if value, err := time.Parse(layout, raw); err == nil {
+				return value, nil
+			}
+		}
+
+		return nil, errInvalidValue.SetParams(map[string]any{"type": t})
+	case String:
+		return raw, nil
+	default:
+		panic(fmt.Sprintf("listquery: unknown type %q", t))
+	}
+}
@@ -0,0 +1,101 @@
+package listquery
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = Schema{
+	"id":         {Type: Int, Sortable: true, Operators: []Operator{Eq, In}},
+	"title":      {Type: String, Sortable: true, Operators: []Operator{Eq, Contains}},
+	"content":    {Type: String, Operators: []Operator{Contains}},
+	"created_at": {Type: Time, Sortable: true, Operators: []Operator{Gte, Lt}},
+}
+
+func TestSchema_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     Spec
+		wantErrs map[string]string // codes by query name
+	}{
+		{
+			name:  "Empty",
+			query: "page=2",
+			want:  Spec{},
+		},
+		{
+			name:  "Sorts and filters",
+			query: "sort=-created_at,title&filter[id][in]=1,2&filter[title]=abc&filter[created_at][gte]=2024-01-01",
+			want: Spec{
+				Sorts: []Sort{{Field: "created_at", Desc: true}, {Field: "title"}},
+				Filters: []Filter{
+					{Field: "created_at", Operator: Gte, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{Field: "id", Operator: In, Value: []any{int64(1), int64(2)}},
+					{Field: "title", Operator: Eq, Value: "abc"},
+				},
+			},
+		},
+		{
+			name:  "RFC 3339 time",
+			query: "filter[created_at][lt]=2024-01-31T15:26:31Z",
+			want: Spec{
+				Filters: []Filter{{Field: "created_at", Operator: Lt, Value: time.Date(2024, 1, 31, 15, 26, 31, 0, time.UTC)}},
+			},
+		},
+		{
+			name:  "Invalid queries",
+			query: "sort=content&filter[user_id]=1&filter[title][gt]=a&filter[id]=abc&filter[id][in]=1,x&filter[title]x=1&filter=1",
+			wantErrs: map[string]string{
+				"sort":              "query_field_not_sortable",
+				"filter[user_id]":   "query_unknown_field",
+				"filter[title][gt]": "query_unsupported_operator",
+				"filter[id]":        "query_invalid_value",
+				"filter[id][in]":    "query_invalid_value",
+				"filter[title]x":    "query_malformed",
+				"filter":            "query_malformed",
+			},
+		},
+		{
+			name:     "Unknown sort field",
+			query:    "sort=title,-author",
+			wantErrs: map[string]string{"sort": "query_unknown_field"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			got, err := testSchema.Parse(values)
+			if tt.wantErrs == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+
+				return
+			}
+
+			var errs validation.Errors
+			require.ErrorAs(t, err, &errs)
+
+			codes := map[string]string{}
+			for key, fieldErr := range errs {
+				codes[key] = fieldErr.(validation.Error).Code()
+			}
+			assert.Equal(t, tt.wantErrs, codes)
+		})
+	}
+}
+
+func TestSchema_Sortable(t *testing.T) {
+	schema := testSchema.Sortable("created_at")
+
+	assert.True(t, schema["created_at"].Sortable)
+	assert.False(t, schema["title"].Sortable)
+	assert.True(t, testSchema["title"].Sortable)
+}
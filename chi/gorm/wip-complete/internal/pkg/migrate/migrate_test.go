@@ -0,0 +1,137 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_create_articles.up.sql":   {Data: []byte("CREATE TABLE articles ();")},
+		"000002_create_articles.down.sql": {Data: []byte("DROP TABLE articles;")},
+		"000001_create_users.up.sql":      {Data: []byte("CREATE TABLE users ();")},
+		"000001_create_users.down.sql":    {Data: []byte("DROP TABLE users;")},
+		"000003_backfill.up.sql":          {Data: []byte("")},
+		"README.md":                       {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+
+	require.Len(t, migrations, 3)
+	assert.EqualValues(t, 1, migrations[0].Version)
+	assert.Equal(t, "create_users", migrations[0].Name)
+	assert.Equal(t, "CREATE TABLE users ();", migrations[0].Up)
+	assert.Equal(t, "DROP TABLE users;", migrations[0].Down)
+	assert.EqualValues(t, 2, migrations[1].Version)
+	assert.Equal(t, "create_articles", migrations[1].Name)
+	assert.EqualValues(t, 3, migrations[2].Version)
+	assert.True(t, migrations[2].hasUp)
+	assert.False(t, migrations[2].hasDown)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "Same version",
+			fsys: fstest.MapFS{
+				"000001_create_users.up.sql":    {},
+				"000001_create_articles.up.sql": {},
+			},
+		},
+		{
+			name: "No up script",
+			fsys: fstest.MapFS{
+				"000001_create_users.down.sql": {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+
+	up, down, err := Create(dir, "Create_Users")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "000001_create_users.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "000001_create_users.down.sql"), down)
+
+	up, _, err = Create(dir, "add_bio")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "000002_add_bio.up.sql"), up)
+
+	_, _, err = Create(dir, "add bio; DROP TABLE users")
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
+
+func TestNew_UnknownDialect(t *testing.T) {
+	_, err := New(nil, fstest.MapFS{}, "oracle")
+	assert.ErrorIs(t, err, ErrNoDialect)
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fsys := fstest.MapFS{
+		"000001_create_users.up.sql":      {Data: []byte("CREATE TABLE users (id integer PRIMARY KEY);")},
+		"000001_create_users.down.sql":    {Data: []byte("DROP TABLE users;")},
+		"000002_create_articles.up.sql":   {Data: []byte("CREATE TABLE articles (id integer PRIMARY KEY);")},
+		"000002_create_articles.down.sql": {Data: []byte("DROP TABLE articles;")},
+	}
+	migrator, err := New(db, fsys, "sqlite")
+	require.NoError(t, err)
+
+	// Status doesn't create the schema_migrations table.
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Pending())
+
+	var tables int
+	err = db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&tables)
+	require.NoError(t, err)
+	assert.Zero(t, tables)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+
+	// Applied migrations aren't applied again.
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	rolledBack, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, "create_articles", rolledBack[0].Name)
+
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.False(t, statuses[0].Pending())
+	assert.True(t, statuses[1].Pending())
+
+	err = db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name IN ('users', 'articles')").Scan(&tables)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tables)
+}
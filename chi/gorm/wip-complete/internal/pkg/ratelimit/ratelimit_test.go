@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Limit
+		wantErr bool
+	}{
+		{
+			name:  "HappyPath - per minute",
+			input: "10/1m",
+			want:  Limit{Rate: 10, Period: time.Minute, Burst: 10},
+		},
+		{
+			name:  "Empty means unlimited",
+			input: "",
+			want:  Limit{},
+		},
+		{
+			name:    "Missing period",
+			input:   "10",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid rate",
+			input:   "-1/1m",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid period",
+			input:   "10/minute",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimit(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	// 3 requests per 3 seconds, so 1 request is replenished every second.
+	limit := Limit{Rate: 3, Period: 3 * time.Second, Burst: 3}
+	now := time.Date(2024, 1, 31, 15, 26, 31, 0, time.UTC)
+
+	mr := miniredis.RunT(t)
+	mr.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	memory := NewMemoryLimiter()
+	memory.now = func() time.Time { return now }
+
+	limiters := map[string]struct {
+		limiter Limiter
+		advance func(d time.Duration)
+	}{
+		"memory": {
+			limiter: memory,
+			advance: func(d time.Duration) {
+				current := memory.now()
+				memory.now = func() time.Time { return current.Add(d) }
+			},
+		},
+		"redis": {
+			limiter: NewRedisLimiter(client),
+			advance: func(d time.Duration) {
+				now = now.Add(d)
+				mr.SetTime(now)
+			},
+		},
+	}
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// The whole burst is allowed at once.
+			for i := 2; i >= 0; i-- {
+				result, err := l.limiter.Allow(ctx, "key", limit)
+				require.NoError(t, err)
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 3, result.Limit)
+				assert.Equal(t, i, result.Remaining)
+			}
+
+			// Then the next one has to wait until a request is replenished.
+			result, err := l.limiter.Allow(ctx, "key", limit)
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+			assert.Equal(t, time.Second, result.RetryAfter)
+			assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+			// Other keys aren't affected.
+			result, err = l.limiter.Allow(ctx, "other key", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+
+			l.advance(time.Second)
+			result, err = l.limiter.Allow(ctx, "key", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+
+			// Unlimited.
+			result, err = l.limiter.Allow(ctx, "key", Limit{})
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		})
+	}
+}
@@ -5,19 +5,27 @@ import (
 	"fmt"
 
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/listquery"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository/dao"
 )
 
 var (
 	ErrArticleDuplicated = dao.ErrArticleDuplicated
 	ErrArticleNotFound   = dao.ErrArticleNotFound
+	ErrArticleModified   = dao.ErrArticleModified
 )
 
 type ArticleDAO interface {
 	Insert(ctx context.Context, article dao.Article) (dao.Article, error)
-	FindByID(ctx context.Context, id uint) (dao.Article, error)
-	FindAll(ctx context.Context, page, perPage uint) ([]dao.Article, error)
-	Search(ctx context.Context, title, content string) ([]dao.Article, error)
+	FindByID(ctx context.Context, id uint, preloads ...string) (dao.Article, error)
+	FindAll(ctx context.Context, page, perPage uint, spec listquery.Spec, preloads ...string) ([]dao.Article, error)
+	FindAfter(ctx context.Context, key *dao.Keyset, limit uint, backward bool, spec listquery.Spec, preloads ...string) ([]dao.Article, error)
+	Export(ctx context.Context, spec listquery.Spec, query, language string, fn func(dao.Article) error) error
+	Count(ctx context.Context, spec listquery.Spec) (int64, error)
+	Search(ctx context.Context, query, language string, page, perPage uint) ([]dao.ArticleMatch, error)
+	CountMatches(ctx context.Context, query, language string) (int64, error)
+	Update(ctx context.Context, article dao.Article) (dao.Article, error)
+	Delete(ctx context.Context, id, version uint) error
 }
 
 type ArticleRepository struct {
@@ -43,8 +51,8 @@ func (r *ArticleRepository) Create(ctx context.Context, article domain.Article)
 	return r.daoToDomain(created), nil
 }
 
-func (r *ArticleRepository) FindByID(ctx context.Context, id uint) (domain.Article, error) {
-	found, err := r.dao.FindByID(ctx, id)
+func (r *ArticleRepository) FindByID(ctx context.Context, id uint, relations domain.ArticleRelations) (domain.Article, error) {
+	found, err := r.dao.FindByID(ctx, id, r.preloads(relations)...)
 	if err != nil {
 		return domain.Article{}, fmt.Errorf("r.dao.FindByID -> %w", err)
 	}
@@ -52,8 +60,8 @@ func (r *ArticleRepository) FindByID(ctx context.Context, id uint) (domain.Artic
 	return r.daoToDomain(found), nil
 }
 
-func (r *ArticleRepository) FindAll(ctx context.Context, page, perPage uint) ([]domain.Article, error) {
-	allArticles, err := r.dao.FindAll(ctx, page, perPage)
+func (r *ArticleRepository) FindAll(ctx context.Context, page, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) ([]domain.Article, error) {
+	allArticles, err := r.dao.FindAll(ctx, page, perPage, spec, r.preloads(relations)...)
 	if err != nil {
 		return nil, fmt.Errorf("r.dao.FindAll -> %w", err)
 	}
@@ -66,27 +74,146 @@ func (r *ArticleRepository) FindAll(ctx context.Context, page, perPage uint) ([]
 	return articles, nil
 }
 
-func (r *ArticleRepository) Search(ctx context.Context, title, content string) ([]domain.Article, error) {
-	allArticles, err := r.dao.Search(ctx, title, content)
+// FindByCursor returns the page of perPage articles matching spec starting right after cursor,
+// or ending right before it for backward cursors. A nil cursor returns the first page.
+func (r *ArticleRepository) FindByCursor(ctx context.Context, cursor *domain.Cursor, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) (domain.Page[domain.Article], error) {
+	var key *dao.Keyset
+	backward := false
+	if cursor != nil {
+		key = &dao.Keyset{CreatedAt: cursor.CreatedAt, ID: cursor.ID}
+		backward = cursor.Backward
+	}
+
+	// One more article tells whether there's another page in the same direction.
+	found, err := r.dao.FindAfter(ctx, key, perPage+1, backward, spec, r.preloads(relations)...)
+	if err != nil {
+		return domain.Page[domain.Article]{}, fmt.Errorf("r.dao.FindAfter -> %w", err)
+	}
+
+	hasMore := uint(len(found)) > perPage
+	if hasMore && backward {
+		found = found[1:]
+	} else if hasMore {
+		found = found[:perPage]
+	}
+
+	page := domain.Page[domain.Article]{
+		Items: make([]domain.Article, 0, len(found)),
+	}
+	for _, a := range found {
+		page.Items = append(page.Items, r.daoToDomain(a))
+	}
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	// Coming from a cursor means there are articles on the other side of it.
+	first, last := page.Items[0], page.Items[len(page.Items)-1]
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		page.Prev = &domain.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true}
+	}
+	if (!backward && hasMore) || backward {
+		page.Next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// Export calls fn with each article matching spec and export, one at a time as they're read.
+func (r *ArticleRepository) Export(ctx context.Context, spec listquery.Spec, export domain.ArticleExport, fn func(domain.Article) error) error {
+	err := r.dao.Export(ctx, spec, export.Query, export.Language, func(article dao.Article) error {
+		return fn(r.daoToDomain(article))
+	})
+	if err != nil {
+		return fmt.Errorf("r.dao.Export -> %w", err)
+	}
+
+	return nil
+}
+
+func (r *ArticleRepository) Count(ctx context.Context, spec listquery.Spec) (int64, error) {
+	count, err := r.dao.Count(ctx, spec)
+	if err != nil {
+		return 0, fmt.Errorf("r.dao.Count -> %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *ArticleRepository) Search(ctx context.Context, query, language string, page, perPage uint) ([]domain.ArticleMatch, error) {
+	found, err := r.dao.Search(ctx, query, language, page, perPage)
 	if err != nil {
 		return nil, fmt.Errorf("r.dao.Search -> %w", err)
 	}
 
-	articles := make([]domain.Article, 0, len(allArticles))
-	for _, a := range allArticles {
-		articles = append(articles, r.daoToDomain(a))
+	matches := make([]domain.ArticleMatch, 0, len(found))
+	for _, m := range found {
+		matches = append(matches, domain.ArticleMatch{
+			Article:  r.daoToDomain(m.Article),
+			Rank:     m.Rank,
+			Headline: m.Headline,
+		})
 	}
 
-	return articles, nil
+	return matches, nil
+}
+
+func (r *ArticleRepository) CountMatches(ctx context.Context, query, language string) (int64, error) {
+	count, err := r.dao.CountMatches(ctx, query, language)
+	if err != nil {
+		return 0, fmt.Errorf("r.dao.CountMatches -> %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *ArticleRepository) Update(ctx context.Context, article domain.Article) (domain.Article, error) {
+	updated, err := r.dao.Update(ctx, dao.Article{
+		ID:      article.ID,
+		Title:   article.Title,
+		Content: article.Content,
+		Version: article.Version,
+	})
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("r.dao.Update -> %w", err)
+	}
+
+	return r.daoToDomain(updated), nil
+}
+
+func (r *ArticleRepository) Delete(ctx context.Context, id, version uint) error {
+	if err := r.dao.Delete(ctx, id, version); err != nil {
+		return fmt.Errorf("r.dao.Delete -> %w", err)
+	}
+
+	return nil
+}
+
+func (r *ArticleRepository) preloads(relations domain.ArticleRelations) []string {
+	var preloads []string
+	if relations.Author {
+		preloads = append(preloads, dao.ArticleUser)
+	}
+
+	return preloads
 }
 
 func (r *ArticleRepository) daoToDomain(a dao.Article) domain.Article {
-	return domain.Article{
+	article := domain.Article{
 		ID:        a.ID,
 		UserID:    a.UserID,
 		Title:     a.Title,
 		Content:   a.Content,
+		Version:   a.Version,
 		CreatedAt: a.CreatedAt,
 		UpdatedAt: a.UpdatedAt,
 	}
+	if a.User != nil {
+		article.Author = &domain.Author{
+			ID:        a.User.ID,
+			CreatedAt: a.User.CreatedAt,
+		}
+	}
+
+	return article
 }
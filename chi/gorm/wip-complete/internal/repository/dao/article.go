@@ -3,28 +3,99 @@ package dao
 import (
 	"context"
 	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 
-	"github.com/jackc/pgerrcode"
-	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/listquery"
 )
 
 var (
 	ErrArticleDuplicated = errors.New("article already exists")
 	ErrArticleNotFound   = errors.New("article not found")
+	ErrArticleModified   = errors.New("article has been modified since it was fetched")
+	ErrSearchUnsupported = errors.New("search isn't supported by the search backend")
 )
 
 type Article struct {
-	ID uint `gorm:"primaryKey"`
+	ID uint `gorm:"primaryKey;index:idx_articles_created_at_id,priority:2"`
 
 	UserID  uint   `gorm:"uniqueIndex:idx_user_id_title,not null"`
 	Title   string `gorm:"uniqueIndex:idx_user_id_title,not null"`
 	Content string `gorm:"not null"`
 
-	CreatedAt time.Time `gorm:"not null"`
+	// User is the author, it's only loaded when preloaded with ArticleUser.
+	// The foreign key keeps articles from referring to users that don't exist.
+	User *User `gorm:"foreignKey:UserID"`
+
+	// Version is incremented on every update, for optimistic concurrency control.
+	Version uint `gorm:"not null;default:1"`
+
+	CreatedAt time.Time `gorm:"not null;index:idx_articles_created_at_id,priority:1"`
 	UpdatedAt time.Time `gorm:"not null"`
+
+	// SearchVector is maintained by Postgres for full-text search in the searchLanguage configuration,
+	// titles weigh more than contents. It's never read nor written by the application.
+	SearchVector string `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(content, '')), 'B')) STORED;index:idx_articles_search_vector,type:gin;->:false;<-:false"`
+}
+
+// ArticleMatch is an article found by a full-text search.
+type ArticleMatch struct {
+	Article
+
+	Rank     float32
+	Headline string
+}
+
+const (
+	// searchLanguage is the text search configuration of Article.SearchVector.
+	searchLanguage = "english"
+
+	// searchVectorSQL computes the search vector of articles in another configuration, which isn't indexed.
+	// Languages are cast with CAST rather than ::, which GORM would take as part of the named parameter.
+	searchVectorSQL = "setweight(to_tsvector(CAST(@language AS regconfig), coalesce(title, '')), 'A') || " +
+		"setweight(to_tsvector(CAST(@language AS regconfig), coalesce(content, '')), 'B')"
+
+	// headlineStart and headlineStop delimit the matches in the headlines of ts_headline,
+	// they're control characters so that the content around them can be escaped, see escapeHeadline.
+	headlineStart = "\x02"
+	headlineStop  = "\x03"
+
+	// headlineOptions highlight matches in up to 2 fragments of the content, see
+	// https://www.postgresql.org/docs/current/textsearch-controls.html#TEXTSEARCH-HEADLINE.
+	headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+// headlineMarks turn the delimiters of matches into <b></b>.
+var headlineMarks = strings.NewReplacer(headlineStart, "<b>", headlineStop, "</b>")
+
+// escapeHeadline HTML-escapes a headline of ts_headline, which copies the content as is,
+// and marks its matches with <b></b>.
+func escapeHeadline(headline string) string {
+	return headlineMarks.Replace(html.EscapeString(headline))
+}
+
+// ArticleUser is the relation of articles to their authors, see Article.User.
+const ArticleUser = "User"
+
+// Keyset is the position of an article in the (created_at, id) order.
+type Keyset struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// articleColumns are the columns articles can be sorted and filtered by, keyed by field name.
+var articleColumns = map[string]string{
+	"id":         "id",
+	"user_id":    "user_id",
+	"title":      "title",
+	"content":    "content",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
 }
 
 type ArticleDAO struct {
@@ -38,12 +109,15 @@ func NewArticleDAO(db *gorm.DB) *ArticleDAO {
 }
 
 func (d *ArticleDAO) Insert(ctx context.Context, article Article) (Article, error) {
-	result := d.db.WithContext(ctx).Create(&article)
+	result := conn(ctx, d.db).Create(&article)
 	if result.Error != nil {
-		var err *pgconn.PgError
-		if errors.As(result.Error, &err) && err.Code == pgerrcode.UniqueViolation {
+		err := translateError(d.db, result.Error)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return Article{}, ErrArticleDuplicated
 		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return Article{}, ErrUserNotFound
+		}
 
 		return Article{}, result.Error
 	}
@@ -51,10 +125,11 @@ func (d *ArticleDAO) Insert(ctx context.Context, article Article) (Article, erro
 	return article, nil
 }
 
-func (d *ArticleDAO) FindByID(ctx context.Context, id uint) (Article, error) {
+// FindByID returns an article along with the preloaded relations, e.g. ArticleUser.
+func (d *ArticleDAO) FindByID(ctx context.Context, id uint, preloads ...string) (Article, error) {
 	var article Article
 
-	result := d.db.WithContext(ctx).First(&article, id)
+	result := preload(conn(ctx, d.db), preloads).First(&article, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return Article{}, ErrArticleNotFound
@@ -66,12 +141,26 @@ func (d *ArticleDAO) FindByID(ctx context.Context, id uint) (Article, error) {
 	return article, nil
 }
 
-func (d *ArticleDAO) FindAll(ctx context.Context, page, perPage uint) ([]Article, error) {
+// FindAll returns a page of articles along with the preloaded relations, see FindByID.
+func (d *ArticleDAO) FindAll(ctx context.Context, page, perPage uint, spec listquery.Spec, preloads ...string) ([]Article, error) {
 	var articles []Article
 
+	finder, err := applyFilters(preload(readConn(ctx, d.db), preloads), spec, articleColumns)
+	if err != nil {
+		return nil, err
+	}
+	finder, err = applySorts(finder, spec, articleColumns)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.Sorts) > 0 {
+		// Break ties, so articles don't move between pages.
+		finder = finder.Order("id")
+	}
+
 	// page number is starting from 1.
 	offset := (page - 1) * perPage
-	result := d.db.WithContext(ctx).Offset(int(offset)).Limit(int(perPage)).Find(&articles)
+	result := finder.Offset(int(offset)).Limit(int(perPage)).Find(&articles)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -79,15 +168,30 @@ func (d *ArticleDAO) FindAll(ctx context.Context, page, perPage uint) ([]Article
 	return articles, nil
 }
 
-func (d *ArticleDAO) Search(ctx context.Context, title, content string) ([]Article, error) {
+// FindAfter returns up to limit articles matching the filters of spec right after key in the (created_at, id) order,
+// or right before it when backward is set. A nil key starts from the first or the last article.
+// Articles are always returned in the (created_at, id) order, which is reversed when spec sorts by -created_at.
+// Other sorts of spec are ignored. Relations are preloaded as in FindByID.
+func (d *ArticleDAO) FindAfter(ctx context.Context, key *Keyset, limit uint, backward bool, spec listquery.Spec, preloads ...string) ([]Article, error) {
 	var articles []Article
 
-	finder := d.db.WithContext(ctx)
-	if title != "" {
-		finder = finder.Where("title LIKE ?", "%"+title+"%")
+	finder, err := applyFilters(preload(readConn(ctx, d.db), preloads), spec, articleColumns)
+	if err != nil {
+		return nil, err
 	}
-	if content != "" {
-		finder = finder.Where("content LIKE ?", "%"+content+"%")
+	finder = finder.Limit(int(limit))
+
+	desc := len(spec.Sorts) > 0 && spec.Sorts[0].Field == "created_at" && spec.Sorts[0].Desc
+	if backward != desc {
+		finder = finder.Order("created_at DESC, id DESC")
+		if key != nil {
+			finder = finder.Where("(created_at, id) < (?, ?)", key.CreatedAt, key.ID)
+		}
+	} else {
+		finder = finder.Order("created_at, id")
+		if key != nil {
+			finder = finder.Where("(created_at, id) > (?, ?)", key.CreatedAt, key.ID)
+		}
 	}
 
 	result := finder.Find(&articles)
@@ -95,5 +199,209 @@ func (d *ArticleDAO) Search(ctx context.Context, title, content string) ([]Artic
 		return nil, result.Error
 	}
 
+	if backward {
+		slices.Reverse(articles)
+	}
+
 	return articles, nil
 }
+
+// exportColumns are the columns of exported articles, qualified as articles may be joined with a search query.
+const exportColumns = "articles.id, articles.user_id, articles.title, articles.content, articles.version, articles.created_at, articles.updated_at"
+
+// Export calls fn with each article matching the filters of spec, in the order of its sorts then by id.
+// Articles are also filtered by query like in Search, unless it's empty. They're read from a cursor one at a time,
+// so memory stays the same however many they are. The query is stopped as soon as ctx is done or fn fails.
+func (d *ArticleDAO) Export(ctx context.Context, spec listquery.Spec, query, language string, fn func(Article) error) error {
+	finder := readConn(ctx, d.db).Model(&Article{})
+	if query != "" {
+		if err := d.checkSearchable(); err != nil {
+			return err
+		}
+
+		finder = d.searchFrom(ctx, query, language).
+			Where(searchVector(language)+" @@ query", map[string]any{"language": language})
+	}
+
+	finder, err := applyFilters(finder.Select(exportColumns), spec, articleColumns)
+	if err != nil {
+		return err
+	}
+	finder, err = applySorts(finder, spec, articleColumns)
+	if err != nil {
+		return err
+	}
+
+	rows, err := finder.Order("id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article Article
+		if err = finder.ScanRows(rows, &article); err != nil {
+			return err
+		}
+
+		if err = fn(article); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Count returns how many articles match the filters of spec.
+func (d *ArticleDAO) Count(ctx context.Context, spec listquery.Spec) (int64, error) {
+	var count int64
+
+	counter, err := applyFilters(readConn(ctx, d.db).Model(&Article{}), spec, articleColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	result := counter.Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// Search returns the page of articles matching query, parsed by websearch_to_tsquery
+// in the language text search configuration, from the most to the least relevant.
+func (d *ArticleDAO) Search(ctx context.Context, query, language string, page, perPage uint) ([]ArticleMatch, error) {
+	if err := d.checkSearchable(); err != nil {
+		return nil, err
+	}
+
+	var matches []ArticleMatch
+
+	vector := searchVector(language)
+	finder := d.searchFrom(ctx, query, language).
+		Select("articles.*, ts_rank("+vector+", query) AS rank, ts_headline(CAST(@language AS regconfig), content, query, @options) AS headline",
+			map[string]any{"language": language, "options": headlineOptions}).
+		Where(vector+" @@ query", map[string]any{"language": language}).
+		Order("rank DESC, id")
+
+	// page number is starting from 1.
+	offset := (page - 1) * perPage
+	result := finder.Offset(int(offset)).Limit(int(perPage)).Find(&matches)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for i := range matches {
+		matches[i].Headline = escapeHeadline(matches[i].Headline)
+	}
+
+	return matches, nil
+}
+
+// CountMatches returns how many articles match query, see Search.
+func (d *ArticleDAO) CountMatches(ctx context.Context, query, language string) (int64, error) {
+	if err := d.checkSearchable(); err != nil {
+		return 0, err
+	}
+
+	var count int64
+
+	result := d.searchFrom(ctx, query, language).
+		Where(searchVector(language)+" @@ query", map[string]any{"language": language}).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// checkSearchable returns ErrSearchUnsupported unless the database is Postgres, the only one with full-text search.
+// It must be called before the first query, so that callers can still report the error as a bad request.
+func (d *ArticleDAO) checkSearchable() error {
+	if dialect := d.db.Dialector.Name(); dialect != "postgres" {
+		return fmt.Errorf("%w: full-text search needs postgres, not %v", ErrSearchUnsupported, dialect)
+	}
+
+	return nil
+}
+
+// searchFrom selects articles along with query, parsed as a tsquery named query.
+func (d *ArticleDAO) searchFrom(ctx context.Context, query, language string) *gorm.DB {
+	return readConn(ctx, d.db).
+		Table("articles, websearch_to_tsquery(CAST(? AS regconfig), ?) AS query", language, query)
+}
+
+// searchVector is the indexed search vector when language is searchLanguage, and computed on the fly otherwise.
+func searchVector(language string) string {
+	if language == searchLanguage {
+		return "search_vector"
+	}
+
+	return "(" + searchVectorSQL + ")"
+}
+
+// Update overwrites the title and content of an article.
+// When article.Version is set, the update only happens if the stored version is still the same.
+func (d *ArticleDAO) Update(ctx context.Context, article Article) (Article, error) {
+	query := conn(ctx, d.db).Model(&Article{}).Where("id = ?", article.ID)
+	if article.Version != 0 {
+		query = query.Where("version = ?", article.Version)
+	}
+
+	result := query.Updates(map[string]any{
+		"title":   article.Title,
+		"content": article.Content,
+		"version": gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		if errors.Is(translateError(d.db, result.Error), gorm.ErrDuplicatedKey) {
+			return Article{}, ErrArticleDuplicated
+		}
+
+		return Article{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return Article{}, d.notAffectedErr(ctx, article.ID)
+	}
+
+	return d.FindByID(ctx, article.ID)
+}
+
+// Delete removes an article.
+// When version is set, the article is only removed if the stored version is still the same.
+func (d *ArticleDAO) Delete(ctx context.Context, id, version uint) error {
+	query := conn(ctx, d.db).Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&Article{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return d.notAffectedErr(ctx, id)
+	}
+
+	return nil
+}
+
+// preload loads relations along with articles, with one more query per relation rather than per article.
+func preload(tx *gorm.DB, relations []string) *gorm.DB {
+	for _, relation := range relations {
+		tx = tx.Preload(relation)
+	}
+
+	return tx
+}
+
+// notAffectedErr tells why a write matched no rows: either the article doesn't exist or its version has moved on.
+func (d *ArticleDAO) notAffectedErr(ctx context.Context, id uint) error {
+	if _, err := d.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return ErrArticleModified
+}
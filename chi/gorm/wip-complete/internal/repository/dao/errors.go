@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// mysqlDeadlock is the error of MySQL transactions rolled back to break a deadlock,
+// which is how conflicts of concurrent transactions surface with it.
+const mysqlDeadlock = 1213
+
+// translateError translates the unique and foreign key violations of any driver
+// to gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated, with the translator of the dialector of db.
+// So DAOs only depend on GORM to tell them apart, other errors are returned as is.
+func translateError(db *gorm.DB, err error) error {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+
+	return err
+}
+
+// isSerializationFailure reports whether err is a transaction that conflicted with a concurrent one,
+// and succeeds if it's run again. SQLite has no such errors, as its transactions never run concurrently.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgerrcode.SerializationFailure
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock
+	}
+
+	return false
+}
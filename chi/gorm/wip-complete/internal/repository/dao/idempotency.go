@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/idempotency"
+)
+
+type IdempotencyRecord struct {
+	Key string `gorm:"primaryKey"`
+
+	RequestHash string `gorm:"not null"`
+	Completed   bool   `gorm:"not null;default:false"`
+	StatusCode  int
+	Header      []byte // JSON encoded http.Header.
+	Body        []byte
+
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// IdempotencyDAO implements idempotency.Store with the database.
+type IdempotencyDAO struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyDAO(db *gorm.DB) *IdempotencyDAO {
+	return &IdempotencyDAO{
+		db: db,
+	}
+}
+
+func (d *IdempotencyDAO) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, idempotency.Record, error) {
+	now := time.Now().UTC()
+	db := conn(ctx, d.db)
+
+	// Expired records are deleted lazily, so the key can be reserved again.
+	result := db.Where("expires_at <= ?", now).Where(&IdempotencyRecord{Key: key}).Delete(&IdempotencyRecord{})
+	if result.Error != nil {
+		return false, idempotency.Record{}, result.Error
+	}
+
+	result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(ttl),
+	})
+	if result.Error != nil {
+		return false, idempotency.Record{}, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, idempotency.Record{}, nil
+	}
+
+	var existing IdempotencyRecord
+	result = db.Where(&IdempotencyRecord{Key: key}).First(&existing)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// Released in between, just try again.
+			return d.Reserve(ctx, key, requestHash, ttl)
+		}
+
+		return false, idempotency.Record{}, result.Error
+	}
+
+	var header http.Header
+	if len(existing.Header) > 0 {
+		if err := json.Unmarshal(existing.Header, &header); err != nil {
+			return false, idempotency.Record{}, fmt.Errorf("json.Unmarshal -> %w", err)
+		}
+	}
+
+	return false, idempotency.Record{
+		RequestHash: existing.RequestHash,
+		Completed:   existing.Completed,
+		StatusCode:  existing.StatusCode,
+		Header:      header,
+		Body:        existing.Body,
+	}, nil
+}
+
+func (d *IdempotencyDAO) Complete(ctx context.Context, key string, record idempotency.Record, ttl time.Duration) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("json.Marshal -> %w", err)
+	}
+
+	result := conn(ctx, d.db).Model(&IdempotencyRecord{}).Where(&IdempotencyRecord{Key: key}).Updates(map[string]any{
+		"completed":   true,
+		"status_code": record.StatusCode,
+		"header":      header,
+		"body":        record.Body,
+		"expires_at":  time.Now().UTC().Add(ttl),
+	})
+
+	return result.Error
+}
+
+func (d *IdempotencyDAO) Release(ctx context.Context, key string) error {
+	result := conn(ctx, d.db).Where(&IdempotencyRecord{Key: key}).Delete(&IdempotencyRecord{})
+
+	return result.Error
+}
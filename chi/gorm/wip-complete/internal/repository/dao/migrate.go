@@ -0,0 +1,40 @@
+package dao
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/migrate"
+)
+
+// MigrationsDir is where new migrations are created, relative to the project root.
+// Each dialect has its own directory of migrations, named after it.
+const MigrationsDir = "internal/repository/dao/migrations"
+
+// Dialects are the databases with migrations, named like the GORM dialectors.
+var Dialects = []string{"postgres", "mysql", "sqlite"}
+
+// migrations are embedded, so the binary can migrate the database it's deployed with.
+//
+//go:embed migrations/*/*.sql
+var migrations embed.FS
+
+// NewMigrator returns a migrator of the migrations of the dialect of db.
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("db.DB -> %w", err)
+	}
+
+	dialect := db.Dialector.Name()
+	fsys, err := fs.Sub(migrations, path.Join("migrations", dialect))
+	if err != nil {
+		return nil, fmt.Errorf("fs.Sub -> %w", err)
+	}
+
+	return migrate.New(sqlDB, fsys, dialect)
+}
@@ -0,0 +1,13 @@
+-- Articles are searched with bleve, there's no full-text index.
+CREATE TABLE articles (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    user_id bigint unsigned NOT NULL,
+    title varchar(255) NOT NULL,
+    content longtext NOT NULL,
+    version bigint unsigned NOT NULL DEFAULT 1,
+    created_at datetime(6) NOT NULL,
+    updated_at datetime(6) NOT NULL,
+    CONSTRAINT fk_articles_user FOREIGN KEY (user_id) REFERENCES users (id),
+    UNIQUE INDEX idx_user_id_title (user_id, title),
+    INDEX idx_articles_created_at_id (created_at, id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_records (
+    `key` varchar(255) PRIMARY KEY,
+    request_hash varchar(255) NOT NULL,
+    completed boolean NOT NULL DEFAULT false,
+    status_code bigint,
+    header blob,
+    body longblob,
+    expires_at datetime(6) NOT NULL,
+    created_at datetime(6) NOT NULL,
+    updated_at datetime(6) NOT NULL,
+    INDEX idx_idempotency_records_expires_at (expires_at)
+);
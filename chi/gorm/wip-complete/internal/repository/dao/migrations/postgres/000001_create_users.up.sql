@@ -0,0 +1,18 @@
+-- IF NOT EXISTS adopts the users table of databases that were created by GORM's AutoMigrate,
+-- the columns and constraints it lacks are added explicitly below.
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    email text NOT NULL,
+    password text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin boolean NOT NULL DEFAULT false;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'users'::regclass AND conname = 'uni_users_email') THEN
+        ALTER TABLE users ADD CONSTRAINT uni_users_email UNIQUE (email);
+    END IF;
+END $$;
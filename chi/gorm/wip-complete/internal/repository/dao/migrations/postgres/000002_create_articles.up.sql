@@ -0,0 +1,30 @@
+-- IF NOT EXISTS adopts the articles table of databases that were created by GORM's AutoMigrate,
+-- the columns, constraints and indexes it lacks are added explicitly below.
+CREATE TABLE IF NOT EXISTS articles (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    title text NOT NULL,
+    content text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+-- Titles weigh more than contents in full-text search, see dao.searchLanguage.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+) STORED;
+
+-- Adopting fails on articles of users that don't exist, they have to be cleaned up first.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'articles'::regclass AND conname = 'fk_articles_user') THEN
+        ALTER TABLE articles ADD CONSTRAINT fk_articles_user FOREIGN KEY (user_id) REFERENCES users (id);
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_id_title ON articles (user_id, title);
+CREATE INDEX IF NOT EXISTS idx_articles_created_at_id ON articles (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING gin (search_vector);
@@ -0,0 +1,13 @@
+-- Articles are searched with bleve, SQLite has no search vector.
+CREATE TABLE articles (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    user_id integer NOT NULL CONSTRAINT fk_articles_user REFERENCES users (id),
+    title text NOT NULL,
+    content text NOT NULL,
+    version integer NOT NULL DEFAULT 1,
+    created_at datetime NOT NULL,
+    updated_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_user_id_title ON articles (user_id, title);
+CREATE INDEX idx_articles_created_at_id ON articles (created_at, id);
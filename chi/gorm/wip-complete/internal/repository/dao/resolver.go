@@ -0,0 +1,18 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ReplicasResolver names the dbresolver resolver of the read replicas.
+// It's only used by reads asking for it, so that everything else reads what it just wrote.
+const ReplicasResolver = "replicas"
+
+// readConn is conn for reads that can lag a little behind writes, like listing and searching.
+// They go to a read replica when there are some, and to the transaction of ctx if there's one.
+func readConn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return conn(ctx, db).Clauses(dbresolver.Use(ReplicasResolver))
+}
@@ -3,13 +3,9 @@ package dao
 import (
 	"context"
 	"errors"
-	"strings"
 	"time"
 
 	"gorm.io/gorm"
-
-	"github.com/jackc/pgerrcode"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 var (
@@ -22,6 +18,7 @@ type User struct {
 
 	Email    string `gorm:"unique;not null"`
 	Password string `gorm:"not null"`
+	IsAdmin  bool   `gorm:"not null;default:false"`
 
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
@@ -38,12 +35,10 @@ func NewUserDAO(db *gorm.DB) *UserDAO {
 }
 
 func (d *UserDAO) Insert(ctx context.Context, user User) (User, error) {
-	result := d.db.WithContext(ctx).Create(&user)
+	result := conn(ctx, d.db).Create(&user)
 	if result.Error != nil {
-		var err *pgconn.PgError
-		if errors.As(result.Error, &err) &&
-			err.Code == pgerrcode.UniqueViolation &&
-			strings.Contains(err.Message, `unique constraint "uni_users_email"`) {
+		// The email is the only unique column besides the id, which is generated.
+		if errors.Is(translateError(d.db, result.Error), gorm.ErrDuplicatedKey) {
 			return User{}, ErrUserEmailExists
 		}
 
@@ -56,7 +51,7 @@ func (d *UserDAO) Insert(ctx context.Context, user User) (User, error) {
 func (d *UserDAO) FindByID(ctx context.Context, id uint) (User, error) {
 	var user User
 
-	result := d.db.WithContext(ctx).First(&user, id)
+	result := conn(ctx, d.db).First(&user, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return User{}, ErrUserNotFound
@@ -71,7 +66,7 @@ func (d *UserDAO) FindByID(ctx context.Context, id uint) (User, error) {
 func (d *UserDAO) FindByEmail(ctx context.Context, email string) (User, error) {
 	var user User
 
-	result := d.db.WithContext(ctx).First(&user, "email = ?", email)
+	result := conn(ctx, d.db).First(&user, "email = ?", email)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return User{}, ErrUserNotFound
@@ -33,6 +33,7 @@ func (r *UserRepository) Create(ctx context.Context, user domain.User) (domain.U
 	created, err := r.dao.Insert(ctx, dao.User{
 		Email:    user.Email,
 		Password: user.Password,
+		IsAdmin:  user.IsAdmin,
 	})
 	if err != nil {
 		return domain.User{}, fmt.Errorf("r.dao.Insert -> %w", err)
@@ -64,6 +65,7 @@ func (r *UserRepository) daoToDomain(u dao.User) domain.User {
 		ID:        u.ID,
 		Email:     u.Email,
 		Password:  u.Password,
+		IsAdmin:   u.IsAdmin,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
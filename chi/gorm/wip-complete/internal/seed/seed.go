@@ -0,0 +1,221 @@
+// Package seed loads users and articles described in YAML files into the database, through the services.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"gopkg.in/yaml.v3"
+
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/service"
+)
+
+type Fixtures struct {
+	Users    []User    `yaml:"users"`
+	Articles []Article `yaml:"articles"`
+}
+
+// User is a user to seed, its password is in plaintext and hashed when seeded.
+type User struct {
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"`
+	IsAdmin  bool   `yaml:"is_admin"`
+}
+
+// Article is an article to seed, written by the user whose email is Author.
+type Article struct {
+	Author  string `yaml:"author"`
+	Title   string `yaml:"title"`
+	Content string `yaml:"content"`
+}
+
+func (f Fixtures) Validate() error {
+	return validation.ValidateStruct(
+		&f,
+		validation.Field(&f.Users),
+		validation.Field(&f.Articles),
+	)
+}
+
+func (u User) Validate() error {
+	return validation.ValidateStruct(
+		&u,
+		validation.Field(&u.Email, validation.Required, is.EmailFormat),
+		validation.Field(&u.Password, validation.Required),
+	)
+}
+
+func (a Article) Validate() error {
+	return validation.ValidateStruct(
+		&a,
+		validation.Field(&a.Author, validation.Required, is.EmailFormat),
+		validation.Field(&a.Title, validation.Required),
+		validation.Field(&a.Content, validation.Required),
+	)
+}
+
+// Load reads and validates the fixtures of all the files, in order.
+// Unknown fields are rejected, so that typos don't go unnoticed.
+func Load(paths ...string) (Fixtures, error) {
+	var fixtures Fixtures
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return Fixtures{}, fmt.Errorf("os.Open -> %w", err)
+		}
+
+		var f Fixtures
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(&f)
+		file.Close()
+		if err != nil {
+			return Fixtures{}, fmt.Errorf("failed to decode %v -> %w", path, err)
+		}
+
+		fixtures.Users = append(fixtures.Users, f.Users...)
+		fixtures.Articles = append(fixtures.Articles, f.Articles...)
+	}
+
+	if err := fixtures.Validate(); err != nil {
+		return Fixtures{}, fmt.Errorf("invalid fixtures -> %w", err)
+	}
+
+	return fixtures, nil
+}
+
+type AuthService interface {
+	Signup(ctx context.Context, user domain.User) (domain.User, error)
+}
+
+type UserService interface {
+	GetUserByEmail(ctx context.Context, email string) (domain.User, error)
+}
+
+type ArticleService interface {
+	CreateArticle(ctx context.Context, caller domain.Caller, article domain.Article) (domain.Article, error)
+}
+
+// Result counts the users and articles created, and the ones skipped as they already existed.
+type Result struct {
+	Users    int
+	Articles int
+	Skipped  int
+}
+
+type Seeder struct {
+	authSvc    AuthService
+	userSvc    UserService
+	articleSvc ArticleService
+	txm        service.TxManager
+}
+
+func NewSeeder(authSvc AuthService, userSvc UserService, articleSvc ArticleService, txm service.TxManager) *Seeder {
+	return &Seeder{
+		authSvc:    authSvc,
+		userSvc:    userSvc,
+		articleSvc: articleSvc,
+		txm:        txm,
+	}
+}
+
+// Seed creates the users then the articles of fixtures, in a single transaction so that a failed seed
+// doesn't leave users without their articles. The search index isn't part of the transaction, with the bleve backend
+// it may keep the articles of a failed seed until it's rebuilt.
+// Existing users and articles are left as they are, so seeding again only creates what's missing.
+func (s *Seeder) Seed(ctx context.Context, fixtures Fixtures) (Result, error) {
+	var result Result
+
+	// The articles are indexed as they're created, so the seed can't be retried.
+	err := s.txm.WithinTxOnce(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.seed(ctx, fixtures)
+
+		return err
+	})
+	if err != nil {
+		// Nothing is left of a failed seed.
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+func (s *Seeder) seed(ctx context.Context, fixtures Fixtures) (Result, error) {
+	var result Result
+
+	userIDs := make(map[string]uint)
+	for _, u := range fixtures.Users {
+		var created domain.User
+		err := s.skippable(ctx, func(ctx context.Context) error {
+			var err error
+			created, err = s.authSvc.Signup(ctx, domain.User{
+				Email:    u.Email,
+				Password: u.Password,
+				IsAdmin:  u.IsAdmin,
+			})
+
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrUserEmailExists) {
+				result.Skipped++
+
+				continue
+			}
+
+			return result, fmt.Errorf("failed to seed user %v -> %w", u.Email, err)
+		}
+
+		userIDs[u.Email] = created.ID
+		result.Users++
+	}
+
+	for _, a := range fixtures.Articles {
+		userID, ok := userIDs[a.Author]
+		if !ok {
+			author, err := s.userSvc.GetUserByEmail(ctx, a.Author)
+			if err != nil {
+				return result, fmt.Errorf("failed to find author %v of article %q -> %w", a.Author, a.Title, err)
+			}
+
+			userID = author.ID
+			userIDs[a.Author] = userID
+		}
+
+		err := s.skippable(ctx, func(ctx context.Context) error {
+			// Articles are seeded on behalf of their authors, which only admins may do.
+			_, err := s.articleSvc.CreateArticle(ctx, domain.Caller{IsAdmin: true}, domain.Article{
+				UserID:  userID,
+				Title:   a.Title,
+				Content: a.Content,
+			})
+
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrArticleDuplicated) {
+				result.Skipped++
+
+				continue
+			}
+
+			return result, fmt.Errorf("failed to seed article %q -> %w", a.Title, err)
+		}
+
+		result.Articles++
+	}
+
+	return result, nil
+}
+
+// skippable runs fn within a savepoint, so that users and articles skipped as they already exist
+// don't fail the transaction of the seed.
+func (s *Seeder) skippable(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.txm.WithinTx(ctx, fn)
+}
@@ -2,45 +2,86 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+
+	"go.uber.org/zap"
 
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/domain"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/logger"
+	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/pkg/listquery"
 	"github.com/yizeng/gab/chi/gorm/wip-complete/internal/repository"
 )
 
 var (
 	ErrArticleDuplicated = repository.ErrArticleDuplicated
 	ErrArticleNotFound   = repository.ErrArticleNotFound
+	ErrArticleModified   = repository.ErrArticleModified
+	ErrSearchUnsupported = repository.ErrSearchUnsupported
+
+	ErrImportRolledBack = errors.New("import is rolled back as some articles can't be created")
+	ErrPermissionDenied = errors.New("articles can only be changed by their author or an admin")
 )
 
 type ArticleRepository interface {
 	Create(ctx context.Context, article domain.Article) (domain.Article, error)
-	FindByID(ctx context.Context, id uint) (domain.Article, error)
-	FindAll(ctx context.Context, page, perPage uint) ([]domain.Article, error)
-	Search(ctx context.Context, title, content string) ([]domain.Article, error)
+	FindByID(ctx context.Context, id uint, relations domain.ArticleRelations) (domain.Article, error)
+	FindAll(ctx context.Context, page, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) ([]domain.Article, error)
+	FindByCursor(ctx context.Context, cursor *domain.Cursor, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) (domain.Page[domain.Article], error)
+	Export(ctx context.Context, spec listquery.Spec, export domain.ArticleExport, fn func(domain.Article) error) error
+	Count(ctx context.Context, spec listquery.Spec) (int64, error)
+	Update(ctx context.Context, article domain.Article) (domain.Article, error)
+	Delete(ctx context.Context, id, version uint) error
+}
+
+// SearchIndex finds articles by full-text search, ArticleService keeps it in sync with the repository.
+type SearchIndex interface {
+	Search(ctx context.Context, search domain.ArticleSearch) (domain.ArticleSearchResult, error)
+
+	// Index adds articles to the index, or replaces them if they're indexed already.
+	Index(ctx context.Context, articles ...domain.Article) error
+
+	// Remove deletes an article from the index.
+	Remove(ctx context.Context, id uint) error
+
+	// Clear deletes all articles from the index.
+	Clear(ctx context.Context) error
 }
 
 type ArticleService struct {
-	repo ArticleRepository
+	repo  ArticleRepository
+	index SearchIndex
+	txm   TxManager
 }
 
-func NewArticleService(repo ArticleRepository) *ArticleService {
+func NewArticleService(repo ArticleRepository, index SearchIndex, txm TxManager) *ArticleService {
 	return &ArticleService{
-		repo: repo,
+		repo:  repo,
+		index: index,
+		txm:   txm,
 	}
 }
 
-func (s *ArticleService) CreateArticle(ctx context.Context, article domain.Article) (domain.Article, error) {
+// CreateArticle creates an article written by the caller, or by anyone if the caller is an admin.
+func (s *ArticleService) CreateArticle(ctx context.Context, caller domain.Caller, article domain.Article) (domain.Article, error) {
+	if !caller.Owns(article.UserID) {
+		return domain.Article{}, ErrPermissionDenied
+	}
+
 	created, err := s.repo.Create(ctx, article)
 	if err != nil {
 		return domain.Article{}, fmt.Errorf("s.repo.Create -> %w", err)
 	}
 
+	s.syncIndex(ctx, s.index.Index(ctx, created), created.ID)
+
 	return created, nil
 }
 
-func (s *ArticleService) GetArticle(ctx context.Context, id uint) (domain.Article, error) {
-	article, err := s.repo.FindByID(ctx, id)
+func (s *ArticleService) GetArticle(ctx context.Context, id uint, relations domain.ArticleRelations) (domain.Article, error) {
+	article, err := s.repo.FindByID(ctx, id, relations)
 	if err != nil {
 		return domain.Article{}, fmt.Errorf("s.repo.FindByID -> %w", err)
 	}
@@ -48,8 +89,8 @@ func (s *ArticleService) GetArticle(ctx context.Context, id uint) (domain.Articl
 	return article, nil
 }
 
-func (s *ArticleService) ListArticles(ctx context.Context, page, perPage uint) ([]domain.Article, error) {
-	articles, err := s.repo.FindAll(ctx, page, perPage)
+func (s *ArticleService) ListArticles(ctx context.Context, page, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) ([]domain.Article, error) {
+	articles, err := s.repo.FindAll(ctx, page, perPage, spec, relations)
 	if err != nil {
 		return nil, fmt.Errorf("s.repo.FindAll -> %w", err)
 	}
@@ -57,11 +98,318 @@ func (s *ArticleService) ListArticles(ctx context.Context, page, perPage uint) (
 	return articles, nil
 }
 
-func (s *ArticleService) SearchArticles(ctx context.Context, title, content string) ([]domain.Article, error) {
-	articles, err := s.repo.Search(ctx, title, content)
+// ListArticlesByCursor lists articles by (created_at, id), a nil cursor returns the first page.
+func (s *ArticleService) ListArticlesByCursor(ctx context.Context, cursor *domain.Cursor, perPage uint, spec listquery.Spec, relations domain.ArticleRelations) (domain.Page[domain.Article], error) {
+	page, err := s.repo.FindByCursor(ctx, cursor, perPage, spec, relations)
 	if err != nil {
-		return nil, fmt.Errorf("s.repo.Search -> %w", err)
+		return domain.Page[domain.Article]{}, fmt.Errorf("s.repo.FindByCursor -> %w", err)
 	}
 
-	return articles, nil
+	return page, nil
+}
+
+// ExportArticles calls fn with each article matching spec and export as they're read from the repository,
+// so exports of any size take the same memory. It stops as soon as ctx is done or fn fails.
+func (s *ArticleService) ExportArticles(ctx context.Context, spec listquery.Spec, export domain.ArticleExport, fn func(domain.Article) error) error {
+	if err := s.repo.Export(ctx, spec, export, fn); err != nil {
+		return fmt.Errorf("s.repo.Export -> %w", err)
+	}
+
+	return nil
+}
+
+func (s *ArticleService) CountArticles(ctx context.Context, spec listquery.Spec) (int64, error) {
+	count, err := s.repo.Count(ctx, spec)
+	if err != nil {
+		return 0, fmt.Errorf("s.repo.Count -> %w", err)
+	}
+
+	return count, nil
+}
+
+// SearchArticles runs a full-text search of articles, the most relevant articles come first.
+func (s *ArticleService) SearchArticles(ctx context.Context, search domain.ArticleSearch) (domain.ArticleSearchResult, error) {
+	result, err := s.index.Search(ctx, search)
+	if err != nil {
+		return domain.ArticleSearchResult{}, fmt.Errorf("s.index.Search -> %w", err)
+	}
+
+	return result, nil
+}
+
+// RebuildSearchIndex indexes all articles of the repository from scratch and returns how many there are.
+func (s *ArticleService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	const batchSize = 100
+
+	if err := s.index.Clear(ctx); err != nil {
+		return 0, fmt.Errorf("s.index.Clear -> %w", err)
+	}
+
+	indexed := 0
+	var cursor *domain.Cursor
+	for {
+		page, err := s.repo.FindByCursor(ctx, cursor, batchSize, listquery.Spec{}, domain.ArticleRelations{})
+		if err != nil {
+			return indexed, fmt.Errorf("s.repo.FindByCursor -> %w", err)
+		}
+
+		if err = s.index.Index(ctx, page.Items...); err != nil {
+			return indexed, fmt.Errorf("s.index.Index -> %w", err)
+		}
+		indexed += len(page.Items)
+
+		if page.Next == nil {
+			return indexed, nil
+		}
+		cursor = page.Next
+	}
+}
+
+// UpdateArticle updates the title and content of an article of the caller, or of anyone if the caller is an admin.
+// article.Version is the version the change is based on, zero skips the concurrency check.
+func (s *ArticleService) UpdateArticle(ctx context.Context, caller domain.Caller, article domain.Article) (domain.Article, error) {
+	// The author is checked in the transaction of the update, so that they're both based on the same article.
+	var updated domain.Article
+	err := s.txm.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkAuthor(ctx, caller, article.ID); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = s.repo.Update(ctx, article)
+		if err != nil {
+			return fmt.Errorf("s.repo.Update -> %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("s.txm.WithinTx -> %w", err)
+	}
+
+	s.syncIndex(ctx, s.index.Index(ctx, updated), updated.ID)
+
+	return updated, nil
+}
+
+// DeleteArticle deletes an article of the caller, or of anyone if the caller is an admin.
+// version is the version the deletion is based on, zero skips the concurrency check.
+func (s *ArticleService) DeleteArticle(ctx context.Context, caller domain.Caller, id, version uint) error {
+	err := s.txm.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkAuthor(ctx, caller, id); err != nil {
+			return err
+		}
+
+		if err := s.repo.Delete(ctx, id, version); err != nil {
+			return fmt.Errorf("s.repo.Delete -> %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("s.txm.WithinTx -> %w", err)
+	}
+
+	s.syncIndex(ctx, s.index.Remove(ctx, id), id)
+
+	return nil
+}
+
+// checkAuthor fails with ErrPermissionDenied unless the caller may change the article of id.
+func (s *ArticleService) checkAuthor(ctx context.Context, caller domain.Caller, id uint) error {
+	if caller.IsAdmin {
+		return nil
+	}
+
+	article, err := s.repo.FindByID(ctx, id, domain.ArticleRelations{})
+	if err != nil {
+		return fmt.Errorf("s.repo.FindByID -> %w", err)
+	}
+
+	if !caller.Owns(article.UserID) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// ImportArticles creates the articles of the rows returned by next until it returns io.EOF.
+// Rows are created in transactions of batchSize rows, then passed to report with either the created
+// article or why it isn't created, e.g. ErrArticleDuplicated. A failed row doesn't fail the import.
+// Like with CreateArticle, only admins can import articles of other users than the caller.
+// When atomic, all rows are created in a single transaction, which is rolled back with ErrImportRolledBack
+// unless every row is created. Rows are still reported as they're processed, before the import is committed.
+func (s *ArticleService) ImportArticles(
+	ctx context.Context,
+	caller domain.Caller,
+	batchSize int,
+	atomic bool,
+	next func() (domain.ArticleImportRow, error),
+	report func(rows []domain.ArticleImportRow) error,
+) error {
+	if atomic {
+		// Rows are reported from within the transaction, which therefore can't be retried.
+		var imported []domain.Article
+		err := s.txm.WithinTxOnce(ctx, func(ctx context.Context) error {
+			failed := false
+			for done := false; !done; {
+				rows, err := nextBatch(next, batchSize)
+				done = errors.Is(err, io.EOF)
+				if err != nil && !done {
+					return err
+				}
+				if len(rows) == 0 {
+					continue
+				}
+
+				batchFailed, err := s.importRows(ctx, caller, rows)
+				if err != nil {
+					return err
+				}
+				failed = failed || batchFailed
+
+				if err = report(rows); err != nil {
+					return fmt.Errorf("report -> %w", err)
+				}
+				imported = append(imported, createdArticles(rows)...)
+			}
+
+			if failed {
+				return ErrImportRolledBack
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("s.txm.WithinTxOnce -> %w", err)
+		}
+
+		s.syncImported(ctx, imported)
+
+		return nil
+	}
+
+	for done := false; !done; {
+		rows, err := nextBatch(next, batchSize)
+		done = errors.Is(err, io.EOF)
+		if err != nil && !done {
+			return err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		// Each attempt imports a copy of the rows, so that a retry starts over from the rows as they were read.
+		err = s.txm.WithinTx(ctx, func(ctx context.Context) error {
+			attempt := slices.Clone(rows)
+			if _, err := s.importRows(ctx, caller, attempt); err != nil {
+				return err
+			}
+
+			copy(rows, attempt)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("s.txm.WithinTx -> %w", err)
+		}
+
+		s.syncImported(ctx, createdArticles(rows))
+
+		if err = report(rows); err != nil {
+			return fmt.Errorf("report -> %w", err)
+		}
+	}
+
+	return nil
+}
+
+// nextBatch reads up to batchSize rows with next, io.EOF is returned along with the last rows.
+func nextBatch(next func() (domain.ArticleImportRow, error), batchSize int) ([]domain.ArticleImportRow, error) {
+	rows := make([]domain.ArticleImportRow, 0, batchSize)
+	for len(rows) < batchSize {
+		row, err := next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, io.EOF
+			}
+
+			return nil, fmt.Errorf("next -> %w", err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// importRows creates the articles of rows within the transaction of ctx and tells whether any row failed.
+// Each article is created within a savepoint, so one that can't be created doesn't abort the transaction.
+func (s *ArticleService) importRows(ctx context.Context, caller domain.Caller, rows []domain.ArticleImportRow) (bool, error) {
+	failed := false
+	for i := range rows {
+		if rows[i].Err == nil && !caller.Owns(rows[i].Article.UserID) {
+			rows[i].Err = ErrPermissionDenied
+		}
+		if rows[i].Err != nil {
+			failed = true
+
+			continue
+		}
+
+		var created domain.Article
+		err := s.txm.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			created, err = s.repo.Create(ctx, rows[i].Article)
+
+			return err
+		})
+		switch {
+		case err == nil:
+			rows[i].Article = created
+		case errors.Is(err, ErrArticleDuplicated), errors.Is(err, ErrUserNotFound):
+			rows[i].Err = err
+			failed = true
+		default:
+			return failed, fmt.Errorf("s.repo.Create -> %w", err)
+		}
+	}
+
+	return failed, nil
+}
+
+// createdArticles returns the articles of the rows that are created.
+func createdArticles(rows []domain.ArticleImportRow) []domain.Article {
+	articles := make([]domain.Article, 0, len(rows))
+	for _, row := range rows {
+		if row.Err == nil {
+			articles = append(articles, row.Article)
+		}
+	}
+
+	return articles
+}
+
+// syncImported indexes the articles of an import once they're committed.
+func (s *ArticleService) syncImported(ctx context.Context, articles []domain.Article) {
+	if len(articles) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+	s.syncIndex(ctx, s.index.Index(ctx, articles...), ids...)
+}
+
+// syncIndex reports a failed update of the search index for articles ids. The change is already stored,
+// so it isn't failed because of the index, which can be rebuilt from the repository.
+func (s *ArticleService) syncIndex(ctx context.Context, err error, ids ...uint) {
+	if err == nil {
+		return
+	}
+
+	fields := append(logger.FieldsFromContext(ctx), zap.Uints("articleIDs", ids), zap.Error(err))
+	zap.L().Error("search index is out of sync, rebuild it with the reindex command", fields...)
 }
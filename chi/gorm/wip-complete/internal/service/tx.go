@@ -0,0 +1,14 @@
+package service
+
+import "context"
+
+// TxManager is a unit of work: repositories called with the context given to fn run within its transaction.
+type TxManager interface {
+	// WithinTx runs fn in a transaction, which is committed when fn returns nil and rolled back otherwise.
+	// Nested calls run within savepoints. fn may be run again after a serialization failure,
+	// so it must not have side effects outside the repositories.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// WithinTxOnce is WithinTx without retries, for fn with side effects that can't be repeated.
+	WithinTxOnce(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,7 +2,6 @@ package dockertester
 
 import (
 	"fmt"
-	"log"
 	"math/rand"
 	"time"
 
@@ -30,8 +29,9 @@ type Dockertester struct {
 }
 
 // InitPostgres function initialize dockertest for PostgreSQL.
+// It fails rather than exits when Docker isn't available, so tests can fall back to other databases.
 // Please refer to the official example here: https://github.com/ory/dockertest/blob/v3/examples/PostgreSQL.md
-func InitPostgres() *Dockertester {
+func InitPostgres() (*Dockertester, error) {
 	// create a random port number between min and max to avoid conflicts.
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	hostPort := fmt.Sprint(r.Intn(maxPort-minPort) + minPort)
@@ -39,11 +39,11 @@ func InitPostgres() *Dockertester {
 	// uses a sensible default on windows (tcp/http) and linux/osx (socket)
 	pool, err := dockertest.NewPool("")
 	if err != nil {
-		log.Fatalf("Could not construct pool: %s", err)
+		return nil, fmt.Errorf("could not construct pool: %w", err)
 	}
 
 	if err = pool.Client.Ping(); err != nil {
-		log.Fatalf("Could not connect to docker: %s", err)
+		return nil, fmt.Errorf("could not connect to docker: %w", err)
 	}
 
 	// pulls an image, creates a container based on it and runs it
@@ -68,7 +68,7 @@ func InitPostgres() *Dockertester {
 		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
 	})
 	if err != nil {
-		log.Fatalf("Could not start resource: %s", err)
+		return nil, fmt.Errorf("could not start resource: %w", err)
 	}
 
 	resource.Expire(120) // Tell docker to hard kill the container in 120 seconds
@@ -89,14 +89,16 @@ func InitPostgres() *Dockertester {
 
 		return sqlDB.Ping()
 	}); err != nil {
-		log.Fatalf("Could not connect to docker: %s", err)
+		_ = pool.Purge(resource)
+
+		return nil, fmt.Errorf("could not connect to postgres: %w", err)
 	}
 
 	return &Dockertester{
 		HostPort: hostPort,
 		Pool:     pool,
 		Resource: resource,
-	}
+	}, nil
 }
 
 func OpenPostgres(resource *dockertest.Resource, port string) (*gorm.DB, error) {
@@ -94,6 +94,10 @@ const docTemplate = `{
         "response.Err": {
             "type": "object",
             "properties": {
+                "column": {
+                    "description": "column of malformed JSON",
+                    "type": "integer"
+                },
                 "error": {
                     "description": "user-facing error message",
                     "type": "string"
@@ -101,6 +105,42 @@ const docTemplate = `{
                 "error_code": {
                     "description": "application-specific error code",
                     "type": "integer"
+                },
+                "errors": {
+                    "description": "invalid fields of the request",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "line": {
+                    "description": "line of malformed JSON",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "stable code of the failed rule",
+                    "type": "string",
+                    "example": "validation_length_out_of_range"
+                },
+                "field": {
+                    "description": "path of the field, made of JSON names",
+                    "type": "string",
+                    "example": "states[2].population"
+                },
+                "message": {
+                    "description": "user-facing message",
+                    "type": "string",
+                    "example": "the length must be between 1 and 128"
+                },
+                "params": {
+                    "description": "parameters of the failed rule, e.g. min and max",
+                    "type": "object",
+                    "additionalProperties": {}
                 }
             }
         }
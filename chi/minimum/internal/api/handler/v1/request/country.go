@@ -3,7 +3,7 @@ package request
 import (
 	"net/http"
 
-	validation "github.com/go-ozzo/ozzo-validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 
 	"github.com/yizeng/gab/chi/minimum/internal/domain"
 )
@@ -16,21 +16,9 @@ func (req *SumPopulationByState) Validate() error {
 	return validation.ValidateStruct(
 		req,
 		validation.Field(&req.States, validation.Required),
-		validation.Field(&req.States, validation.By(eachState)),
 	)
 }
 
-func eachState(value any) error {
-	states, _ := value.([]domain.State)
-	for _, s := range states {
-		if err := s.Validate(); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func (req *SumPopulationByState) Bind(r *http.Request) error {
 	if err := req.Validate(); err != nil {
 		return err
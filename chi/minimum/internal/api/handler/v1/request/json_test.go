@@ -0,0 +1,80 @@
+package request
+
+import (
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type state struct {
+		Population uint `json:"population"`
+	}
+	type body struct {
+		States []state `json:"states"`
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		wantSyntax *SyntaxError
+		wantField  string
+	}{
+		{
+			name:  "Valid JSON",
+			input: `{"states":[{"population":1}]}`,
+		},
+		{
+			name:       "Truncated JSON",
+			input:      "[",
+			wantSyntax: &SyntaxError{Line: 1, Column: 2, Msg: "unexpected end of JSON input"},
+		},
+		{
+			name:       "Empty body",
+			input:      "",
+			wantSyntax: &SyntaxError{Line: 1, Column: 1, Msg: "unexpected end of JSON input"},
+		},
+		{
+			name:       "Invalid character on another line",
+			input:      "{\n  \"states\": [],\n  x\n}",
+			wantSyntax: &SyntaxError{Line: 3, Column: 3, Msg: "invalid character 'x' looking for beginning of object key string"},
+		},
+		{
+			name:      "Wrong type",
+			input:     `{"states":[{},{"population":"many"}]}`,
+			wantField: "states.1.population",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v body
+			err := DecodeJSON(strings.NewReader(tt.input), &v)
+
+			switch {
+			case tt.wantSyntax != nil:
+				var syntaxErr *SyntaxError
+				require.ErrorAs(t, err, &syntaxErr)
+				assert.Equal(t, tt.wantSyntax, syntaxErr)
+			case tt.wantField != "":
+				var errs validation.Errors
+				require.ErrorAs(t, err, &errs)
+
+				var fieldErr error = errs
+				for _, key := range strings.Split(tt.wantField, ".") {
+					fieldErr = fieldErr.(validation.Errors)[key]
+				}
+
+				var ruleErr validation.Error
+				require.ErrorAs(t, fieldErr, &ruleErr)
+				assert.Equal(t, codeTypeMismatch, ruleErr.Code())
+				assert.Equal(t, "must be a number", ruleErr.Error())
+			default:
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, v.States[0].Population)
+			}
+		})
+	}
+}
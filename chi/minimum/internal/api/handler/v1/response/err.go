@@ -1,10 +1,14 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/render"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/chi/minimum/internal/api/handler/v1/request"
 )
 
 type Err struct {
@@ -14,6 +18,10 @@ type Err struct {
 
 	ErrorCode int    `json:"error_code,omitempty"` // application-specific error code
 	ErrorMsg  string `json:"error"`                // user-facing error message
+
+	Errors []FieldError `json:"errors,omitempty"` // invalid fields of the request
+	Line   int          `json:"line,omitempty"`   // line of malformed JSON
+	Column int          `json:"column,omitempty"` // column of malformed JSON
 }
 
 func (e *Err) Render(w http.ResponseWriter, r *http.Request) error {
@@ -27,9 +35,36 @@ func (e *Err) Render(w http.ResponseWriter, r *http.Request) error {
 }
 
 func ErrBadRequest(err error) *Err {
+	var syntaxErr *request.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return ErrMalformedJSON(syntaxErr)
+	}
+
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		return ErrValidation(validationErrs)
+	}
+
+	return &Err{
+		statusCode: http.StatusBadRequest,
+		ErrorMsg:   err.Error(),
+	}
+}
+
+func ErrValidation(errs validation.Errors) *Err {
+	return &Err{
+		statusCode: http.StatusBadRequest,
+		ErrorMsg:   errs.Error(),
+		Errors:     fieldErrors("", errs),
+	}
+}
+
+func ErrMalformedJSON(err *request.SyntaxError) *Err {
 	return &Err{
 		statusCode: http.StatusBadRequest,
 		ErrorMsg:   err.Error(),
+		Line:       err.Line,
+		Column:     err.Column,
 	}
 }
 
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yizeng/gab/chi/minimum/internal/api/handler/v1/request"
+)
+
+func TestErrBadRequest_Validation(t *testing.T) {
+	type state struct {
+		Name       string `json:"name"`
+		Population uint   `json:"population"`
+	}
+	states := make([]state, 11)
+	states[2] = state{Name: "Texas"}
+	states[10] = state{Population: 1}
+
+	err := validation.Errors{
+		"title": validation.Validate("", validation.Required),
+		"states": validation.Validate(states, validation.Each(validation.By(func(value any) error {
+			s := value.(state)
+
+			return validation.ValidateStruct(&s,
+				validation.Field(&s.Name, validation.Required, validation.Length(1, 3)),
+				validation.Field(&s.Population, validation.Required),
+			)
+		}))),
+	}.Filter()
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, http.StatusBadRequest, got.statusCode)
+	assert.Equal(t, []FieldError{
+		{Field: "states[0].name", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[0].population", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[1].name", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[1].population", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[2].name", Code: "validation_length_out_of_range", Message: "the length must be between 1 and 3", Params: map[string]any{"min": 1, "max": 3}},
+		{Field: "states[2].population", Code: "validation_required", Message: "cannot be blank"},
+	}, got.Errors[:6])
+	assert.Equal(t, FieldError{Field: "states[10].name", Code: "validation_required", Message: "cannot be blank"}, got.Errors[len(got.Errors)-2])
+	assert.Equal(t, FieldError{Field: "title", Code: "validation_required", Message: "cannot be blank"}, got.Errors[len(got.Errors)-1])
+}
+
+func TestErrBadRequest_MalformedJSON(t *testing.T) {
+	var v map[string]any
+	err := request.DecodeJSON(strings.NewReader("{\n  x\n}"), &v)
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, http.StatusBadRequest, got.statusCode)
+	assert.Equal(t, 2, got.Line)
+	assert.Equal(t, 3, got.Column)
+	assert.Empty(t, got.Errors)
+}
@@ -1,7 +1,7 @@
 package domain
 
 import (
-	validation "github.com/go-ozzo/ozzo-validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type State struct {
@@ -9,9 +9,10 @@ type State struct {
 	Population uint   `json:"population" validate:"required"`
 }
 
-func (s *State) Validate() error {
+// Validate has a value receiver, so slices of State are validated element by element.
+func (s State) Validate() error {
 	return validation.ValidateStruct(
-		s,
+		&s,
 		validation.Field(&s.Name, validation.Required),
 		validation.Field(&s.Population, validation.Required),
 	)
@@ -0,0 +1,8 @@
+package response
+
+import "github.com/yizeng/gab/chi/sqlc/auth-jwt/internal/domain"
+
+type LoginResponse struct {
+	Token string      `json:"token"`
+	User  domain.User `json:"user"`
+}
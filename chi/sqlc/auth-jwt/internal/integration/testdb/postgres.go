@@ -0,0 +1,113 @@
+package testdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yizeng/gab/chi/sqlc/auth-jwt/pkg/dockertester"
+)
+
+// dsnBackend creates a database in the PostgreSQL server of EnvDSN for each suite,
+// so that packages running at the same time don't see each other's rows.
+type dsnBackend struct{}
+
+func (dsnBackend) Name() string { return "dsn" }
+
+func (dsnBackend) Open() (*pgxpool.Pool, func() error, error) {
+	dsn := os.Getenv(EnvDSN)
+	if dsn == "" {
+		return nil, nil, fmt.Errorf("%v isn't set", EnvDSN)
+	}
+
+	ctx := context.Background()
+	server, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pgx.Connect -> %w", err)
+	}
+
+	name, err := createDatabase(ctx, server)
+	if err != nil {
+		return nil, nil, errors.Join(err, server.Close(ctx))
+	}
+
+	db, err := connect(ctx, dsn, name)
+	if err != nil {
+		return nil, nil, errors.Join(err, dropDatabase(ctx, server, name), server.Close(ctx))
+	}
+
+	return db, func() error {
+		db.Close()
+
+		return errors.Join(dropDatabase(ctx, server, name), server.Close(ctx))
+	}, nil
+}
+
+// dockerBackend starts a PostgreSQL container for each suite.
+type dockerBackend struct{}
+
+func (dockerBackend) Name() string { return "docker" }
+
+func (dockerBackend) Open() (*pgxpool.Pool, func() error, error) {
+	dt, err := dockertester.InitPostgres()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("dockertester.OpenPostgres -> %w", err), dt.Pool.Purge(dt.Resource))
+	}
+
+	return db, func() error {
+		db.Close()
+
+		return dt.Pool.Purge(dt.Resource)
+	}, nil
+}
+
+// createDatabase creates a database with a random name in the server of conn.
+func createDatabase(ctx context.Context, conn *pgx.Conn) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand.Read -> %w", err)
+	}
+
+	name := "test_" + hex.EncodeToString(b)
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		return "", fmt.Errorf("failed to create database -> %w", err)
+	}
+
+	return name, nil
+}
+
+// dropDatabase drops a database of the server of conn, even when it's still connected to.
+func dropDatabase(ctx context.Context, conn *pgx.Conn, name string) error {
+	if _, err := conn.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+		return fmt.Errorf("failed to drop database -> %w", err)
+	}
+
+	return nil
+}
+
+// connect connects to another database of the server of dsn.
+func connect(ctx context.Context, dsn, name string) (*pgxpool.Pool, error) {
+	conf, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.ParseConfig -> %w", err)
+	}
+	conf.ConnConfig.Database = name
+
+	db, err := pgxpool.NewWithConfig(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.NewWithConfig -> %w", err)
+	}
+
+	return db, nil
+}
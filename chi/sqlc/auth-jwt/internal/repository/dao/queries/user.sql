@@ -0,0 +1,12 @@
+-- name: InsertUser :one
+INSERT INTO users (email, password)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: FindUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: FindUserByEmail :one
+SELECT * FROM users
+WHERE email = $1;
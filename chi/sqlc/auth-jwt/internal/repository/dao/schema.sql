@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS users (
+    id         bigserial PRIMARY KEY,
+    email      text        NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password   text        NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrUserEmailExists = errors.New("user already exists")
+	ErrUserNotFound    = errors.New("user not found")
+)
+
+// UserDAO wraps the queries generated by sqlc from queries/user.sql,
+// translating Postgres errors into the errors of this package.
+type UserDAO struct {
+	q *Queries
+}
+
+func NewUserDAO(db DBTX) *UserDAO {
+	return &UserDAO{
+		q: New(db),
+	}
+}
+
+func (d *UserDAO) Insert(ctx context.Context, user User) (User, error) {
+	created, err := d.q.InsertUser(ctx, InsertUserParams{
+		Email:    user.Email,
+		Password: user.Password,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) &&
+			pgErr.Code == pgerrcode.UniqueViolation &&
+			pgErr.ConstraintName == "uni_users_email" {
+			return User{}, ErrUserEmailExists
+		}
+
+		return User{}, err
+	}
+
+	return created, nil
+}
+
+func (d *UserDAO) FindByID(ctx context.Context, id uint) (User, error) {
+	user, err := d.q.FindUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func (d *UserDAO) FindByEmail(ctx context.Context, email string) (User, error) {
+	user, err := d.q.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, ErrUserNotFound
+		}
+
+		return User{}, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1 @@
+CREATE DATABASE chi_sqlc_auth_jwt;
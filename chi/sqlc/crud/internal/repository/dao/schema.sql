@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS articles (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint      NOT NULL,
+    title      text        NOT NULL,
+    content    text        NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_id_title ON articles (user_id, title);
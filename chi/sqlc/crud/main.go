@@ -0,0 +1,13 @@
+package main
+
+import (
+	_ "github.com/joho/godotenv/autoload" // Autoload .env file.
+
+	"github.com/yizeng/gab/chi/sqlc/crud/cmd/app"
+)
+
+func main() {
+	if err := app.Start(); err != nil {
+		panic(err)
+	}
+}
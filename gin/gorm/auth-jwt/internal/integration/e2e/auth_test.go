@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -20,8 +19,8 @@ import (
 	"github.com/yizeng/gab/gin/gorm/auth-jwt/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/gin/gorm/auth-jwt/internal/config"
 	"github.com/yizeng/gab/gin/gorm/auth-jwt/internal/domain"
+	"github.com/yizeng/gab/gin/gorm/auth-jwt/internal/integration/testdb"
 	"github.com/yizeng/gab/gin/gorm/auth-jwt/internal/repository/dao"
-	"github.com/yizeng/gab/gin/gorm/auth-jwt/pkg/dockertester"
 )
 
 var (
@@ -40,28 +39,12 @@ var (
 type AuthHandlerTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
-	server   *api.Server
+	db     *gorm.DB
+	server *api.Server
 }
 
 func (s *AuthHandlerTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *AuthHandlerTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	s.db = testdb.Open(s.T())
 }
 
 func (s *AuthHandlerTestSuite) SetupTest() {
@@ -0,0 +1,39 @@
+package request
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	maxTitleLength   = 128
+	maxContentLength = 5000
+)
+
+type CreateArticleRequest struct {
+	UserID uint `json:"user_id" validate:"required"`
+
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
+}
+
+func (req *CreateArticleRequest) Validate() error {
+	return validation.ValidateStruct(
+		req,
+		validation.Field(&req.UserID, validation.Required, validation.Min(uint(1))),
+		validation.Field(&req.Title, validation.Required, validation.Length(1, maxTitleLength)),
+		validation.Field(&req.Content, validation.Required, validation.Length(1, maxContentLength)),
+	)
+}
+
+type UpdateArticleRequest struct {
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
+}
+
+func (req *UpdateArticleRequest) Validate() error {
+	return validation.ValidateStruct(
+		req,
+		validation.Field(&req.Title, validation.Required, validation.Length(1, maxTitleLength)),
+		validation.Field(&req.Content, validation.Required, validation.Length(1, maxContentLength)),
+	)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"expvar"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/gorm/crud-cached/docs"
+	v1 "github.com/yizeng/gab/gin/gorm/crud-cached/internal/api/handler/v1"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/api/middleware"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/config"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/pkg/cache"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/repository"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/repository/dao"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/service"
+)
+
+const (
+	defaultArticleTTL = 5 * time.Minute
+	defaultListTTL    = 30 * time.Second
+)
+
+type Server struct {
+	Config *config.AppConfig
+	Router *gin.Engine
+}
+
+// NewServer creates a server on top of db, with articles cached in articleCache unless it's nil.
+func NewServer(conf *config.AppConfig, db *gorm.DB, articleCache cache.Cache) *Server {
+	gin.SetMode(conf.Gin.Mode)
+	engine := gin.New()
+
+	s := &Server{
+		Config: conf,
+		Router: engine,
+	}
+
+	s.MountMiddlewares()
+
+	articleHandler := s.initArticleHandler(db, articleCache)
+	s.MountHandlers(articleHandler)
+
+	return s
+}
+
+func (s *Server) MountMiddlewares() {
+	// Logger and Recovery are needed unless we use gin.Default().
+	s.Router.Use(gin.Logger())
+	s.Router.Use(gin.Recovery())
+	s.Router.Use(requestid.New())
+	s.Router.Use(middleware.ConfigCORS(s.Config.API.AllowedCORSDomains))
+}
+
+func (s *Server) MountHandlers(articleHandler *v1.ArticleHandler) {
+	const basePath = "/api/v1"
+
+	apiV1 := s.Router.Group(basePath)
+	{
+		apiV1.GET("/articles", middleware.Paginate(), articleHandler.HandleListArticles)
+		apiV1.POST("/articles", articleHandler.HandleCreateArticle)
+		apiV1.GET("/articles/:articleID", articleHandler.HandleGetArticle)
+		apiV1.PUT("/articles/:articleID", articleHandler.HandleUpdateArticle)
+		apiV1.DELETE("/articles/:articleID", articleHandler.HandleDeleteArticle)
+		apiV1.GET("/articles/search", articleHandler.HandleSearchArticles)
+	}
+
+	s.Router.GET("/", v1.HandleHealthcheck)
+
+	// Setup Swagger UI.
+	docs.SwaggerInfo.Host = s.Config.API.BaseURL
+	docs.SwaggerInfo.BasePath = basePath
+	docs.SwaggerInfo.Title = "API for gin/crud-cached-gorm"
+	docs.SwaggerInfo.Description = "This is an example of Go API with Gin."
+	docs.SwaggerInfo.Version = "1.0"
+	s.Router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+
+	// Expose metrics such as the hits and misses of the article cache, only in development
+	// as they're served without authentication, along with the command line of the process.
+	if strings.EqualFold(s.Config.API.Environment, "development") {
+		s.Router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
+}
+
+func (s *Server) initArticleHandler(db *gorm.DB, articleCache cache.Cache) *v1.ArticleHandler {
+	articleDAO := dao.NewArticleDAO(db)
+
+	var articleRepo service.ArticleRepository = repository.NewArticleRepository(articleDAO)
+	if articleCache != nil {
+		articleTTL, listTTL := defaultArticleTTL, defaultListTTL
+		if s.Config.Cache != nil && s.Config.Cache.ArticleTTL > 0 {
+			articleTTL = s.Config.Cache.ArticleTTL
+		}
+		if s.Config.Cache != nil && s.Config.Cache.ListTTL > 0 {
+			listTTL = s.Config.Cache.ListTTL
+		}
+
+		articleRepo = repository.NewCachedArticleRepository(articleRepo, articleCache, articleTTL, listTTL)
+	}
+
+	articleSvc := service.NewArticleService(articleRepo)
+	articleHandler := v1.NewArticleHandler(articleSvc)
+
+	return articleHandler
+}
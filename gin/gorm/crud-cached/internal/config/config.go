@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+type AppConfig struct {
+	API      *APIConfig      `mapstructure:"API"`
+	Gin      *GinConfig      `mapstructure:"GIN"`
+	Postgres *PostgresConfig `mapstructure:"POSTGRES"`
+	Cache    *CacheConfig    `mapstructure:"CACHE"`
+	Redis    *RedisConfig    `mapstructure:"REDIS"`
+}
+
+func (c *AppConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.API, validation.Required),
+		validation.Field(&c.Gin, validation.Required),
+		validation.Field(&c.Postgres, validation.Required),
+	)
+}
+
+func (c *AppConfig) validateConfig() error {
+	if err := c.validate(); err != nil {
+		return fmt.Errorf("c.validate() -> %w", err)
+	}
+
+	if err := c.API.validate(); err != nil {
+		return fmt.Errorf("c.API.validate() -> %w", err)
+	}
+
+	if err := c.Gin.validate(); err != nil {
+		return fmt.Errorf("c.Gin.validate() -> %w", err)
+	}
+
+	if err := c.Postgres.validate(); err != nil {
+		return fmt.Errorf("c.Postgres.validate() -> %w", err)
+	}
+
+	if c.Cache != nil {
+		if err := c.Cache.validate(); err != nil {
+			return fmt.Errorf("c.Cache.validate() -> %w", err)
+		}
+
+		if c.Cache.Backend == CacheBackendRedis && (c.Redis == nil || c.Redis.Addr == "") {
+			return errors.New("redis must be configured to use it as cache backend")
+		}
+	}
+
+	return nil
+}
+
+type APIConfig struct {
+	Environment        string   `mapstructure:"ENV"`
+	Port               string   `mapstructure:"PORT"`
+	BaseURL            string   `mapstructure:"BASE_URL"`
+	AllowedCORSDomains []string `mapstructure:"ALLOWED_CORS_DOMAINS"`
+}
+
+func (c *APIConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Environment, validation.Required),
+		validation.Field(&c.Port, validation.Required),
+		validation.Field(&c.BaseURL, validation.Required),
+	)
+}
+
+type GinConfig struct {
+	Mode string `mapstructure:"MODE"`
+}
+
+func (c *GinConfig) validate() error {
+	allowedModes := []any{
+		gin.TestMode,
+		gin.DebugMode,
+		gin.ReleaseMode,
+	}
+
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Mode, validation.Required, validation.In(allowedModes...)),
+	)
+}
+
+type PostgresConfig struct {
+	Host     string `mapstructure:"HOST"`
+	Port     string `mapstructure:"PORT"`
+	User     string `mapstructure:"USER"`
+	Password string `mapstructure:"PASSWORD"`
+	DB       string `mapstructure:"DB"`
+	LogLevel string `mapstructure:"LOG_LEVEL"`
+}
+
+func (c *PostgresConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Host, validation.Required),
+		validation.Field(&c.Port, validation.Required),
+		validation.Field(&c.User, validation.Required),
+		validation.Field(&c.Password, validation.Required),
+		validation.Field(&c.DB, validation.Required),
+	)
+}
+
+const (
+	CacheBackendLRU   = "lru"
+	CacheBackendRedis = "redis"
+)
+
+// CacheConfig configures the cache in front of articles.
+type CacheConfig struct {
+	Backend    string        `mapstructure:"BACKEND"`     // lru or redis, empty disables caching
+	Size       int           `mapstructure:"SIZE"`        // how many entries the lru backend holds, 1000 by default
+	ArticleTTL time.Duration `mapstructure:"ARTICLE_TTL"` // how long an article is cached, 5m by default
+	ListTTL    time.Duration `mapstructure:"LIST_TTL"`    // how long a page of articles is cached, 30s by default
+}
+
+func (c *CacheConfig) validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Backend, validation.In(CacheBackendLRU, CacheBackendRedis)),
+		validation.Field(&c.Size, validation.Min(0)),
+		validation.Field(&c.ArticleTTL, validation.Min(time.Duration(0))),
+		validation.Field(&c.ListTTL, validation.Min(time.Duration(0))),
+	)
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"ADDR"`
+	Password string `mapstructure:"PASSWORD"`
+	DB       int    `mapstructure:"DB"`
+}
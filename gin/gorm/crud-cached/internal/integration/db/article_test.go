@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/integration/testdb"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/repository/dao"
+)
+
+type ArticleDBTestSuite struct {
+	suite.Suite
+
+	db *gorm.DB
+
+	articleDAO *dao.ArticleDAO
+}
+
+func (s *ArticleDBTestSuite) SetupSuite() {
+	s.db = testdb.Open(s.T())
+}
+
+func (s *ArticleDBTestSuite) SetupTest() {
+	// Run migrations.
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+
+	// Seed database.
+	script, err := os.ReadFile("../scripts/seed_db.sql")
+	require.NoError(s.T(), err)
+
+	err = s.db.Exec(string(script)).Error
+	require.NoError(s.T(), err)
+
+	// Initialize repository.
+	s.articleDAO = dao.NewArticleDAO(s.db)
+}
+
+func (s *ArticleDBTestSuite) TearDownTest() {
+	s.cleanDB()
+}
+
+func (s *ArticleDBTestSuite) cleanDB() {
+	script, err := os.ReadFile("../scripts/clean_db.sql")
+	require.NoError(s.T(), err)
+
+	err = s.db.Exec(string(script)).Error
+	require.NoError(s.T(), err)
+}
+
+func TestArticleDB(t *testing.T) {
+	suite.Run(t, new(ArticleDBTestSuite))
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_FindByID() {
+	result, err := s.articleDAO.FindByID(context.TODO(), 99999)
+	assert.Error(s.T(), gorm.ErrRecordNotFound)
+
+	result, err = s.articleDAO.FindByID(context.TODO(), 999)
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.EqualValues(s.T(), 123, result.UserID)
+	assert.Equal(s.T(), "seeded title 999", result.Title)
+	assert.Equal(s.T(), "seeded content 999", result.Content)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_FindAll() {
+	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), len(result), 2)
+	assert.EqualValues(s.T(), 123, result[0].UserID)
+	assert.Equal(s.T(), "seeded title 999", result[0].Title)
+	assert.Equal(s.T(), "seeded content 999", result[0].Content)
+
+	result, err = s.articleDAO.FindAll(context.TODO(), 2, 1)
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, len(result))
+	assert.EqualValues(s.T(), 123, result[0].UserID)
+	assert.Equal(s.T(), "seeded title 888", result[0].Title)
+	assert.Equal(s.T(), "seeded content 888", result[0].Content)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_FindAll_Empty() {
+	s.cleanDB()
+
+	result, err := s.articleDAO.FindAll(context.TODO(), 1, 10)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Insert() {
+	result, err := s.articleDAO.Insert(context.TODO(), dao.Article{
+		UserID:  999,
+		Title:   "seeded title 99",
+		Content: "duplicated",
+	})
+	assert.Error(s.T(), dao.ErrArticleDuplicated)
+
+	result, err = s.articleDAO.Insert(context.TODO(), dao.Article{
+		UserID:  123,
+		Title:   "new title",
+		Content: "new content",
+	})
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.Equal(s.T(), "new title", result.Title)
+	assert.Equal(s.T(), "new content", result.Content)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Update() {
+	_, err := s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      999,
+		Title:   "seeded title 888",
+		Content: "duplicated",
+	})
+	assert.ErrorIs(s.T(), err, dao.ErrArticleDuplicated)
+
+	_, err = s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      1,
+		Title:   "title",
+		Content: "content",
+	})
+	assert.ErrorIs(s.T(), err, dao.ErrArticleNotFound)
+
+	result, err := s.articleDAO.Update(context.TODO(), dao.Article{
+		ID:      999,
+		Title:   "updated title",
+		Content: "updated content",
+	})
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), uint(999), result.ID)
+	assert.Equal(s.T(), uint(123), result.UserID)
+	assert.Equal(s.T(), "updated title", result.Title)
+	assert.Equal(s.T(), "updated content", result.Content)
+	assert.True(s.T(), result.UpdatedAt.After(result.CreatedAt))
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Delete() {
+	err := s.articleDAO.Delete(context.TODO(), 999)
+	assert.NoError(s.T(), err)
+
+	_, err = s.articleDAO.FindByID(context.TODO(), 999)
+	assert.ErrorIs(s.T(), err, dao.ErrArticleNotFound)
+
+	err = s.articleDAO.Delete(context.TODO(), 999)
+	assert.ErrorIs(s.T(), err, dao.ErrArticleNotFound)
+}
+
+func (s *ArticleDBTestSuite) TestArticleDB_Search() {
+	result, err := s.articleDAO.Search(context.TODO(), "999", "")
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.EqualValues(s.T(), 123, result[0].UserID)
+	assert.Equal(s.T(), "seeded title 999", result[0].Title)
+	assert.Equal(s.T(), "seeded content 999", result[0].Content)
+
+	result, err = s.articleDAO.Search(context.TODO(), "", "999")
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.EqualValues(s.T(), 123, result[0].UserID)
+	assert.Equal(s.T(), "seeded title 999", result[0].Title)
+	assert.Equal(s.T(), "seeded content 999", result[0].Content)
+}
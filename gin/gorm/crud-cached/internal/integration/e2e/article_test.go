@@ -0,0 +1,1025 @@
+package e2e
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/api"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/config"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/domain"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/integration/testdb"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/pkg/cache"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/repository/dao"
+)
+
+var (
+	testArticle999 = domain.Article{
+		ID:      999,
+		UserID:  123,
+		Title:   "seeded title 999",
+		Content: "seeded content 999",
+	}
+	testArticle888 = domain.Article{
+		ID:      888,
+		UserID:  123,
+		Title:   "seeded title 888",
+		Content: "seeded content 888",
+	}
+	testDBErr = errors.New("DB error")
+)
+
+type ArticleHandlerTestSuite struct {
+	suite.Suite
+
+	db     *gorm.DB
+	server *api.Server
+}
+
+func (s *ArticleHandlerTestSuite) SetupSuite() {
+	s.db = testdb.Open(s.T())
+}
+
+func (s *ArticleHandlerTestSuite) SetupTest() {
+	// Run migrations.
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+
+	// Seed database.
+	script, err := os.ReadFile("../scripts/seed_db.sql")
+	require.NoError(s.T(), err)
+
+	err = s.db.Exec(string(script)).Error
+	require.NoError(s.T(), err)
+
+	// Create API server.
+	s.server = api.NewServer(&config.AppConfig{
+		API: &config.APIConfig{},
+		Gin: &config.GinConfig{
+			Mode: gin.TestMode,
+		},
+		Postgres: &config.PostgresConfig{},
+	}, s.db, nil)
+}
+
+func (s *ArticleHandlerTestSuite) TearDownTest() {
+	s.cleanDB()
+}
+
+func (s *ArticleHandlerTestSuite) cleanDB() {
+	script, err := os.ReadFile("../scripts/clean_db.sql")
+	require.NoError(s.T(), err)
+
+	err = s.db.Exec(string(script)).Error
+	require.NoError(s.T(), err)
+}
+
+func (s *ArticleHandlerTestSuite) createDBError() {
+	// Create/fake a DB error by dropping the articles table.
+	err := s.db.Exec(`DROP TABLE "articles"`).Error
+	require.NoError(s.T(), err)
+}
+
+func TestArticleHandler(t *testing.T) {
+	suite.Run(t, new(ArticleHandlerTestSuite))
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
+	type args struct {
+		buildReqBody func() string
+	}
+	type want struct {
+		article  domain.Article
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "201 Created",
+			setup: func() {},
+			args: args{
+				buildReqBody: func() string {
+					article := request.CreateArticleRequest{
+						UserID:  123,
+						Title:   "title 1",
+						Content: "content 1",
+					}
+
+					body, err := json.Marshal(article)
+					require.NoError(s.T(), err)
+
+					return string(body)
+				},
+			},
+			want: want{
+				article: domain.Article{
+					UserID:  123,
+					Title:   "title 1",
+					Content: "content 1",
+				},
+				respCode: http.StatusCreated,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "400 Bad Request - Missing user_id, Title too long, Content too long",
+			setup: func() {},
+			args: args{
+				buildReqBody: func() string {
+					article := request.CreateArticleRequest{
+						Title:   uniuri.NewLen(200),
+						Content: uniuri.NewLen(10000),
+					}
+
+					body, err := json.Marshal(article)
+					require.NoError(s.T(), err)
+
+					return string(body)
+				},
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(errors.New("content: the length must be between 1 and 5000; title: the length must be between 1 and 128; user_id: cannot be blank.")),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - invalid JSON",
+			setup: func() {},
+			args: args{
+				buildReqBody: func() string {
+					return "["
+				},
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(errors.New("unexpected EOF")),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Already exists",
+			setup: func() {},
+			args: args{
+				buildReqBody: func() string {
+					article := request.CreateArticleRequest{
+						UserID:  123,
+						Title:   "seeded title 999",
+						Content: "seeded content 999",
+					}
+
+					body, err := json.Marshal(article)
+					require.NoError(s.T(), err)
+
+					return string(body)
+				},
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(errors.New("article already exists")),
+			},
+			wantErr: true,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				buildReqBody: func() string {
+					article := request.CreateArticleRequest{
+						UserID:  123,
+						Title:   "title 1",
+						Content: "content 1",
+					}
+
+					body, err := json.Marshal(article)
+					require.NoError(s.T(), err)
+
+					return string(body)
+				},
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			body := tt.args.buildReqBody()
+			req, err := http.NewRequest("POST", "/api/v1/articles", strings.NewReader(body))
+			require.NoError(t, err)
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
+				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+			} else {
+				var result domain.Article
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.article.UserID, result.UserID)
+				assert.Equal(t, tt.want.article.Title, result.Title)
+				assert.Equal(t, tt.want.article.Content, result.Content)
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
+	type args struct {
+		articleID string
+	}
+	type want struct {
+		article  domain.Article
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "200 OK",
+			setup: func() {},
+			args: args{
+				articleID: "999",
+			},
+			want: want{
+				article:  testArticle999,
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "404 Not Found - articleID is not found",
+			setup: func() {},
+			args: args{
+				articleID: "1",
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusNotFound,
+				err:      response.ErrNotFound("article", "ID", "1"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "404 Not Found - articleID is negative",
+			setup: func() {},
+			args: args{
+				articleID: "-1",
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusNotFound,
+				err:      response.ErrNotFound("article", "ID", "-1"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - invalid articleID",
+			setup: func() {},
+			args: args{
+				articleID: "abc",
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("articleID", "abc"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				articleID: "999",
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			req, err := http.NewRequest("GET", "/api/v1/articles/"+tt.args.articleID, nil)
+			require.NoError(t, err)
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
+				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+			} else {
+				var result domain.Article
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.article.UserID, result.UserID)
+				assert.Equal(t, tt.want.article.Title, result.Title)
+				assert.Equal(t, tt.want.article.Content, result.Content)
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleUpdateArticle() {
+	type args struct {
+		articleID    string
+		buildReqBody func() string
+	}
+	type want struct {
+		article  domain.Article
+		respCode int
+		err      *response.Err
+	}
+	buildReqBody := func(title, content string) func() string {
+		return func() string {
+			body, err := json.Marshal(request.UpdateArticleRequest{
+				Title:   title,
+				Content: content,
+			})
+			require.NoError(s.T(), err)
+
+			return string(body)
+		}
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "200 OK",
+			setup: func() {},
+			args: args{
+				articleID:    "999",
+				buildReqBody: buildReqBody("updated title 999", "updated content 999"),
+			},
+			want: want{
+				article: domain.Article{
+					ID:      999,
+					UserID:  123,
+					Title:   "updated title 999",
+					Content: "updated content 999",
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "400 Bad Request - Missing title",
+			setup: func() {},
+			args: args{
+				articleID:    "999",
+				buildReqBody: buildReqBody("", "content"),
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(errors.New("title: cannot be blank.")),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Already exists",
+			setup: func() {},
+			args: args{
+				articleID:    "999",
+				buildReqBody: buildReqBody("seeded title 888", "content"),
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(errors.New("article already exists")),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "404 Not Found - articleID is not found",
+			setup: func() {},
+			args: args{
+				articleID:    "1",
+				buildReqBody: buildReqBody("title", "content"),
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusNotFound,
+				err:      response.ErrNotFound("article", "ID", "1"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - invalid articleID",
+			setup: func() {},
+			args: args{
+				articleID:    "abc",
+				buildReqBody: buildReqBody("title", "content"),
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("articleID", "abc"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				articleID:    "999",
+				buildReqBody: buildReqBody("title", "content"),
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			req, err := http.NewRequest("PUT", "/api/v1/articles/"+tt.args.articleID, strings.NewReader(tt.args.buildReqBody()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
+				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+			} else {
+				var result domain.Article
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.article.ID, result.ID)
+				assert.Equal(t, tt.want.article.UserID, result.UserID)
+				assert.Equal(t, tt.want.article.Title, result.Title)
+				assert.Equal(t, tt.want.article.Content, result.Content)
+				assert.True(t, result.UpdatedAt.After(result.CreatedAt))
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleDeleteArticle() {
+	type args struct {
+		articleID string
+	}
+	type want struct {
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "204 No Content",
+			setup: func() {},
+			args: args{
+				articleID: "999",
+			},
+			want: want{
+				respCode: http.StatusNoContent,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "404 Not Found - articleID is deleted already",
+			setup: func() {},
+			args: args{
+				articleID: "999",
+			},
+			want: want{
+				respCode: http.StatusNotFound,
+				err:      response.ErrNotFound("article", "ID", "999"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - invalid articleID",
+			setup: func() {},
+			args: args{
+				articleID: "abc",
+			},
+			want: want{
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("articleID", "abc"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				articleID: "888",
+			},
+			want: want{
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			req, err := http.NewRequest("DELETE", "/api/v1/articles/"+tt.args.articleID, nil)
+			require.NoError(t, err)
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
+				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+			} else {
+				assert.Empty(t, resp.Body.String())
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_Cache() {
+	cachedServer := api.NewServer(&config.AppConfig{
+		API: &config.APIConfig{},
+		Gin: &config.GinConfig{
+			Mode: gin.TestMode,
+		},
+		Postgres: &config.PostgresConfig{},
+		Cache: &config.CacheConfig{
+			Backend:    config.CacheBackendLRU,
+			ArticleTTL: time.Minute,
+			ListTTL:    time.Minute,
+		},
+	}, s.db, cache.NewLRU(100))
+
+	getArticle := func(articleID string) (int, domain.Article) {
+		req, err := http.NewRequest("GET", "/api/v1/articles/"+articleID, nil)
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, cachedServer)
+
+		var result domain.Article
+		_ = json.Unmarshal(resp.Body.Bytes(), &result)
+
+		return resp.Code, result
+	}
+	listTitles := func() []string {
+		req, err := http.NewRequest("GET", "/api/v1/articles", nil)
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, cachedServer)
+		require.Equal(s.T(), http.StatusOK, resp.Code)
+
+		var result []domain.Article
+		require.NoError(s.T(), json.Unmarshal(resp.Body.Bytes(), &result))
+
+		titles := make([]string, 0, len(result))
+		for _, a := range result {
+			titles = append(titles, a.Title)
+		}
+
+		return titles
+	}
+
+	// Warm the cache.
+	code, article := getArticle("999")
+	require.Equal(s.T(), http.StatusOK, code)
+	assert.Equal(s.T(), testArticle999.Title, article.Title)
+	assert.ElementsMatch(s.T(), []string{testArticle999.Title, testArticle888.Title}, listTitles())
+
+	// Changes made behind the cache's back aren't seen until the TTL.
+	err := s.db.Exec(`UPDATE articles SET title = 'changed behind the cache' WHERE id = 999`).Error
+	require.NoError(s.T(), err)
+	_, article = getArticle("999")
+	assert.Equal(s.T(), testArticle999.Title, article.Title)
+
+	// Writes through the API invalidate the article and the pages.
+	req, err := http.NewRequest("PUT", "/api/v1/articles/999", strings.NewReader(`{"title":"updated title","content":"updated content"}`))
+	require.NoError(s.T(), err)
+	req.Header.Set("Content-Type", "application/json")
+	resp := executeRequest(req, cachedServer)
+	require.Equal(s.T(), http.StatusOK, resp.Code)
+
+	_, article = getArticle("999")
+	assert.Equal(s.T(), "updated title", article.Title)
+	assert.ElementsMatch(s.T(), []string{"updated title", testArticle888.Title}, listTitles())
+
+	req, err = http.NewRequest("POST", "/api/v1/articles", strings.NewReader(`{"user_id":123,"title":"new title","content":"new content"}`))
+	require.NoError(s.T(), err)
+	req.Header.Set("Content-Type", "application/json")
+	resp = executeRequest(req, cachedServer)
+	require.Equal(s.T(), http.StatusCreated, resp.Code)
+	assert.ElementsMatch(s.T(), []string{"updated title", testArticle888.Title, "new title"}, listTitles())
+
+	req, err = http.NewRequest("DELETE", "/api/v1/articles/999", nil)
+	require.NoError(s.T(), err)
+	resp = executeRequest(req, cachedServer)
+	require.Equal(s.T(), http.StatusNoContent, resp.Code)
+
+	code, _ = getArticle("999")
+	assert.Equal(s.T(), http.StatusNotFound, code)
+	assert.ElementsMatch(s.T(), []string{testArticle888.Title, "new title"}, listTitles())
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
+	type args struct {
+		query string
+	}
+	type want struct {
+		articles []domain.Article
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "200 OK",
+			setup: func() {},
+			args: args{
+				query: "",
+			},
+			want: want{
+				articles: []domain.Article{
+					testArticle999,
+					testArticle888,
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "200 OK - With pagination",
+			setup: func() {},
+			args: args{
+				query: "?page=2&per_page=1",
+			},
+			want: want{
+				articles: []domain.Article{
+					testArticle888,
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "200 OK - When there are no articles",
+			setup: func() {
+				s.cleanDB()
+			},
+			args: args{
+				query: "?page=1&per_page=2",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "400 Bad Request - Invalid page query",
+			setup: func() {},
+			args: args{
+				query: "?page=abc&per_page=2",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("page", "abc"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Negative page query",
+			setup: func() {},
+			args: args{
+				query: "?page=-123&per_page=2",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("page", "-123"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Invalid per_page query",
+			setup: func() {},
+			args: args{
+				query: "?page=1&per_page=abc",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("per_page", "abc"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Negative per_page query",
+			setup: func() {},
+			args: args{
+				query: "?page=1&per_page=-123",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("per_page", "-123"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				query: "?page=1&per_page=1",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			req, err := http.NewRequest("GET", "/api/v1/articles"+tt.args.query, nil)
+			require.NoError(t, err)
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
+				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+			} else {
+				var result []domain.Article
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.want.articles), len(result))
+
+				for i, v := range result {
+					wantArticle := tt.want.articles[i]
+
+					assert.Equal(t, wantArticle.UserID, v.UserID)
+					assert.Equal(t, wantArticle.Title, v.Title)
+					assert.Equal(t, wantArticle.Content, v.Content)
+				}
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles() {
+	type args struct {
+		query string
+	}
+	type want struct {
+		articles []domain.Article
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "200 OK - by title",
+			setup: func() {},
+			args: args{
+				query: "title=999",
+			},
+			want: want{
+				articles: []domain.Article{
+					testArticle999,
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "200 OK - by content",
+			setup: func() {},
+			args: args{
+				query: "content=999",
+			},
+			want: want{
+				articles: []domain.Article{
+					testArticle999,
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "200 OK - When there are no results",
+			setup: func() {},
+			args: args{
+				query: "title=no-title&content=no-content",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "200 OK - No query parameters",
+			setup: func() {},
+			args: args{
+				query: "",
+			},
+			want: want{
+				articles: []domain.Article{
+					testArticle999,
+					testArticle888,
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				query: "",
+			},
+			want: want{
+				articles: nil,
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			req, err := http.NewRequest("GET", "/api/v1/articles/search?"+tt.args.query, nil)
+			require.NoError(t, err)
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
+				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+			} else {
+				var result []domain.Article
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.want.articles), len(result))
+
+				for i, v := range result {
+					wantArticle := tt.want.articles[i]
+
+					assert.Equal(t, wantArticle.UserID, v.UserID)
+					assert.Equal(t, wantArticle.Title, v.Title)
+					assert.Equal(t, wantArticle.Content, v.Content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package cache stores byte values by key for a limited time,
+// either in process with an LRU or in Redis to share them between replicas.
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get when the key isn't cached, or has expired.
+var ErrMiss = errors.New("cache miss")
+
+// Cache is safe for concurrent use.
+type Cache interface {
+	// Get returns the value of key, or ErrMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores the value of key for ttl. A zero ttl means no expiration.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes keys, missing ones are ignored.
+	Delete(ctx context.Context, keys ...string) error
+
+	// Incr increments the counter of key, starting from 0, and returns its new value.
+	// Counters don't expire and can be read with Get as a decimal number.
+	Incr(ctx context.Context, key string) (int64, error)
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaches(t *testing.T) {
+	now := time.Date(2024, 1, 31, 15, 26, 31, 0, time.UTC)
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	lru := NewLRU(10)
+	lru.now = func() time.Time { return now }
+
+	caches := map[string]struct {
+		cache   Cache
+		advance func(d time.Duration)
+	}{
+		"lru": {
+			cache: lru,
+			advance: func(d time.Duration) {
+				current := lru.now()
+				lru.now = func() time.Time { return current.Add(d) }
+			},
+		},
+		"redis": {
+			cache:   NewRedis(client),
+			advance: mr.FastForward,
+		},
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_, err := c.cache.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrMiss)
+
+			require.NoError(t, c.cache.Set(ctx, "key", []byte("value"), time.Minute))
+			require.NoError(t, c.cache.Set(ctx, "forever", []byte("value"), 0))
+			value, err := c.cache.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("value"), value)
+
+			// Values expire with their TTL.
+			c.advance(time.Minute)
+			_, err = c.cache.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrMiss)
+			_, err = c.cache.Get(ctx, "forever")
+			assert.NoError(t, err)
+
+			require.NoError(t, c.cache.Delete(ctx, "forever", "missing"))
+			_, err = c.cache.Get(ctx, "forever")
+			assert.ErrorIs(t, err, ErrMiss)
+
+			for i := int64(1); i <= 2; i++ {
+				counter, err := c.cache.Incr(ctx, "counter")
+				require.NoError(t, err)
+				assert.Equal(t, i, counter)
+			}
+			value, err = c.cache.Get(ctx, "counter")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("2"), value)
+		})
+	}
+}
+
+func TestLRU_Eviction(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2)
+
+	require.NoError(t, lru.Set(ctx, "a", []byte("a"), 0))
+	require.NoError(t, lru.Set(ctx, "b", []byte("b"), 0))
+
+	// Reading a makes b the least recently used.
+	_, err := lru.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, lru.Set(ctx, "c", []byte("c"), 0))
+
+	_, err = lru.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrMiss)
+	_, err = lru.Get(ctx, "a")
+	assert.NoError(t, err)
+	_, err = lru.Get(ctx, "c")
+	assert.NoError(t, err)
+
+	// Counters don't take room and aren't evicted.
+	_, err = lru.Incr(ctx, "counter")
+	require.NoError(t, err)
+	require.NoError(t, lru.Set(ctx, "d", []byte("d"), 0))
+	value, err := lru.Get(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+}
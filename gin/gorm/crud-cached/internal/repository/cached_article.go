@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/domain"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/pkg/cache"
+)
+
+// listGenerationKey versions the keys of cached pages of articles.
+// Pages are invalidated all at once by replacing it, old ones are left to expire.
+const listGenerationKey = "articles:list:generation"
+
+// articleCacheStats counts the hits, misses and errors of the article cache, served at /debug/vars.
+var articleCacheStats = expvar.NewMap("article_cache")
+
+// ArticleStore is what ArticleRepository implements and ArticleService consumes.
+type ArticleStore interface {
+	Create(ctx context.Context, article domain.Article) (domain.Article, error)
+	FindByID(ctx context.Context, id uint) (domain.Article, error)
+	FindAll(ctx context.Context, page, perPage uint) ([]domain.Article, error)
+	Search(ctx context.Context, title, content string) ([]domain.Article, error)
+	Update(ctx context.Context, article domain.Article) (domain.Article, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// CachedArticleRepository is a cache-aside decorator of an ArticleStore.
+// Articles and pages of articles are cached on read, and invalidated on write.
+// Concurrent misses of the same key are collapsed into a single read of the store.
+//
+// The cache is a best effort: when it fails, the store is used directly.
+// A read racing with a write may still cache a stale value, until its TTL.
+type CachedArticleRepository struct {
+	next       ArticleStore
+	cache      cache.Cache
+	articleTTL time.Duration
+	listTTL    time.Duration
+	group      singleflight.Group
+}
+
+func NewCachedArticleRepository(next ArticleStore, c cache.Cache, articleTTL, listTTL time.Duration) *CachedArticleRepository {
+	return &CachedArticleRepository{
+		next:       next,
+		cache:      c,
+		articleTTL: articleTTL,
+		listTTL:    listTTL,
+	}
+}
+
+func (r *CachedArticleRepository) Create(ctx context.Context, article domain.Article) (domain.Article, error) {
+	created, err := r.next.Create(ctx, article)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("r.next.Create -> %w", err)
+	}
+
+	r.invalidate(ctx)
+
+	return created, nil
+}
+
+func (r *CachedArticleRepository) FindByID(ctx context.Context, id uint) (domain.Article, error) {
+	key := articleKey(id)
+
+	var article domain.Article
+	if r.lookup(ctx, key, &article) {
+		return article, nil
+	}
+
+	found, err := r.load(ctx, key, r.articleTTL, func(ctx context.Context) (any, error) {
+		return r.next.FindByID(ctx, id)
+	})
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("r.next.FindByID -> %w", err)
+	}
+
+	return found.(domain.Article), nil
+}
+
+func (r *CachedArticleRepository) FindAll(ctx context.Context, page, perPage uint) ([]domain.Article, error) {
+	generation, err := r.listGeneration(ctx)
+	if err != nil {
+		// Without the generation, a cached page can't be told from a stale one.
+		r.fail("listGeneration", listGenerationKey, err)
+
+		articles, err := r.next.FindAll(ctx, page, perPage)
+		if err != nil {
+			return nil, fmt.Errorf("r.next.FindAll -> %w", err)
+		}
+
+		return articles, nil
+	}
+
+	key := fmt.Sprintf("articles:list:%s:%d:%d", generation, page, perPage)
+
+	var articles []domain.Article
+	if r.lookup(ctx, key, &articles) {
+		return articles, nil
+	}
+
+	found, err := r.load(ctx, key, r.listTTL, func(ctx context.Context) (any, error) {
+		return r.next.FindAll(ctx, page, perPage)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("r.next.FindAll -> %w", err)
+	}
+
+	return found.([]domain.Article), nil
+}
+
+// Search isn't cached, there are too many combinations of queries for it to be worth it.
+func (r *CachedArticleRepository) Search(ctx context.Context, title, content string) ([]domain.Article, error) {
+	articles, err := r.next.Search(ctx, title, content)
+	if err != nil {
+		return nil, fmt.Errorf("r.next.Search -> %w", err)
+	}
+
+	return articles, nil
+}
+
+func (r *CachedArticleRepository) Update(ctx context.Context, article domain.Article) (domain.Article, error) {
+	updated, err := r.next.Update(ctx, article)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("r.next.Update -> %w", err)
+	}
+
+	r.invalidate(ctx, article.ID)
+
+	return updated, nil
+}
+
+func (r *CachedArticleRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return fmt.Errorf("r.next.Delete -> %w", err)
+	}
+
+	r.invalidate(ctx, id)
+
+	return nil
+}
+
+// lookup decodes the cached value of key into v, and tells whether it was found.
+func (r *CachedArticleRepository) lookup(ctx context.Context, key string, v any) bool {
+	value, err := r.cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, cache.ErrMiss) {
+			articleCacheStats.Add("misses", 1)
+			zap.L().Debug("cache miss", zap.String("key", key))
+		} else {
+			r.fail("cache.Get", key, err)
+		}
+
+		return false
+	}
+
+	if err = json.Unmarshal(value, v); err != nil {
+		r.fail("json.Unmarshal", key, err)
+
+		return false
+	}
+
+	articleCacheStats.Add("hits", 1)
+	zap.L().Debug("cache hit", zap.String("key", key))
+
+	return true
+}
+
+// load reads key from the store with read and caches it for ttl, once for all the concurrent misses of key.
+// The read isn't canceled with ctx, as other callers may be waiting for it.
+func (r *CachedArticleRepository) load(
+	ctx context.Context, key string, ttl time.Duration, read func(ctx context.Context) (any, error),
+) (any, error) {
+	found, err, _ := r.group.Do(key, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+
+		found, err := read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(found)
+		if err != nil {
+			r.fail("json.Marshal", key, err)
+
+			return found, nil
+		}
+		if err = r.cache.Set(ctx, key, value, ttl); err != nil {
+			r.fail("cache.Set", key, err)
+		}
+
+		return found, nil
+	})
+
+	return found, err
+}
+
+// invalidate removes the given articles and all the pages of articles from the cache.
+func (r *CachedArticleRepository) invalidate(ctx context.Context, ids ...uint) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, articleKey(id))
+	}
+
+	if err := r.cache.Delete(ctx, keys...); err != nil {
+		r.fail("cache.Delete", fmt.Sprint(keys), err)
+	}
+	if _, err := r.newListGeneration(ctx); err != nil {
+		r.fail("cache.Set", listGenerationKey, err)
+	}
+}
+
+// listGeneration returns the generation of the cached pages of articles.
+// When there isn't one, e.g. it was evicted, a new one is started, which none of the cached pages belong to.
+func (r *CachedArticleRepository) listGeneration(ctx context.Context) ([]byte, error) {
+	generation, err := r.cache.Get(ctx, listGenerationKey)
+	if errors.Is(err, cache.ErrMiss) {
+		return r.newListGeneration(ctx)
+	}
+
+	return generation, err
+}
+
+// newListGeneration starts a new generation of pages of articles, leaving the cached ones behind.
+// Generations are random rather than counted, so one started after an eviction can't be a former one.
+func (r *CachedArticleRepository) newListGeneration(ctx context.Context) ([]byte, error) {
+	generation := []byte(strconv.FormatUint(rand.Uint64(), 36))
+	if err := r.cache.Set(ctx, listGenerationKey, generation, 0); err != nil {
+		return nil, err
+	}
+
+	return generation, nil
+}
+
+func (r *CachedArticleRepository) fail(op, key string, err error) {
+	articleCacheStats.Add("errors", 1)
+	zap.L().Warn("cache failed", zap.String("op", op), zap.String("key", key), zap.Error(err))
+}
+
+func articleKey(id uint) string {
+	return "articles:" + strconv.FormatUint(uint64(id), 10)
+}
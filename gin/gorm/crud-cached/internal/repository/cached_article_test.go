@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/domain"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/pkg/cache"
+)
+
+// fakeArticleStore keeps articles in a map and counts the reads.
+type fakeArticleStore struct {
+	mu       sync.Mutex
+	articles map[uint]domain.Article
+	reads    atomic.Int32
+	release  chan struct{} // when set, reads wait for it
+}
+
+func newFakeArticleStore(articles ...domain.Article) *fakeArticleStore {
+	s := &fakeArticleStore{articles: make(map[uint]domain.Article)}
+	for _, a := range articles {
+		s.articles[a.ID] = a
+	}
+
+	return s
+}
+
+func (s *fakeArticleStore) Create(_ context.Context, article domain.Article) (domain.Article, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	article.ID = uint(len(s.articles) + 1)
+	s.articles[article.ID] = article
+
+	return article, nil
+}
+
+func (s *fakeArticleStore) FindByID(_ context.Context, id uint) (domain.Article, error) {
+	s.read()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	article, ok := s.articles[id]
+	if !ok {
+		return domain.Article{}, ErrArticleNotFound
+	}
+
+	return article, nil
+}
+
+func (s *fakeArticleStore) FindAll(_ context.Context, _, _ uint) ([]domain.Article, error) {
+	s.read()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	articles := make([]domain.Article, 0, len(s.articles))
+	for id := uint(1); id <= uint(len(s.articles)); id++ {
+		articles = append(articles, s.articles[id])
+	}
+
+	return articles, nil
+}
+
+func (s *fakeArticleStore) Search(_ context.Context, _, _ string) ([]domain.Article, error) {
+	s.read()
+
+	return nil, nil
+}
+
+func (s *fakeArticleStore) Update(_ context.Context, article domain.Article) (domain.Article, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.articles[article.ID] = article
+
+	return article, nil
+}
+
+func (s *fakeArticleStore) Delete(_ context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.articles, id)
+
+	return nil
+}
+
+func (s *fakeArticleStore) read() {
+	s.reads.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+}
+
+// failingCache fails every operation, like an unreachable Redis.
+type failingCache struct{}
+
+var errCacheDown = errors.New("cache down")
+
+func (failingCache) Get(context.Context, string) ([]byte, error) { return nil, errCacheDown }
+
+func (failingCache) Set(context.Context, string, []byte, time.Duration) error { return errCacheDown }
+
+func (failingCache) Delete(context.Context, ...string) error { return errCacheDown }
+
+func (failingCache) Incr(context.Context, string) (int64, error) { return 0, errCacheDown }
+
+var testArticle = domain.Article{ID: 1, UserID: 123, Title: "title 1", Content: "content 1"}
+
+func TestCachedArticleRepository_FindByID(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeArticleStore(testArticle)
+	repo := NewCachedArticleRepository(store, cache.NewLRU(10), time.Minute, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		article, err := repo.FindByID(ctx, testArticle.ID)
+		require.NoError(t, err)
+		assert.Equal(t, testArticle, article)
+	}
+	assert.EqualValues(t, 1, store.reads.Load(), "the second read should be a hit")
+
+	// Updates invalidate the article.
+	updated := testArticle
+	updated.Title = "updated title"
+	_, err := repo.Update(ctx, updated)
+	require.NoError(t, err)
+
+	article, err := repo.FindByID(ctx, testArticle.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, article)
+	assert.EqualValues(t, 2, store.reads.Load())
+
+	// So do deletes, and missing articles aren't cached.
+	require.NoError(t, repo.Delete(ctx, testArticle.ID))
+	for i := 0; i < 2; i++ {
+		_, err = repo.FindByID(ctx, testArticle.ID)
+		assert.ErrorIs(t, err, ErrArticleNotFound)
+	}
+	assert.EqualValues(t, 4, store.reads.Load())
+}
+
+func TestCachedArticleRepository_FindAll(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeArticleStore(testArticle)
+	repo := NewCachedArticleRepository(store, cache.NewLRU(10), time.Minute, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		articles, err := repo.FindAll(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Article{testArticle}, articles)
+	}
+	assert.EqualValues(t, 1, store.reads.Load(), "the second read should be a hit")
+
+	// Other pages are cached apart.
+	_, err := repo.FindAll(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, store.reads.Load())
+
+	// Any write invalidates all pages.
+	created, err := repo.Create(ctx, domain.Article{UserID: 123, Title: "title 2", Content: "content 2"})
+	require.NoError(t, err)
+
+	articles, err := repo.FindAll(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Article{testArticle, created}, articles)
+	assert.EqualValues(t, 3, store.reads.Load())
+}
+
+func TestCachedArticleRepository_FindAll_GenerationEvicted(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeArticleStore(testArticle)
+	c := cache.NewLRU(10)
+	repo := NewCachedArticleRepository(store, c, time.Minute, time.Minute)
+
+	_, err := repo.FindAll(ctx, 1, 10)
+	require.NoError(t, err)
+	created, err := repo.Create(ctx, domain.Article{UserID: 123, Title: "title 2", Content: "content 2"})
+	require.NoError(t, err)
+
+	// The page cached before the write is still there, but isn't served once the generation is evicted.
+	require.NoError(t, c.Delete(ctx, listGenerationKey))
+
+	articles, err := repo.FindAll(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Article{testArticle, created}, articles)
+	assert.EqualValues(t, 2, store.reads.Load())
+}
+
+func TestCachedArticleRepository_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeArticleStore(testArticle)
+	store.release = make(chan struct{})
+	repo := NewCachedArticleRepository(store, cache.NewLRU(10), time.Minute, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			article, err := repo.FindByID(ctx, testArticle.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, testArticle, article)
+		}()
+	}
+
+	// Give all the readers time to miss, then let the first one read the store.
+	time.Sleep(50 * time.Millisecond)
+	close(store.release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, store.reads.Load())
+}
+
+func TestCachedArticleRepository_CacheDown(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeArticleStore(testArticle)
+	repo := NewCachedArticleRepository(store, failingCache{}, time.Minute, time.Minute)
+
+	article, err := repo.FindByID(ctx, testArticle.ID)
+	require.NoError(t, err)
+	assert.Equal(t, testArticle, article)
+
+	articles, err := repo.FindAll(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Article{testArticle}, articles)
+
+	_, err = repo.Update(ctx, testArticle)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, store.reads.Load())
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/domain"
+	"github.com/yizeng/gab/gin/gorm/crud-cached/internal/repository"
+)
+
+var (
+	ErrArticleDuplicated = repository.ErrArticleDuplicated
+	ErrArticleNotFound   = repository.ErrArticleNotFound
+)
+
+type ArticleRepository interface {
+	Create(ctx context.Context, article domain.Article) (domain.Article, error)
+	FindByID(ctx context.Context, id uint) (domain.Article, error)
+	FindAll(ctx context.Context, page, perPage uint) ([]domain.Article, error)
+	Search(ctx context.Context, title, content string) ([]domain.Article, error)
+	Update(ctx context.Context, article domain.Article) (domain.Article, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type ArticleService struct {
+	repo ArticleRepository
+}
+
+func NewArticleService(repo ArticleRepository) *ArticleService {
+	return &ArticleService{
+		repo: repo,
+	}
+}
+
+func (s *ArticleService) CreateArticle(ctx context.Context, article domain.Article) (domain.Article, error) {
+	created, err := s.repo.Create(ctx, article)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("s.repo.Create -> %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *ArticleService) GetArticle(ctx context.Context, id uint) (domain.Article, error) {
+	article, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("s.repo.FindByID -> %w", err)
+	}
+
+	return article, nil
+}
+
+func (s *ArticleService) ListArticles(ctx context.Context, page, perPage uint) ([]domain.Article, error) {
+	articles, err := s.repo.FindAll(ctx, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.FindAll -> %w", err)
+	}
+
+	return articles, nil
+}
+
+func (s *ArticleService) SearchArticles(ctx context.Context, title, content string) ([]domain.Article, error) {
+	articles, err := s.repo.Search(ctx, title, content)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.Search -> %w", err)
+	}
+
+	return articles, nil
+}
+
+func (s *ArticleService) UpdateArticle(ctx context.Context, article domain.Article) (domain.Article, error) {
+	updated, err := s.repo.Update(ctx, article)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("s.repo.Update -> %w", err)
+	}
+
+	return updated, nil
+}
+
+func (s *ArticleService) DeleteArticle(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("s.repo.Delete -> %w", err)
+	}
+
+	return nil
+}
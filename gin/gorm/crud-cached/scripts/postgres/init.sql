@@ -0,0 +1 @@
+CREATE DATABASE gin_gorm_crud_cached;
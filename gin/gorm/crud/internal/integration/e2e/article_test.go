@@ -10,7 +10,6 @@ import (
 
 	"github.com/dchest/uniuri"
 	"github.com/gin-gonic/gin"
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -21,8 +20,8 @@ import (
 	"github.com/yizeng/gab/gin/gorm/crud/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/gin/gorm/crud/internal/config"
 	"github.com/yizeng/gab/gin/gorm/crud/internal/domain"
+	"github.com/yizeng/gab/gin/gorm/crud/internal/integration/testdb"
 	"github.com/yizeng/gab/gin/gorm/crud/internal/repository/dao"
-	"github.com/yizeng/gab/gin/gorm/crud/pkg/dockertester"
 )
 
 var (
@@ -44,28 +43,12 @@ var (
 type ArticleHandlerTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
-	server   *api.Server
+	db     *gorm.DB
+	server *api.Server
 }
 
 func (s *ArticleHandlerTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *ArticleHandlerTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	s.db = testdb.Open(s.T())
 }
 
 func (s *ArticleHandlerTestSuite) SetupTest() {
@@ -1,32 +1,117 @@
 package app
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	"github.com/yizeng/gab/gin/wip-complete/internal/api"
 	"github.com/yizeng/gab/gin/wip-complete/internal/config"
 	"github.com/yizeng/gab/gin/wip-complete/internal/db"
 	"github.com/yizeng/gab/gin/wip-complete/internal/logger"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+	"github.com/yizeng/gab/gin/wip-complete/internal/service"
 )
 
-func Start() error {
-	conf, err := config.Load("./cmd/app/config.yml")
+const defaultConfigPath = "./cmd/app/config.yml"
+
+// env is shared by all the commands, it's set up before any of them runs.
+type env struct {
+	configPath string
+	conf       *config.AppConfig
+}
+
+// setup loads the config and initializes the logger.
+func (e *env) setup() error {
+	conf, err := config.Load(e.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize config -> %w", err)
 	}
 
-	if err = logger.Init(conf.API.Environment); err != nil {
+	if err = logger.Init(conf.Log, conf.API.Environment); err != nil {
 		return fmt.Errorf("failed to initialize logger -> %w", err)
 	}
 
-	postgresDB, err := db.OpenPostgres(conf.Postgres)
+	e.conf = conf
+
+	return nil
+}
+
+func (e *env) openDB() (*gorm.DB, error) {
+	gormDB, err := db.Open(e.conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database -> %w", err)
+	}
+
+	return gormDB, nil
+}
+
+// NewCommand creates the root command, which serves the API when no subcommand is given.
+func NewCommand() *cobra.Command {
+	e := &env{}
+
+	cmd := &cobra.Command{
+		Use:          "app",
+		Short:        "API of gin/gorm/wip-complete and its admin tasks",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return e.setup()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.serve()
+		},
+	}
+	cmd.PersistentFlags().StringVar(&e.configPath, "config", defaultConfigPath, "path of the config file")
+
+	cmd.AddCommand(
+		newServeCommand(e),
+		newMigrateCommand(e),
+		newSeedCommand(e),
+		newRoutesCommand(e),
+		newUserCommand(e),
+		newReindexCommand(e),
+	)
+
+	return cmd
+}
+
+func newServeCommand(e *env) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Apply the pending migrations and serve the API",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.serve()
+		},
+	}
+}
+
+func (e *env) serve() error {
+	gormDB, err := e.openDB()
 	if err != nil {
-		return fmt.Errorf("failed to initialize database -> %w", err)
+		return err
 	}
 
-	s := api.NewServer(conf, postgresDB)
+	if err = db.Migrate(context.Background(), gormDB); err != nil {
+		return fmt.Errorf("failed to migrate database -> %w", err)
+	}
+
+	var redisClient *redis.Client
+	if e.conf.RedisNeeded() {
+		redisClient, err = db.OpenRedis(e.conf.Redis)
+		if err != nil {
+			return fmt.Errorf("failed to initialize redis -> %w", err)
+		}
+	}
+
+	s := api.NewServer(e.conf, gormDB, redisClient)
+	defer s.Close()
 
 	addr := ":" + s.Config.API.Port
 	zap.L().Info(fmt.Sprintf("starting server at %v", addr))
@@ -36,3 +121,74 @@ func Start() error {
 
 	return nil
 }
+
+func newReindexCommand(e *env) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the bleve search index from the database",
+		Long: "Rebuild the bleve search index from the database.\n" +
+			"The server must be stopped meanwhile, as the index can only be opened by one process.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.reindex()
+		},
+	}
+}
+
+func (e *env) reindex() error {
+	if e.conf.Search == nil || e.conf.Search.Backend != config.SearchBackendBleve {
+		return fmt.Errorf("only the %v search backend has an index to rebuild", config.SearchBackendBleve)
+	}
+
+	gormDB, err := e.openDB()
+	if err != nil {
+		return err
+	}
+
+	index, err := repository.OpenBleveSearchIndex(e.conf.Search.BlevePath)
+	if err != nil {
+		return fmt.Errorf("failed to open search index -> %w", err)
+	}
+	defer index.Close()
+
+	articleRepo := repository.NewArticleRepository(dao.NewArticleDAO(gormDB))
+	svc := service.NewArticleService(articleRepo, index, e.newTxManager(gormDB))
+
+	indexed, err := svc.RebuildSearchIndex(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to rebuild search index -> %w", err)
+	}
+
+	zap.L().Info(fmt.Sprintf("indexed %v articles into %v", indexed, e.conf.Search.BlevePath))
+
+	return nil
+}
+
+// newArticleService creates an ArticleService that keeps the configured search index in sync.
+// The returned func must be called once done, it releases the bleve index.
+func (e *env) newArticleService(gormDB *gorm.DB) (*service.ArticleService, func() error, error) {
+	articleRepo := repository.NewArticleRepository(dao.NewArticleDAO(gormDB))
+
+	if e.conf.Search == nil || e.conf.Search.Backend != config.SearchBackendBleve {
+		index := repository.NewPostgresSearchIndex(articleRepo)
+
+		return service.NewArticleService(articleRepo, index, e.newTxManager(gormDB)), func() error { return nil }, nil
+	}
+
+	index, err := repository.OpenBleveSearchIndex(e.conf.Search.BlevePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open search index -> %w", err)
+	}
+
+	return service.NewArticleService(articleRepo, index, e.newTxManager(gormDB)), index.Close, nil
+}
+
+func (e *env) newTxManager(gormDB *gorm.DB) *dao.TxManager {
+	conf := e.conf.SQL()
+
+	return dao.NewTxManager(gormDB, dao.TxConfig{
+		Isolation:    conf.TxIsolationLevel(),
+		MaxRetries:   conf.TxMaxRetries,
+		RetryBackoff: conf.TxRetryBackoff,
+	})
+}
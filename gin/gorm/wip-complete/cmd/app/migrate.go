@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/db"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/migrate"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+)
+
+func newMigrateCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the versioned SQL migrations of the database",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all the pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				gormDB, err := e.openDB()
+				if err != nil {
+					return err
+				}
+
+				if err = db.Migrate(context.Background(), gormDB); err != nil {
+					return fmt.Errorf("failed to migrate database -> %w", err)
+				}
+
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "down [steps]",
+			Short: "Roll back the latest migrations, 1 by default",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				steps := 1
+				if len(args) > 0 {
+					var err error
+					if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+						return fmt.Errorf("steps must be a positive number, got %q", args[0])
+					}
+				}
+
+				migrator, err := e.newMigrator()
+				if err != nil {
+					return err
+				}
+
+				rolledBack, err := migrator.Down(context.Background(), steps)
+				for _, m := range rolledBack {
+					zap.L().Info("rolled back migration", zap.Uint64("version", m.Version), zap.String("name", m.Name))
+				}
+				if err != nil {
+					return fmt.Errorf("failed to roll back database -> %w", err)
+				}
+
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Print all the migrations and when they were applied",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				migrator, err := e.newMigrator()
+				if err != nil {
+					return err
+				}
+
+				statuses, err := migrator.Status(context.Background())
+				if err != nil {
+					return fmt.Errorf("failed to get migration status -> %w", err)
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+				for _, s := range statuses {
+					appliedAt := "pending"
+					if !s.Pending() {
+						appliedAt = s.AppliedAt.Format(time.RFC3339)
+					}
+
+					fmt.Fprintf(w, "%06d\t%v\t%v\n", s.Version, s.Name, appliedAt)
+				}
+
+				return w.Flush()
+			},
+		},
+		&cobra.Command{
+			Use:   "create <name>",
+			Short: "Create empty up and down scripts of a new migration, for each database dialect",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				for _, dialect := range dao.Dialects {
+					up, down, err := migrate.Create(filepath.Join(dao.MigrationsDir, dialect), args[0])
+					if err != nil {
+						return fmt.Errorf("failed to create %v migration -> %w", dialect, err)
+					}
+
+					fmt.Fprintf(cmd.OutOrStdout(), "created %v\ncreated %v\n", up, down)
+				}
+
+				return nil
+			},
+		},
+	)
+
+	return cmd
+}
+
+func (e *env) newMigrator() (*migrate.Migrator, error) {
+	gormDB, err := e.openDB()
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := dao.NewMigrator(gormDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations -> %w", err)
+	}
+
+	return migrator, nil
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+	"github.com/yizeng/gab/gin/wip-complete/internal/service"
+)
+
+func newUserCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	cmd.AddCommand(newUserCreateCommand(e))
+
+	return cmd
+}
+
+func newUserCreateCommand(e *env) *cobra.Command {
+	var (
+		email    string
+		password string
+		admin    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user, like signing up but optionally as an admin",
+		Long: "Create a user, like signing up but optionally as an admin.\n" +
+			"The password is read from the standard input when --password isn't given, to keep it out of the shell history.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if password == "" {
+				fmt.Fprint(cmd.ErrOrStderr(), "Password: ")
+
+				line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if err != nil && line == "" {
+					return fmt.Errorf("failed to read password -> %w", err)
+				}
+				password = strings.TrimRight(line, "\r\n")
+			}
+
+			// Users are held to the same rules as when they sign up.
+			req := request.SignupRequest{Email: email, Password: password, ConfirmPassword: password}
+			if err := req.Validate(); err != nil {
+				return err
+			}
+
+			gormDB, err := e.openDB()
+			if err != nil {
+				return err
+			}
+
+			svc := service.NewAuthService(repository.NewUserRepository(dao.NewUserDAO(gormDB)))
+
+			user, err := svc.Signup(context.Background(), domain.User{
+				Email:    req.Email,
+				Password: req.Password,
+				IsAdmin:  admin,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create user -> %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "created user %v %v (admin: %v)\n", user.ID, user.Email, user.IsAdmin)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "email of the user")
+	cmd.Flags().StringVar(&password, "password", "", "password of the user, read from the standard input if empty")
+	cmd.Flags().BoolVar(&admin, "admin", false, "whether the user is an admin")
+	_ = cmd.MarkFlagRequired("email")
+
+	return cmd
+}
@@ -38,13 +38,16 @@ func NewAuthHandler(conf *config.APIConfig, svc AuthService) *AuthHandler {
 // @Tags         auth
 // @Produce      json
 // @Param        request   body      request.SignupRequest true "request body"
+// @Param        Idempotency-Key header string false "replays the stored response when the request is retried with the same key"
 // @Success      201      {object}   domain.User
 // @Failure      400      {object}   response.Err
+// @Failure      409      {object}   response.Err
+// @Failure      422      {object}   response.Err
 // @Failure      500      {object}   response.Err
 // @Router       /auth/signup [post]
 func (h *AuthHandler) HandleSignup(ctx *gin.Context) {
 	req := request.SignupRequest{}
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := ctx.ShouldBindWith(&req, request.JSON); err != nil {
 		response.RenderErr(ctx, response.ErrBadRequest(err))
 
 		return
@@ -87,7 +90,7 @@ func (h *AuthHandler) HandleSignup(ctx *gin.Context) {
 // @Router       /auth/login [post]
 func (h *AuthHandler) HandleLogin(ctx *gin.Context) {
 	req := request.LoginRequest{}
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := ctx.ShouldBindWith(&req, request.JSON); err != nil {
 		response.RenderErr(ctx, response.ErrBadRequest(err))
 
 		return
@@ -113,7 +116,7 @@ func (h *AuthHandler) HandleLogin(ctx *gin.Context) {
 		return
 	}
 
-	token, err := jwthelper.GenerateToken([]byte(h.conf.JWTSigningKey), user.ID, ctx.Request.UserAgent())
+	token, err := jwthelper.GenerateToken([]byte(h.conf.JWTSigningKey), user.ID, ctx.Request.UserAgent(), user.IsAdmin)
 	if err != nil {
 		err = fmt.Errorf("v1.HandleSignup -> middleware.GenerateToken() -> %w", err)
 		response.RenderErr(ctx, response.ErrInternalServerError(err))
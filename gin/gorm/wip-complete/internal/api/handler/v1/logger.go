@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/gin/wip-complete/internal/logger"
+)
+
+// HandleGetLogLevel godoc
+// @Summary      Get the current log level
+// @Tags         admin
+// @Produce      json
+// @Success      200      {object}   response.LogLevelResponse
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Router       /admin/log/level [get]
+func HandleGetLogLevel(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, response.LogLevelResponse{
+		Level: logger.Level().String(),
+	})
+}
+
+// HandleSetLogLevel godoc
+// @Summary      Change the log level at runtime
+// @Tags         admin
+// @Produce      json
+// @Param        request   body      request.SetLogLevelRequest true "request body"
+// @Success      200      {object}   response.LogLevelResponse
+// @Failure      400      {object}   response.Err
+// @Failure      401      {object}   response.Err
+// @Failure      403      {object}   response.Err
+// @Failure      500      {object}   response.Err
+// @Router       /admin/log/level [put]
+func HandleSetLogLevel(ctx *gin.Context) {
+	req := request.SetLogLevelRequest{}
+	if err := ctx.ShouldBindWith(&req, request.JSON); err != nil {
+		response.RenderErr(ctx, response.ErrBadRequest(err))
+
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		response.RenderErr(ctx, response.ErrBadRequest(err))
+
+		return
+	}
+
+	previous := logger.Level()
+	if err := logger.SetLevel(req.Level); err != nil {
+		err = fmt.Errorf("v1.HandleSetLogLevel -> logger.SetLevel -> %w", err)
+		response.RenderErr(ctx, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	zap.L().Warn(
+		"log level changed",
+		zap.Stringer("from", previous),
+		zap.Stringer("to", logger.Level()),
+	)
+
+	ctx.JSON(http.StatusOK, response.LogLevelResponse{
+		Level: logger.Level().String(),
+	})
+}
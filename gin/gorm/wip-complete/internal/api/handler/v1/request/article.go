@@ -1,14 +1,65 @@
 package request
 
 import (
-	validation "github.com/go-ozzo/ozzo-validation"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/fieldset"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/listquery"
 )
 
 const (
 	maxTitleLength   = 128
 	maxContentLength = 5000
+	maxQueryLength   = 256
 )
 
+// ArticleListQuery whitelists the fields articles can be sorted and filtered by.
+var ArticleListQuery = listquery.Schema{
+	"id": {
+		Type:      listquery.Int,
+		Sortable:  true,
+		Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In, listquery.Gt, listquery.Gte, listquery.Lt, listquery.Lte},
+	},
+	"user_id": {
+		Type:      listquery.Int,
+		Sortable:  true,
+		Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In},
+	},
+	"title": {
+		Type:      listquery.String,
+		Sortable:  true,
+		Operators: []listquery.Operator{listquery.Eq, listquery.Ne, listquery.In, listquery.Contains},
+	},
+	"content": {
+		Type:      listquery.String,
+		Operators: []listquery.Operator{listquery.Contains},
+	},
+	"created_at": {
+		Type:      listquery.Time,
+		Sortable:  true,
+		Operators: []listquery.Operator{listquery.Gt, listquery.Gte, listquery.Lt, listquery.Lte},
+	},
+	"updated_at": {
+		Type:      listquery.Time,
+		Sortable:  true,
+		Operators: []listquery.Operator{listquery.Gt, listquery.Gte, listquery.Lt, listquery.Lte},
+	},
+}
+
+// ArticleCursorListQuery is ArticleListQuery for cursor pagination,
+// which keeps articles in the (created_at, id) order and can only reverse it.
+var ArticleCursorListQuery = ArticleListQuery.Sortable("created_at")
+
+// ArticleAuthor is the relation embedding the author of articles, with ?include=author.
+const ArticleAuthor = "author"
+
+// ArticleFields whitelists the fields of articles that can be selected and the relations that can be included.
+var ArticleFields = fieldset.Schema{
+	Fields:    []string{"id", "user_id", "title", "content", "version", "created_at", "updated_at"},
+	Relations: []string{ArticleAuthor},
+}
+
 type CreateArticleRequest struct {
 	UserID uint `json:"user_id" validate:"required"`
 
@@ -24,3 +75,62 @@ func (req *CreateArticleRequest) Validate() error {
 		validation.Field(&req.Content, validation.Required, validation.Length(1, maxContentLength)),
 	)
 }
+
+type UpdateArticleRequest struct {
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
+}
+
+func (req *UpdateArticleRequest) Validate() error {
+	return validation.ValidateStruct(
+		req,
+		validation.Field(&req.Title, validation.Required, validation.Length(1, maxTitleLength)),
+		validation.Field(&req.Content, validation.Required, validation.Length(1, maxContentLength)),
+	)
+}
+
+// SearchArticlesRequest is a full-text search of articles, read from the query.
+type SearchArticlesRequest struct {
+	Query    string `json:"q"`    // search terms in the syntax of web search engines
+	Language string `json:"lang"` // one of domain.SearchLanguages, domain.DefaultSearchLanguage if empty
+}
+
+func (req *SearchArticlesRequest) Validate() error {
+	languages := make([]any, 0, len(domain.SearchLanguages))
+	for _, language := range domain.SearchLanguages {
+		languages = append(languages, language)
+	}
+
+	return validation.ValidateStruct(
+		req,
+		validation.Field(&req.Query, validation.Required, validation.Length(1, maxQueryLength)),
+		validation.Field(&req.Language, validation.In(languages...)),
+	)
+}
+
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+	ExportJSON   = "json"
+)
+
+// ExportArticlesRequest is an export of articles, read from the query along with the filters of ArticleListQuery.
+type ExportArticlesRequest struct {
+	Format   string `json:"format"` // csv, ndjson or json, json if empty
+	Query    string `json:"q"`      // search terms as in SearchArticlesRequest, all articles are exported if empty
+	Language string `json:"lang"`   // one of domain.SearchLanguages, domain.DefaultSearchLanguage if empty
+}
+
+func (req *ExportArticlesRequest) Validate() error {
+	languages := make([]any, 0, len(domain.SearchLanguages))
+	for _, language := range domain.SearchLanguages {
+		languages = append(languages, language)
+	}
+
+	return validation.ValidateStruct(
+		req,
+		validation.Field(&req.Format, validation.In(ExportCSV, ExportNDJSON, ExportJSON)),
+		validation.Field(&req.Query, validation.Length(0, maxQueryLength)),
+		validation.Field(&req.Language, validation.In(languages...)),
+	)
+}
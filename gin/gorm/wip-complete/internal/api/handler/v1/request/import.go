@@ -0,0 +1,155 @@
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+)
+
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeCSV    = "text/csv"
+)
+
+// maxImportLineSize bounds a line of NDJSON imports, an article with the longest content fits easily.
+const maxImportLineSize = 1 << 20
+
+var ErrUnsupportedImport = errors.New("articles can only be imported from " + ContentTypeNDJSON + " or " + ContentTypeCSV)
+
+// ArticleImportColumns are the columns of the header of CSV imports, in any order.
+var ArticleImportColumns = []string{"user_id", "title", "content"}
+
+// ArticleImportReader reads the rows of a bulk import of articles, each validated like a CreateArticleRequest.
+type ArticleImportReader struct {
+	next func() (req CreateArticleRequest, invalid error, err error)
+	row  int
+}
+
+// NewArticleImportReader reads an import from body, NDJSON with an article per line or CSV with a header
+// of ArticleImportColumns, depending on contentType. Other content types fail with ErrUnsupportedImport.
+func NewArticleImportReader(contentType string, body io.Reader) (*ArticleImportReader, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case ContentTypeNDJSON:
+		return &ArticleImportReader{next: ndjsonRows(body)}, nil
+	case ContentTypeCSV:
+		next, err := csvRows(body)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ArticleImportReader{next: next}, nil
+	default:
+		return nil, ErrUnsupportedImport
+	}
+}
+
+// Next returns the next row, with Err set when it's invalid, and io.EOF once all rows are read.
+// Other errors mean the body can't be read any further.
+func (r *ArticleImportReader) Next() (domain.ArticleImportRow, error) {
+	req, invalid, err := r.next()
+	if err != nil {
+		return domain.ArticleImportRow{}, err
+	}
+
+	r.row++
+	if invalid == nil {
+		invalid = req.Validate()
+	}
+
+	return domain.ArticleImportRow{
+		Row: r.row,
+		Article: domain.Article{
+			UserID:  req.UserID,
+			Title:   req.Title,
+			Content: req.Content,
+		},
+		Err: invalid,
+	}, nil
+}
+
+// ndjsonRows decodes a line at a time, blank lines are skipped.
+func ndjsonRows(body io.Reader) func() (CreateArticleRequest, error, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxImportLineSize)
+
+	return func() (CreateArticleRequest, error, error) {
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var req CreateArticleRequest
+			invalid := DecodeJSON(bytes.NewReader(line), &req)
+
+			return req, invalid, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return CreateArticleRequest{}, nil, fmt.Errorf("scanner.Scan -> %w", err)
+		}
+
+		return CreateArticleRequest{}, nil, io.EOF
+	}
+}
+
+// csvRows reads the header right away, so a missing or unknown column fails the whole import.
+func csvRows(body io.Reader) (func() (CreateArticleRequest, error, error), error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = len(ArticleImportColumns)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("CSV header can't be read: %w", err)
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		if !slices.Contains(ArticleImportColumns, column) {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+		columns[column] = i
+	}
+	for _, column := range ArticleImportColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing CSV column %q", column)
+		}
+	}
+
+	return func() (CreateArticleRequest, error, error) {
+		record, err := reader.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return CreateArticleRequest{}, fmt.Errorf("malformed CSV: %w", parseErr), nil
+		}
+		if err != nil {
+			return CreateArticleRequest{}, nil, err
+		}
+
+		req := CreateArticleRequest{
+			Title:   record[columns["title"]],
+			Content: record[columns["content"]],
+		}
+		if raw := record[columns["user_id"]]; raw != "" {
+			userID, err := strconv.ParseUint(raw, 10, 0)
+			if err != nil {
+				return req, validation.Errors{
+					"user_id": validation.NewError(codeTypeMismatch, "must be a {{.type}}").
+						SetParams(map[string]any{"type": "number"}),
+				}, nil
+			}
+			req.UserID = uint(userID)
+		}
+
+		return req, nil, nil
+	}, nil
+}
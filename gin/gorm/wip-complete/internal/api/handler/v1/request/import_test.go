@@ -0,0 +1,114 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+)
+
+func TestArticleImportReader(t *testing.T) {
+	type row struct {
+		article domain.Article
+		invalid string // the invalid field, or "syntax" for a malformed row
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []row
+		wantErr     error
+	}{
+		{
+			name:        "NDJSON",
+			contentType: "application/x-ndjson; charset=utf-8",
+			body: `{"user_id":1,"title":"title 1","content":"content 1"}` + "\n\n" +
+				`{"user_id":1,"title":"","content":"content 2"}` + "\n" +
+				`{"user_id":"1","title":"title 3","content":"content 3"}` + "\n" +
+				`{"user_id":1,` + "\n" +
+				`{"user_id":2,"title":"title 5","content":"content 5"}`,
+			want: []row{
+				{article: domain.Article{UserID: 1, Title: "title 1", Content: "content 1"}},
+				{article: domain.Article{UserID: 1, Content: "content 2"}, invalid: "title"},
+				{article: domain.Article{Title: "title 3", Content: "content 3"}, invalid: "user_id"},
+				{invalid: "syntax"},
+				{article: domain.Article{UserID: 2, Title: "title 5", Content: "content 5"}},
+			},
+		},
+		{
+			name:        "CSV",
+			contentType: "text/csv",
+			body: "title,content,user_id\n" +
+				"title 1,\"content, 1\",1\n" +
+				"title 2,content 2,abc\n" +
+				"title 3,content 3\n" +
+				"title 4,content 4,\n",
+			want: []row{
+				{article: domain.Article{UserID: 1, Title: "title 1", Content: "content, 1"}},
+				{article: domain.Article{Title: "title 2", Content: "content 2"}, invalid: "user_id"},
+				{invalid: "syntax"},
+				{article: domain.Article{Title: "title 4", Content: "content 4"}, invalid: "user_id"},
+			},
+		},
+		{
+			name:        "CSV with an unknown column",
+			contentType: "text/csv",
+			body:        "user_id,title,body\n1,title,content\n",
+			wantErr:     errors.New(`unknown CSV column "body"`),
+		},
+		{
+			name:        "CSV with a missing column",
+			contentType: "text/csv",
+			body:        "user_id,title,title\n1,title,content\n",
+			wantErr:     errors.New(`missing CSV column "content"`),
+		},
+		{
+			name:        "Unsupported content type",
+			contentType: "application/json",
+			body:        `[]`,
+			wantErr:     ErrUnsupportedImport,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewArticleImportReader(tt.contentType, strings.NewReader(tt.body))
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+
+				return
+			}
+			require.NoError(t, err)
+
+			for i, want := range tt.want {
+				got, err := reader.Next()
+				require.NoError(t, err)
+				assert.Equal(t, i+1, got.Row)
+
+				switch want.invalid {
+				case "":
+					assert.NoError(t, got.Err)
+					assert.Equal(t, want.article, got.Article)
+				case "syntax":
+					var errs validation.Errors
+					assert.Error(t, got.Err)
+					assert.False(t, errors.As(got.Err, &errs))
+				default:
+					var errs validation.Errors
+					require.ErrorAs(t, got.Err, &errs)
+					assert.Contains(t, errs, want.invalid)
+					assert.Equal(t, want.article, got.Article)
+				}
+			}
+
+			_, err = reader.Next()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
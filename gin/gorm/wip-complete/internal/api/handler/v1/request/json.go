@@ -0,0 +1,119 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const codeTypeMismatch = "validation_type_mismatch"
+
+// SyntaxError reports malformed JSON with the position where decoding failed.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("malformed JSON at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// JSON is a gin binding decoding with DecodeJSON, so every request body reports malformed JSON the same way.
+// Use it with ctx.ShouldBindWith instead of ctx.ShouldBindJSON.
+var JSON binding.BindingBody = jsonBinding{}
+
+type jsonBinding struct{}
+
+func (jsonBinding) Name() string {
+	return "json"
+}
+
+func (jsonBinding) Bind(r *http.Request, obj any) error {
+	return DecodeJSON(r.Body, obj)
+}
+
+func (jsonBinding) BindBody(body []byte, obj any) error {
+	return DecodeJSON(bytes.NewReader(body), obj)
+}
+
+// DecodeJSON decodes body into v.
+// Malformed JSON is returned as *SyntaxError, and values of the wrong type as validation.Errors,
+// so they are reported like any other invalid field.
+func DecodeJSON(body io.Reader, v any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("io.ReadAll -> %w", err)
+	}
+
+	err = json.Unmarshal(data, v)
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := position(data, syntaxErr.Offset)
+
+		return &SyntaxError{
+			Line:   line,
+			Column: column,
+			Msg:    syntaxErr.Error(),
+		}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		fieldErr := validation.NewError(codeTypeMismatch, "must be a {{.type}}").
+			SetParams(map[string]any{"type": jsonType(typeErr)})
+
+		return nestedErrors(strings.Split(typeErr.Field, "."), fieldErr)
+	}
+
+	return err
+}
+
+// position converts the offset of a json.SyntaxError into a 1-based line and column.
+func position(data []byte, offset int64) (line, column int) {
+	// The offset is right after the invalid character, or the end of the input when it's truncated.
+	i := int(offset) - 1
+	if i < 0 || int(offset) >= len(data) {
+		i = int(offset)
+	}
+
+	before := data[:min(i, len(data))]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// nestedErrors builds validation.Errors shaped like the JSON document from a dotted path like "states.2.population".
+func nestedErrors(path []string, err error) validation.Errors {
+	if len(path) == 1 {
+		return validation.Errors{path[0]: err}
+	}
+
+	return validation.Errors{path[0]: nestedErrors(path[1:], err)}
+}
+
+// jsonType names the JSON type a Go value is decoded from.
+func jsonType(err *json.UnmarshalTypeError) string {
+	switch err.Type.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,8 @@
+package request
+
+import "github.com/yizeng/gab/gin/wip-complete/internal/pkg/fieldset"
+
+// UserFields whitelists the fields of users that can be selected, users have no relations to include.
+var UserFields = fieldset.Schema{
+	Fields: []string{"id", "email", "is_admin", "created_at", "updated_at"},
+}
@@ -0,0 +1,149 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/request"
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+)
+
+// ArticleExportColumns are the columns of CSV exports, named like the fields of articles in JSON.
+var ArticleExportColumns = []string{"id", "user_id", "title", "content", "version", "created_at", "updated_at"}
+
+// exportContentTypes are the media types of each export format.
+var exportContentTypes = map[string]string{
+	request.ExportCSV:    request.ContentTypeCSV + "; charset=utf-8",
+	request.ExportNDJSON: request.ContentTypeNDJSON,
+	request.ExportJSON:   "application/json",
+}
+
+// ArticleExport writes articles in an export format one at a time, as they're read.
+// Nothing is sent until the first article, so a failure before that can still be reported with an error response.
+type ArticleExport struct {
+	w       http.ResponseWriter
+	format  string
+	csv     *csv.Writer
+	started bool
+	written int
+}
+
+// NewArticleExport writes an export to w in format, one of request.ExportCSV, request.ExportNDJSON or request.ExportJSON.
+func NewArticleExport(w http.ResponseWriter, format string) *ArticleExport {
+	return &ArticleExport{
+		w:      w,
+		format: format,
+	}
+}
+
+// Started tells whether the response is sent already.
+func (e *ArticleExport) Started() bool {
+	return e.started
+}
+
+// Write writes an article.
+func (e *ArticleExport) Write(article domain.Article) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	var err error
+	switch e.format {
+	case request.ExportCSV:
+		err = e.csv.Write([]string{
+			strconv.FormatUint(uint64(article.ID), 10),
+			strconv.FormatUint(uint64(article.UserID), 10),
+			article.Title,
+			article.Content,
+			strconv.FormatUint(uint64(article.Version), 10),
+			article.CreatedAt.Format(time.RFC3339Nano),
+			article.UpdatedAt.Format(time.RFC3339Nano),
+		})
+	case request.ExportNDJSON:
+		err = e.writeJSON("", article, "\n")
+	default:
+		separator := ","
+		if e.written == 0 {
+			separator = ""
+		}
+		err = e.writeJSON(separator, article, "")
+	}
+	if err != nil {
+		return fmt.Errorf("article %v can't be exported: %w", article.ID, err)
+	}
+	e.written++
+
+	return nil
+}
+
+// Close ends the export, which is sent even if there are no articles.
+func (e *ArticleExport) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	switch e.format {
+	case request.ExportCSV:
+		e.csv.Flush()
+
+		return e.csv.Error()
+	case request.ExportNDJSON:
+		return nil
+	default:
+		_, err := e.w.Write([]byte("]\n"))
+
+		return err
+	}
+}
+
+// Fail reports an export failing once it's started. The status is sent already, so the export is only cut short
+// and the error is logged. A JSON export then lacks its closing bracket.
+func (e *ArticleExport) Fail(err error) {
+	if e.format == request.ExportCSV {
+		e.csv.Flush()
+	}
+
+	zap.L().Error(err.Error())
+}
+
+// start sends the headers and what comes before the first article.
+func (e *ArticleExport) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	e.w.Header().Set("Content-Type", exportContentTypes[e.format])
+	e.w.Header().Set("Content-Disposition", `attachment; filename="articles.`+e.format+`"`)
+	e.w.WriteHeader(http.StatusOK)
+
+	switch e.format {
+	case request.ExportCSV:
+		e.csv = csv.NewWriter(e.w)
+
+		return e.csv.Write(ArticleExportColumns)
+	case request.ExportNDJSON:
+		return nil
+	default:
+		_, err := e.w.Write([]byte("["))
+
+		return err
+	}
+}
+
+func (e *ArticleExport) writeJSON(prefix string, article domain.Article, suffix string) error {
+	body, err := json.Marshal(article)
+	if err != nil {
+		return fmt.Errorf("json.Marshal -> %w", err)
+	}
+
+	_, err = e.w.Write(append(append([]byte(prefix), body...), suffix...))
+
+	return err
+}
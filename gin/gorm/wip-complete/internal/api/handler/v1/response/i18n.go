@@ -0,0 +1,52 @@
+package response
+
+import (
+	"embed"
+	"io/fs"
+
+	"golang.org/x/text/language"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/i18n"
+)
+
+// locales has a catalogue per language, messages in the code are English.
+//
+//go:embed locales/*.yaml
+var locales embed.FS
+
+// translator is shared by every error response, so catalogues are loaded once.
+var translator = mustTranslator()
+
+func mustTranslator() *i18n.Translator {
+	catalogues, err := fs.Sub(locales, "locales")
+	if err != nil {
+		panic(err)
+	}
+
+	t, err := i18n.New(catalogues, language.English)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// localize translates the title, detail and field messages into the language negotiated from acceptLanguage
+// and returns that language. Codes never change, messages missing from the catalogues stay in English.
+func (e *Err) localize(acceptLanguage string) language.Tag {
+	lang := translator.Match(acceptLanguage)
+
+	if title, ok := translator.Translate(lang, "titles."+string(e.Code), nil); ok {
+		e.Title = title
+	}
+	if detail, ok := translator.Translate(lang, "details."+string(e.Code), e.params); ok {
+		e.Detail = detail
+	}
+	for i, fieldErr := range e.Errors {
+		if msg, ok := translator.Translate(lang, "fields."+fieldErr.Code, fieldErr.Params); ok {
+			e.Errors[i].Message = msg
+		}
+	}
+
+	return lang
+}
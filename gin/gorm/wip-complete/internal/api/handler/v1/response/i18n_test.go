@@ -0,0 +1,107 @@
+package response
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCatalogues(t *testing.T) {
+	files, err := fs.Glob(locales, "locales/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		lang := language.MustParse(strings.TrimSuffix(strings.TrimPrefix(file, "locales/"), ".yaml"))
+
+		for code := range titles {
+			_, ok := translator.Translate(lang, "titles."+string(code), nil)
+			assert.True(t, ok, "%v has no title of %v", lang, code)
+		}
+	}
+}
+
+func TestRenderErr_Localized(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		err            *Err
+		wantLanguage   string
+		wantTitle      string
+		wantDetail     string
+		wantMessages   []string
+	}{
+		{
+			name:           "English by default",
+			acceptLanguage: "",
+			err:            ErrNotFound("article", "ID", 1),
+			wantLanguage:   "en",
+			wantTitle:      "Article not found",
+			wantDetail:     "article not found (ID=1)",
+		},
+		{
+			name:           "Detail with params",
+			acceptLanguage: "de-AT, en;q=0.5",
+			err:            ErrNotFound("article", "ID", 1),
+			wantLanguage:   "de",
+			wantTitle:      "Artikel nicht gefunden",
+			wantDetail:     "Artikel nicht gefunden (ID=1)",
+		},
+		{
+			name:           "Field messages with params",
+			acceptLanguage: "es",
+			err: ErrBadRequest(validation.Errors{
+				"title":   validation.Validate("", validation.Required),
+				"content": validation.Validate("x", validation.Length(2, 10)),
+			}),
+			wantLanguage: "es",
+			wantTitle:    "Validación fallida",
+			wantDetail:   "la solicitud contiene campos no válidos",
+			wantMessages: []string{"la longitud debe estar entre 2 y 10", "no puede estar vacío"},
+		},
+		{
+			name:           "Unsupported language",
+			acceptLanguage: "ja",
+			err:            ErrPermissionDenied(assert.AnError),
+			wantLanguage:   "en",
+			wantTitle:      "Permission denied",
+			wantDetail:     "permission denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/articles/1", nil)
+			ctx.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			code := tt.err.Code
+			RenderErr(ctx, tt.err)
+
+			assert.Equal(t, tt.wantLanguage, rr.Header().Get("Content-Language"))
+			assert.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+
+			var got Err
+			err := json.Unmarshal(rr.Body.Bytes(), &got)
+			require.NoError(t, err)
+			assert.Equal(t, code, got.Code)
+			assert.Equal(t, tt.wantTitle, got.Title)
+			assert.Equal(t, tt.wantDetail, got.Detail)
+
+			messages := make([]string, 0, len(got.Errors))
+			for _, fieldErr := range got.Errors {
+				messages = append(messages, fieldErr.Message)
+			}
+			assert.ElementsMatch(t, tt.wantMessages, messages)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package response
+
+import "github.com/yizeng/gab/gin/wip-complete/internal/domain"
+
+// Page is a page of a list paginated with cursors.
+// Next and Prev are opaque cursors to send back in the cursor query, they are empty on the last and first page.
+type Page[T any] struct {
+	Data  []T    `json:"data"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Total *int64 `json:"total,omitempty"` // only when requested with count=true
+}
+
+// ArticleSearchPage is a page of search results, paginated with page numbers.
+type ArticleSearchPage struct {
+	Data   []domain.ArticleMatch `json:"data"`
+	Total  *int64                `json:"total,omitempty"`  // only when requested with count=true
+	Facets *domain.ArticleFacets `json:"facets,omitempty"` // only when requested with facets=true
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/request"
 	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
 	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/jwthelper"
@@ -34,6 +35,7 @@ func NewUserHandler(svc UserService) *UserHandler {
 // @Tags         users
 // @Produce      json
 // @Param        userID   path       int  true "user ID"
+// @Param        fields   query      string  false  "comma separated fields to return, e.g. id,email. All fields are returned if empty."
 // @Success      200      {object}   domain.User
 // @Failure      401      {object}   response.Err
 // @Failure      500      {object}   response.Err
@@ -66,6 +68,13 @@ func (h *UserHandler) HandleGetUser(ctx *gin.Context) {
 		return
 	}
 
+	fields, err := request.UserFields.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		response.RenderErr(ctx, response.ErrBadRequest(err))
+
+		return
+	}
+
 	user, err := h.svc.GetUser(ctx.Request.Context(), uint(userID))
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
@@ -80,5 +89,13 @@ func (h *UserHandler) HandleGetUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	body, err := fields.Select(user)
+	if err != nil {
+		err = fmt.Errorf("v1.HandleGetUser -> fields.Select -> %w", err)
+		response.RenderErr(ctx, response.ErrInternalServerError(err))
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, body)
 }
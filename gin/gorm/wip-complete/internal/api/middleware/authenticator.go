@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,24 @@ func (a *Authenticator) VerifyJWT() gin.HandlerFunc {
 	}
 }
 
+// RequireAdmin only lets admin users through. It must be mounted after VerifyJWT.
+func (a *Authenticator) RequireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, err := jwthelper.RetrieveClaimsFromContext(ctx)
+		if err != nil {
+			response.RenderErr(ctx, response.ErrJWTUnverified(err))
+
+			return
+		}
+
+		if !claims.IsAdmin {
+			response.RenderErr(ctx, response.ErrPermissionDenied(fmt.Errorf("user %v is not an admin", claims.UserID)))
+
+			return
+		}
+	}
+}
+
 func (a *Authenticator) extractClaims(ctx *gin.Context) (*jwthelper.Claims, error) {
 	authHeader := ctx.GetHeader("Authorization")
 	if authHeader == "" {
@@ -7,14 +7,30 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/idempotency"
 )
 
+// exposedHeaders are the response headers browsers allow scripts to read.
+var exposedHeaders = []string{
+	"Content-Length",
+	"ETag",
+	"Link",
+	"X-Total-Count",
+	"RateLimit-Policy",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"Retry-After",
+	idempotency.ReplayedHeader,
+}
+
 func ConfigCORS(allowedDomains []string) gin.HandlerFunc {
 	conf := cors.Config{
 		AllowOriginFunc:  createAllowedOriginFunc(allowedDomains),
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Accept", "Authorization", "X-CSRF-Token"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", idempotency.Header, "If-Match", "If-None-Match"},
+		ExposeHeaders:    exposedHeaders,
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/idempotency"
+)
+
+const maxIdempotentBodyBytes = 1 << 20
+
+type Idempotency struct {
+	store idempotency.Store
+	ttl   time.Duration
+}
+
+func NewIdempotency(store idempotency.Store, ttl time.Duration) *Idempotency {
+	if ttl <= 0 {
+		ttl = idempotency.DefaultTTL
+	}
+
+	return &Idempotency{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Handle replays the stored response when a request is retried with the same Idempotency-Key.
+// Keys are scoped per client identified by keyFunc.
+// Requests without the header are processed as usual.
+func (i *Idempotency) Handle(keyFunc KeyFunc) gin.HandlerFunc {
+	if i.store == nil {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		idempotencyKey := ctx.GetHeader(idempotency.Header)
+		if idempotencyKey == "" {
+			ctx.Next()
+
+			return
+		}
+		if len(idempotencyKey) > idempotency.MaxKeyLength {
+			response.RenderErr(ctx, response.ErrInvalidInput(idempotency.Header, idempotencyKey))
+
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			response.RenderErr(ctx, response.ErrBadRequest(err))
+
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := keyFunc(ctx) + ":" + idempotencyKey
+		requestHash := idempotency.HashRequest(ctx.Request.Method, ctx.Request.URL.Path, body)
+		reserved, record, err := i.store.Reserve(ctx.Request.Context(), key, requestHash, i.ttl)
+		if err != nil {
+			err = fmt.Errorf("middleware.Idempotency -> i.store.Reserve -> %w", err)
+			response.RenderErr(ctx, response.ErrInternalServerError(err))
+
+			return
+		}
+
+		if !reserved {
+			i.replay(ctx, record, requestHash)
+
+			return
+		}
+
+		// Free the key when the handler panics, or retries would be refused until it expires.
+		defer func() {
+			if p := recover(); p != nil {
+				i.release(context.WithoutCancel(ctx.Request.Context()), key)
+
+				panic(p)
+			}
+		}()
+
+		writer := &teeResponseWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		i.save(ctx, key, requestHash, writer.Status(), writer.Header(), writer.body.Bytes())
+	}
+}
+
+func (i *Idempotency) replay(ctx *gin.Context, record idempotency.Record, requestHash string) {
+	if record.RequestHash != requestHash {
+		response.RenderErr(ctx, response.ErrIdempotencyKeyReused())
+
+		return
+	}
+
+	if !record.Completed {
+		response.RenderErr(ctx, response.ErrIdempotencyKeyInProgress())
+
+		return
+	}
+
+	for name, values := range record.Header {
+		ctx.Writer.Header()[name] = values
+	}
+	ctx.Header(idempotency.ReplayedHeader, "true")
+	ctx.Status(record.StatusCode)
+	_, _ = ctx.Writer.Write(record.Body)
+	ctx.Abort()
+}
+
+func (i *Idempotency) save(ctx *gin.Context, key, requestHash string, status int, header http.Header, body []byte) {
+	// Clients that time out disconnect before the response is saved, but they are the ones retrying.
+	storeCtx := context.WithoutCancel(ctx.Request.Context())
+
+	// Server errors are not final, let the client retry them.
+	if status >= http.StatusInternalServerError {
+		i.release(storeCtx, key)
+
+		return
+	}
+
+	err := i.store.Complete(storeCtx, key, idempotency.Record{
+		RequestHash: requestHash,
+		StatusCode:  status,
+		Header:      idempotency.ReplayableHeader(header),
+		Body:        body,
+	}, i.ttl)
+	if err != nil {
+		zap.L().Error("saving idempotent response failed", zap.Error(err))
+	}
+}
+
+func (i *Idempotency) release(ctx context.Context, key string) {
+	if err := i.store.Release(ctx, key); err != nil {
+		zap.L().Error("releasing idempotency key failed", zap.Error(err))
+	}
+}
+
+// teeResponseWriter keeps a copy of the response body, so it can be replayed.
+type teeResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *teeResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/idempotency"
+)
+
+func TestIdempotency_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	store := idempotency.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	calls := 0
+	status := http.StatusCreated
+	handler := gin.New()
+	handler.POST("/articles", NewIdempotency(store, 0).Handle(KeyByIP), func(ctx *gin.Context) {
+		calls++
+		ctx.Data(status, "application/json", []byte(`{"id":1}`))
+	})
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+		req.RemoteAddr = "1.2.3.4:1000"
+		if key != "" {
+			req.Header.Set(idempotency.Header, key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := send("key-1", `{"title":"a"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// The retry gets the same response without calling the handler again.
+	rr = send("key-1", `{"title":"a"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "true", rr.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `{"id":1}`, rr.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// The same key can't be reused for a different payload.
+	rr = send("key-1", `{"title":"b"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Equal(t, 1, calls)
+
+	// Requests without a key aren't deduplicated.
+	send("", `{"title":"a"}`)
+	send("", `{"title":"a"}`)
+	assert.Equal(t, 3, calls)
+
+	// Server errors can be retried.
+	status = http.StatusInternalServerError
+	rr = send("key-2", `{"title":"a"}`)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	status = http.StatusCreated
+	rr = send("key-2", `{"title":"a"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, 5, calls)
+}
+
+func TestIdempotency_Handle_InProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	store := idempotency.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	var retry *httptest.ResponseRecorder
+	handler := gin.New()
+	handler.POST("/articles", NewIdempotency(store, 0).Handle(KeyByIP), func(ctx *gin.Context) {
+		if retry == nil {
+			// Retry while the first request is still being processed.
+			retry = httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader("{}"))
+			req.RemoteAddr = ctx.Request.RemoteAddr
+			req.Header.Set(idempotency.Header, "key")
+			handler.ServeHTTP(retry, req)
+		}
+		ctx.Status(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader("{}"))
+	req.RemoteAddr = "1.2.3.4:1000"
+	req.Header.Set(idempotency.Header, "key")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, http.StatusConflict, retry.Code)
+}
+
+func TestIdempotency_Handle_Disconnected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	store := idempotency.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	// The client gives up before the response is saved.
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	handler := gin.New()
+	handler.POST("/articles", NewIdempotency(store, 0).Handle(KeyByIP), func(ctx *gin.Context) {
+		calls++
+		cancel()
+		ctx.Status(http.StatusCreated)
+	})
+
+	send := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader("{}")).WithContext(ctx)
+		req.RemoteAddr = "1.2.3.4:1000"
+		req.Header.Set(idempotency.Header, "key")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	send(ctx)
+
+	// Its retry gets the saved response rather than a conflict.
+	rr := send(context.Background())
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "true", rr.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_Handle_Panic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	store := idempotency.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	panics := true
+	handler := gin.New()
+	handler.POST("/articles", NewIdempotency(store, 0).Handle(KeyByIP), func(ctx *gin.Context) {
+		if panics {
+			panic("boom")
+		}
+		ctx.Status(http.StatusCreated)
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader("{}"))
+		req.RemoteAddr = "1.2.3.4:1000"
+		req.Header.Set(idempotency.Header, "key")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	// The panic goes on to the recovery middleware.
+	assert.PanicsWithValue(t, "boom", func() { send() })
+
+	// The key was released, so the retry is processed.
+	panics = false
+	rr := send()
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get(idempotency.ReplayedHeader))
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/logger"
+)
+
+const traceparentHeader = "traceparent"
+
+// LogContext stores the request ID and the trace ID into the request context,
+// so they can be attached to logs emitted further down, e.g. SQL logs from GORM.
+// It must be mounted after requestid.New().
+func LogContext() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reqCtx := ctx.Request.Context()
+		if requestID := requestid.Get(ctx); requestID != "" {
+			reqCtx = logger.WithRequestID(reqCtx, requestID)
+		}
+		if traceID := parseTraceID(ctx.GetHeader(traceparentHeader)); traceID != "" {
+			reqCtx = logger.WithTraceID(reqCtx, traceID)
+		}
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+
+		ctx.Next()
+	}
+}
+
+// parseTraceID extracts the trace ID from a W3C traceparent header,
+// which looks like "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func parseTraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+
+	return parts[1]
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/cursor"
 )
 
 const (
@@ -15,27 +17,85 @@ const (
 	maxPerPage     = 100
 )
 
-func Paginate() gin.HandlerFunc {
+var errPageWithCursor = errors.New("page cannot be used with cursor or limit")
+
+// Paginate parses the pagination queries into the gin context.
+//
+// Lists are paginated with offsets by default, PageQueryKey holds the page number and PerPageQueryKey the page size.
+// Sending the cursor or limit query switches to pagination with cursors decoded by codec instead:
+// CursorQueryKey holds the *domain.Cursor to start from, nil for the first page, and PerPageQueryKey holds the limit.
+// A nil codec disables cursors, for lists only paginated with offsets.
+// CountQueryKey tells whether the total count is requested in both modes.
+func Paginate(codec *cursor.Codec) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		pageNumber := ctx.Query(PageQueryKey)
-		parsedPageNumber, err := parsePageNumber(pageNumber)
+		count := ctx.Query(CountQueryKey)
+		parsedCount, err := parseCount(count)
 		if err != nil {
-			response.RenderErr(ctx, response.ErrInvalidInput(PageQueryKey, pageNumber))
+			response.RenderErr(ctx, response.ErrInvalidInput(CountQueryKey, count))
+
+			return
+		}
+
+		ctx.Set(CountQueryKey, parsedCount)
+
+		token, hasCursor := ctx.GetQuery(CursorQueryKey)
+		limit, hasLimit := ctx.GetQuery(LimitQueryKey)
+		if !hasCursor && !hasLimit {
+			pageNumber := ctx.Query(PageQueryKey)
+			parsedPageNumber, err := parsePageNumber(pageNumber)
+			if err != nil {
+				response.RenderErr(ctx, response.ErrInvalidInput(PageQueryKey, pageNumber))
+
+				return
+			}
+
+			perPage := ctx.Query(PerPageQueryKey)
+			parsedPerPage, err := parsePerPage(perPage)
+			if err != nil {
+				response.RenderErr(ctx, response.ErrInvalidInput(PerPageQueryKey, perPage))
+
+				return
+			}
+
+			ctx.Set(PageQueryKey, parsedPageNumber)
+			ctx.Set(PerPageQueryKey, parsedPerPage)
+			ctx.Next()
+
+			return
+		}
+
+		if codec == nil {
+			if hasCursor {
+				response.RenderErr(ctx, response.ErrInvalidInput(CursorQueryKey, token))
+			} else {
+				response.RenderErr(ctx, response.ErrInvalidInput(LimitQueryKey, limit))
+			}
+
+			return
+		}
+
+		if _, ok := ctx.GetQuery(PageQueryKey); ok {
+			response.RenderErr(ctx, response.ErrBadRequest(errPageWithCursor))
 
 			return
 		}
 
-		perPage := ctx.Query(PerPageQueryKey)
-		parsedPerPage, err := parsePerPage(perPage)
+		parsedLimit, err := parsePerPage(limit)
 		if err != nil {
-			response.RenderErr(ctx, response.ErrInvalidInput(PerPageQueryKey, perPage))
+			response.RenderErr(ctx, response.ErrInvalidInput(LimitQueryKey, limit))
 
 			return
 		}
 
-		ctx.Set(PageQueryKey, parsedPageNumber)
-		ctx.Set(PerPageQueryKey, parsedPerPage)
+		parsedCursor, err := parseCursor(codec, token)
+		if err != nil {
+			response.RenderErr(ctx, response.ErrInvalidInput(CursorQueryKey, token))
 
+			return
+		}
+
+		ctx.Set(PerPageQueryKey, parsedLimit)
+		ctx.Set(CursorQueryKey, parsedCursor)
 		ctx.Next()
 	}
 }
@@ -64,8 +124,34 @@ func parsePerPage(perPage string) (uint, error) {
 	}
 
 	if parsed > maxPerPage {
-		return defaultPerPage, nil
+		return maxPerPage, nil
 	}
 
 	return uint(parsed), nil
 }
+
+func parseCount(count string) (bool, error) {
+	if count == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(count)
+	if err != nil {
+		return false, errors.New("parse count query failed")
+	}
+
+	return parsed, nil
+}
+
+func parseCursor(codec *cursor.Codec, token string) (*domain.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var parsed domain.Cursor
+	if err := codec.Decode(token, &parsed); err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/response"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/jwthelper"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/ratelimit"
+)
+
+// KeyFunc identifies who is making the request.
+type KeyFunc func(ctx *gin.Context) string
+
+// KeyByIP identifies clients by their IP address.
+func KeyByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.RemoteIP()
+}
+
+// KeyByUser identifies clients by the user ID in the JWT claims, or by IP if not logged in.
+// It must be mounted after Authenticator.VerifyJWT.
+func KeyByUser(ctx *gin.Context) string {
+	claims, err := jwthelper.RetrieveClaimsFromContext(ctx)
+	if err != nil {
+		return KeyByIP(ctx)
+	}
+
+	return fmt.Sprintf("user:%d", claims.UserID)
+}
+
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+}
+
+func NewRateLimiter(limiter ratelimit.Limiter) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+	}
+}
+
+// Limit rate limits requests within the route group, per client identified by keyFunc.
+// Responses carry the RateLimit-* headers from https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+// and rejected requests get a 429 with Retry-After.
+func (rl *RateLimiter) Limit(group string, limit ratelimit.Limit, keyFunc KeyFunc) gin.HandlerFunc {
+	if rl.limiter == nil || limit.IsZero() {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		result, err := rl.limiter.Allow(ctx.Request.Context(), "ratelimit:"+group+":"+keyFunc(ctx), limit)
+		if err != nil {
+			// Fail open, an outage of the rate limit storage shouldn't take the API down.
+			zap.L().Error("rate limiting failed", zap.String("group", group), zap.Error(err))
+			ctx.Next()
+
+			return
+		}
+
+		ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Rate, int(limit.Period.Seconds())))
+		ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", seconds(result.ResetAfter))
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", seconds(result.RetryAfter))
+			response.RenderErr(ctx, response.ErrTooManyRequests(result.RetryAfter))
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// seconds rounds d up, so clients never retry too early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
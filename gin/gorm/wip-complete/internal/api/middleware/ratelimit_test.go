@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/ratelimit"
+)
+
+func TestRateLimiter_Limit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limit := ratelimit.Limit{Rate: 2, Period: time.Minute, Burst: 2}
+	router := gin.New()
+	router.GET("/", NewRateLimiter(ratelimit.NewMemoryLimiter()).Limit("test", limit, KeyByIP), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := send("1.2.3.4:1000")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2;w=60", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rr.Header().Get("RateLimit-Reset"))
+
+	// Same IP from a different port is the same client.
+	rr = send("1.2.3.4:2000")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	rr = send("1.2.3.4:3000")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "urn:gab:error:too_many_requests",
+		"title": "Too many requests",
+		"status": 429,
+		"detail": "too many requests, please retry after 30s",
+		"instance": "/",
+		"code": "too_many_requests"
+	}`, rr.Body.String())
+
+	rr = send("5.6.7.8:1000")
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRateLimiter_Limit_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", NewRateLimiter(nil).Limit("test", ratelimit.Limit{Rate: 1, Period: time.Minute}, KeyByIP), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	}
+}
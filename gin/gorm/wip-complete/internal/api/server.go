@@ -1,16 +1,24 @@
 package api
 
 import (
+	"io"
+	"sort"
+
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 
 	"github.com/yizeng/gab/gin/wip-complete/docs"
 	v1 "github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1"
 	"github.com/yizeng/gab/gin/wip-complete/internal/api/middleware"
 	"github.com/yizeng/gab/gin/wip-complete/internal/config"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/cursor"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/idempotency"
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/ratelimit"
 	"github.com/yizeng/gab/gin/wip-complete/internal/repository"
 	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
 	"github.com/yizeng/gab/gin/wip-complete/internal/service"
@@ -19,9 +27,15 @@ import (
 type Server struct {
 	Config *config.AppConfig
 	Router *gin.Engine
+
+	rateLimiter *middleware.RateLimiter
+	idempotency *middleware.Idempotency
+	cursors     *cursor.Codec
+	searchIndex service.SearchIndex
 }
 
-func NewServer(conf *config.AppConfig, db *gorm.DB) *Server {
+// NewServer creates the API server. rdb is optional, it's only needed by Redis backed features.
+func NewServer(conf *config.AppConfig, db *gorm.DB, rdb *redis.Client) *Server {
 	gin.SetMode(conf.Gin.Mode)
 	engine := gin.New()
 
@@ -29,6 +43,9 @@ func NewServer(conf *config.AppConfig, db *gorm.DB) *Server {
 		Config: conf,
 		Router: engine,
 	}
+	s.rateLimiter = middleware.NewRateLimiter(s.initRateLimiter(rdb))
+	s.idempotency = s.initIdempotency(db, rdb)
+	s.cursors = s.initCursorCodec()
 
 	s.MountMiddlewares()
 
@@ -40,11 +57,21 @@ func NewServer(conf *config.AppConfig, db *gorm.DB) *Server {
 	return s
 }
 
+// Close releases the resources held by the server, like the bleve search index.
+func (s *Server) Close() error {
+	if closer, ok := s.searchIndex.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
 func (s *Server) initArticleHandler(db *gorm.DB) *v1.ArticleHandler {
 	articleDAO := dao.NewArticleDAO(db)
 	repo := repository.NewArticleRepository(articleDAO)
-	svc := service.NewArticleService(repo)
-	handler := v1.NewArticleHandler(svc)
+	s.searchIndex = s.initSearchIndex(repo)
+	svc := service.NewArticleService(repo, s.searchIndex, s.initTxManager(db))
+	handler := v1.NewArticleHandler(svc, s.cursors, s.Config.Import)
 
 	return handler
 }
@@ -72,29 +99,45 @@ func (s *Server) MountMiddlewares() {
 	s.Router.Use(gin.Logger())
 	s.Router.Use(gin.Recovery())
 	s.Router.Use(requestid.New())
+	s.Router.Use(middleware.LogContext())
 	s.Router.Use(middleware.ConfigCORS(s.Config.API.AllowedCORSDomains))
 }
 
 func (s *Server) MountHandlers(authHandler *v1.AuthHandler, userHandler *v1.UserHandler, articleHandler *v1.ArticleHandler) {
 	const basePath = "/api/v1"
 
-	auth := s.Router.Group(basePath)
+	auth := s.Router.Group(basePath, s.rateLimit("auth", s.rateLimitConfig().Auth, middleware.KeyByIP))
 	{
-		auth.POST("/auth/signup", authHandler.HandleSignup)
+		auth.POST("/auth/signup", s.idempotency.Handle(middleware.KeyByIP), authHandler.HandleSignup)
 		auth.POST("/auth/login", authHandler.HandleLogin)
 	}
 
-	users := s.Router.Group(basePath, middleware.NewAuthenticator(s.Config.API.JWTSigningKey).VerifyJWT())
+	authenticator := middleware.NewAuthenticator(s.Config.API.JWTSigningKey)
+	users := s.Router.Group(basePath, authenticator.VerifyJWT(), s.rateLimit("users", s.rateLimitConfig().Users, middleware.KeyByUser))
 	{
 		users.GET("/users/:userID", userHandler.HandleGetUser)
 	}
 
-	articles := s.Router.Group(basePath)
+	admin := s.Router.Group(basePath, authenticator.VerifyJWT(), authenticator.RequireAdmin())
+	{
+		admin.GET("/admin/log/level", v1.HandleGetLogLevel)
+		admin.PUT("/admin/log/level", v1.HandleSetLogLevel)
+	}
+
+	articles := s.Router.Group(basePath, s.rateLimit("articles", s.rateLimitConfig().Articles, middleware.KeyByIP))
 	{
-		articles.GET("/articles", middleware.Paginate(), articleHandler.HandleListArticles)
-		articles.POST("/articles", articleHandler.HandleCreateArticle)
+		articles.GET("/articles", middleware.Paginate(s.cursors), articleHandler.HandleListArticles)
 		articles.GET("/articles/:articleID", articleHandler.HandleGetArticle)
-		articles.GET("/articles/search", articleHandler.HandleSearchArticles)
+		articles.GET("/articles/search", middleware.Paginate(nil), articleHandler.HandleSearchArticles)
+		articles.GET("/articles/export", articleHandler.HandleExportArticles)
+	}
+
+	authorArticles := s.Router.Group(basePath, authenticator.VerifyJWT(), s.rateLimit("author_articles", s.rateLimitConfig().AuthorArticles, middleware.KeyByUser))
+	{
+		authorArticles.POST("/articles", s.idempotency.Handle(middleware.KeyByUser), articleHandler.HandleCreateArticle)
+		authorArticles.POST("/articles/bulk", articleHandler.HandleImportArticles)
+		authorArticles.PUT("/articles/:articleID", articleHandler.HandleUpdateArticle)
+		authorArticles.DELETE("/articles/:articleID", articleHandler.HandleDeleteArticle)
 	}
 
 	s.Router.GET("/", v1.HandleHealthcheck)
@@ -103,7 +146,129 @@ func (s *Server) MountHandlers(authHandler *v1.AuthHandler, userHandler *v1.User
 	docs.SwaggerInfo.Host = s.Config.API.BaseURL
 	docs.SwaggerInfo.BasePath = basePath
 	docs.SwaggerInfo.Title = "API for gin/complete"
-	docs.SwaggerInfo.Description = "This is an example of Go API with Gin."
+	docs.SwaggerInfo.Description = "This is an example of Go API with Gin.\n\n" +
+		"Errors are returned as application/problem+json (RFC 9457), their `code` is stable and listed in response.Code.\n\n" +
+		"Titles and messages are translated into the language negotiated from Accept-Language (en, de, es)."
 	docs.SwaggerInfo.Version = "1.0"
 	s.Router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 }
+
+func (s *Server) initRateLimiter(rdb *redis.Client) ratelimit.Limiter {
+	conf := s.rateLimitConfig()
+
+	switch conf.Backend {
+	case config.RateLimitBackendMemory:
+		return ratelimit.NewMemoryLimiter()
+	case config.RateLimitBackendRedis:
+		if rdb == nil {
+			zap.L().Error("rate limiting is disabled because Redis isn't connected")
+
+			return nil
+		}
+
+		return ratelimit.NewRedisLimiter(rdb)
+	default:
+		return nil
+	}
+}
+
+func (s *Server) initIdempotency(db *gorm.DB, rdb *redis.Client) *middleware.Idempotency {
+	conf := s.Config.Idempotency
+	if conf == nil {
+		return middleware.NewIdempotency(nil, 0)
+	}
+
+	var store idempotency.Store
+	switch conf.Backend {
+	case config.IdempotencyBackendPostgres:
+		store = dao.NewIdempotencyDAO(db)
+	case config.IdempotencyBackendRedis:
+		if rdb == nil {
+			zap.L().Error("Idempotency-Key support is disabled because Redis isn't connected")
+
+			break
+		}
+
+		store = idempotency.NewRedisStore(rdb)
+	}
+
+	return middleware.NewIdempotency(store, conf.TTL)
+}
+
+func (s *Server) initSearchIndex(articleRepo *repository.ArticleRepository) service.SearchIndex {
+	conf := s.Config.Search
+	if conf != nil && conf.Backend == config.SearchBackendBleve {
+		index, err := repository.OpenBleveSearchIndex(conf.BlevePath)
+		if err == nil {
+			return index
+		}
+
+		zap.L().Error("falling back to postgres search because the bleve index can't be opened", zap.Error(err))
+	}
+
+	return repository.NewPostgresSearchIndex(articleRepo)
+}
+
+func (s *Server) initTxManager(db *gorm.DB) *dao.TxManager {
+	conf := s.Config.SQL()
+
+	return dao.NewTxManager(db, dao.TxConfig{
+		Isolation:    conf.TxIsolationLevel(),
+		MaxRetries:   conf.TxMaxRetries,
+		RetryBackoff: conf.TxRetryBackoff,
+	})
+}
+
+// initCursorCodec signs cursors with CursorSigningKey, or with a key derived from JWTSigningKey when it's empty,
+// so that cursors and JWTs are never signed with the same key.
+func (s *Server) initCursorCodec() *cursor.Codec {
+	key := s.Config.API.CursorSigningKey
+	if key == "" {
+		key = cursor.DeriveKey(s.Config.API.JWTSigningKey)
+	}
+
+	return cursor.NewCodec(key)
+}
+
+func (s *Server) rateLimitConfig() *config.RateLimitConfig {
+	if s.Config.RateLimit == nil {
+		return &config.RateLimitConfig{}
+	}
+
+	return s.Config.RateLimit
+}
+
+func (s *Server) rateLimit(group, rawLimit string, keyFunc middleware.KeyFunc) gin.HandlerFunc {
+	limit, err := ratelimit.ParseLimit(rawLimit)
+	if err != nil {
+		zap.L().Error("rate limiting is disabled because of invalid limit", zap.String("group", group), zap.Error(err))
+	}
+
+	return s.rateLimiter.Limit(group, limit, keyFunc)
+}
+
+// Route is a method and path served by the router.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Routes lists all the routes mounted on the router, sorted by path and method.
+func (s *Server) Routes() ([]Route, error) {
+	infos := s.Router.Routes()
+
+	routes := make([]Route, 0, len(infos))
+	for _, info := range infos {
+		routes = append(routes, Route{Method: info.Method, Path: info.Path})
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes, nil
+}
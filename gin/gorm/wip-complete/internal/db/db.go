@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/config"
+)
+
+const (
+	defaultConnectRetryBackoff = time.Second
+	maxConnectRetryBackoff     = 30 * time.Second
+)
+
+// Open connects to the database of the driver of conf, see config.AppConfig.DatabaseDriver.
+func Open(conf *config.AppConfig) (*gorm.DB, error) {
+	switch conf.DatabaseDriver() {
+	case config.DatabaseDriverMySQL:
+		return OpenMySQL(conf.MySQL, conf.API.Environment)
+	case config.DatabaseDriverSQLite:
+		return OpenSQLite(conf.SQLite, conf.API.Environment)
+	default:
+		return OpenPostgres(conf.Postgres, conf.API.Environment)
+	}
+}
+
+// openWithRetries calls open again conf.ConnectRetries times while the database isn't ready yet,
+// e.g. when they're started together.
+func openWithRetries(conf *config.SQLConfig, driver string, open func() (*gorm.DB, error)) (*gorm.DB, error) {
+	backoff := conf.ConnectRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultConnectRetryBackoff
+	}
+
+	for retries := 0; ; retries++ {
+		db, err := open()
+		if err == nil || retries >= conf.ConnectRetries {
+			return db, err
+		}
+
+		zap.L().Warn(
+			"retrying to connect to "+driver,
+			zap.Int("retries", retries+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectRetryBackoff)
+	}
+}
+
+// openGorm opens a database with the logger and the pool limits of conf.
+func openGorm(dialector gorm.Dialector, conf *config.SQLConfig, environment string) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: createLogger(conf, environment),
+	})
+	if err != nil {
+		closeDB(db)
+
+		return nil, fmt.Errorf("gorm.Open -> %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("db.DB -> %w", err)
+	}
+	setPoolLimits(sqlDB, conf)
+
+	return db, nil
+}
+
+func setPoolLimits(pool *sql.DB, conf *config.SQLConfig) {
+	if conf.MaxOpenConns > 0 {
+		pool.SetMaxOpenConns(conf.MaxOpenConns)
+	}
+	if conf.MaxIdleConns > 0 {
+		pool.SetMaxIdleConns(conf.MaxIdleConns)
+	}
+	if conf.ConnMaxLifetime > 0 {
+		pool.SetConnMaxLifetime(conf.ConnMaxLifetime)
+	}
+	if conf.ConnMaxIdleTime > 0 {
+		pool.SetConnMaxIdleTime(conf.ConnMaxIdleTime)
+	}
+}
+
+// closeDB closes the connections of a database that failed to open, gorm.Open doesn't.
+func closeDB(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
+
+func createLogger(conf *config.SQLConfig, environment string) logger.Interface {
+	// Bind parameters may contain emails, password hashes, etc.
+	// so they are only printed out during development.
+	redactParams := !strings.EqualFold(environment, "development")
+
+	return newZapLogger(parseLogLevel(conf.LogLevel), conf.SlowThreshold, redactParams)
+}
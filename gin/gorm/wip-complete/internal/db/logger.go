@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+
+	applogger "github.com/yizeng/gab/gin/wip-complete/internal/logger"
+)
+
+const defaultSlowThreshold = 200 * time.Millisecond
+
+// zapLogger implements GORM's logger.Interface on top of zap,
+// so SQL logs share the same format and sinks as the rest of the application.
+type zapLogger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+	redactParams  bool
+}
+
+func newZapLogger(level logger.LogLevel, slowThreshold time.Duration, redactParams bool) *zapLogger {
+	if slowThreshold <= 0 {
+		slowThreshold = defaultSlowThreshold
+	}
+
+	return &zapLogger{
+		level:         level,
+		slowThreshold: slowThreshold,
+		redactParams:  redactParams,
+	}
+}
+
+func (l *zapLogger) LogMode(level logger.LogLevel) logger.Interface {
+	newLogger := *l
+	newLogger.level = level
+
+	return &newLogger
+}
+
+func (l *zapLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.zap(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *zapLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.zap(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *zapLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.zap(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *zapLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	isSlow := elapsed > l.slowThreshold
+
+	var lvl zapcore.Level
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		lvl = zapcore.ErrorLevel
+	case isSlow && l.level >= logger.Warn:
+		lvl = zapcore.WarnLevel
+	case l.level >= logger.Info:
+		lvl = zapcore.InfoLevel
+	default:
+		return
+	}
+
+	log := l.zap(ctx)
+	if !log.Core().Enabled(lvl) {
+		return
+	}
+
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("source", utils.FileWithLineNum()),
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	switch lvl {
+	case zapcore.ErrorLevel:
+		log.Error("sql failed", fields...)
+	case zapcore.WarnLevel:
+		log.Warn("slow sql", append(fields, zap.Duration("threshold", l.slowThreshold))...)
+	default:
+		log.Info("sql", fields...)
+	}
+}
+
+// ParamsFilter is called by GORM before building the SQL in Trace.
+// Returning no params makes GORM keep the placeholders instead of the bind values.
+func (l *zapLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.redactParams {
+		return sql, nil
+	}
+
+	return sql, params
+}
+
+func (l *zapLogger) zap(ctx context.Context) *zap.Logger {
+	return zap.L().With(applogger.FieldsFromContext(ctx)...)
+}
+
+func parseLogLevel(logLevel string) logger.LogLevel {
+	switch strings.ToLower(logLevel) {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "warn":
+		return logger.Warn
+	case "info":
+		return logger.Info
+	default:
+		return logger.Error
+	}
+}
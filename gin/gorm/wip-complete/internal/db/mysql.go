@@ -0,0 +1,34 @@
+package db
+
+import (
+	"net"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/config"
+)
+
+// OpenMySQL connects to the MySQL database of conf.
+func OpenMySQL(conf *config.MySQLConfig, environment string) (*gorm.DB, error) {
+	return openWithRetries(&conf.SQLConfig, "mysql", func() (*gorm.DB, error) {
+		return openGorm(mysql.Open(buildMySQLDSN(conf)), &conf.SQLConfig, environment)
+	})
+}
+
+// buildMySQLDSN returns the connection string of the database of conf.
+// Times are read as time.Time in UTC, and scripts like migrations can have several statements.
+func buildMySQLDSN(conf *config.MySQLConfig) string {
+	dsn := mysqldriver.NewConfig()
+	dsn.User = conf.User
+	dsn.Passwd = conf.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(conf.Host, conf.Port)
+	dsn.DBName = conf.DB
+	dsn.TLSConfig = conf.TLS
+	dsn.ParseTime = true
+	dsn.MultiStatements = true
+
+	return dsn.FormatDSN()
+}
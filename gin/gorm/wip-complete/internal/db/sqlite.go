@@ -0,0 +1,31 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/config"
+)
+
+// sqliteParams enforce foreign keys, which SQLite doesn't by default, let readers work along with a writer,
+// and make writers wait for each other rather than fail with "database is locked".
+const sqliteParams = "_foreign_keys=1&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+
+// OpenSQLite opens the SQLite database of conf, which is created if it doesn't exist.
+func OpenSQLite(conf *config.SQLiteConfig, environment string) (*gorm.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(conf.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll -> %w", err)
+	}
+
+	return openWithRetries(&conf.SQLConfig, "sqlite", func() (*gorm.DB, error) {
+		return openGorm(sqlite.Open(buildSQLiteDSN(conf.Path)), &conf.SQLConfig, environment)
+	})
+}
+
+func buildSQLiteDSN(path string) string {
+	return "file:" + path + "?" + sqliteParams
+}
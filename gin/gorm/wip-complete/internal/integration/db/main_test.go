@@ -0,0 +1,34 @@
+package db
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/fixtures"
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/testdb"
+)
+
+var (
+	// template is migrated and seeded in the preferred test database, with the seeded fixtures.
+	template *testdb.Template
+	seeded   fixtures.Set
+
+	// sqliteTemplate is the same in SQLite, whatever other database is available.
+	sqliteTemplate *testdb.Template
+	sqliteSeeded   fixtures.Set
+)
+
+func TestMain(m *testing.M) {
+	template = testdb.NewTemplate(fixtures.MigrateAndSeed(&seeded))
+	sqliteTemplate = testdb.NewTemplateWith(fixtures.MigrateAndSeed(&sqliteSeeded), testdb.SQLite)
+
+	code := m.Run()
+
+	if err := errors.Join(template.Close(), sqliteTemplate.Close()); err != nil {
+		log.Printf("failed to close the test databases: %v", err)
+	}
+
+	os.Exit(code)
+}
@@ -1,16 +1,21 @@
 package e2e
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/dchest/uniuri"
 	"github.com/gin-gonic/gin"
-	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -21,81 +26,41 @@ import (
 	"github.com/yizeng/gab/gin/wip-complete/internal/api/handler/v1/response"
 	"github.com/yizeng/gab/gin/wip-complete/internal/config"
 	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/fixtures"
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/testdb"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository"
 	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
-	"github.com/yizeng/gab/gin/wip-complete/pkg/dockertester"
+	"github.com/yizeng/gab/gin/wip-complete/internal/service"
 )
 
-var (
-	testArticle999 = domain.Article{
-		ID:      999,
-		UserID:  123,
-		Title:   "seeded title 999",
-		Content: "seeded content 999",
-	}
-	testArticle888 = domain.Article{
-		ID:      888,
-		UserID:  123,
-		Title:   "seeded title 888",
-		Content: "seeded content 888",
-	}
-	testDBErr = errors.New("DB error")
-)
+var testDBErr = errors.New("DB error")
 
 type ArticleHandlerTestSuite struct {
 	suite.Suite
 
-	db       *gorm.DB
-	pool     *dockertest.Pool
-	resource *dockertest.Resource
-	server   *api.Server
-}
-
-func (s *ArticleHandlerTestSuite) SetupSuite() {
-	// Initialize container.
-	dt := dockertester.InitPostgres()
-	s.pool = dt.Pool
-	s.resource = dt.Resource
-
-	// Open connection.
-	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
-	require.NoError(s.T(), err)
-
-	s.db = db
-}
-
-func (s *ArticleHandlerTestSuite) TearDownSuite() {
-	err := s.pool.Purge(s.resource) // Destroy the container.
-	require.NoError(s.T(), err)
+	db      *gorm.DB
+	server  *api.Server
+	factory *fixtures.Factory
 }
 
 func (s *ArticleHandlerTestSuite) SetupTest() {
-	// Run migrations.
-	err := dao.InitTables(s.db)
-	require.NoError(s.T(), err)
-
-	// Seed database.
-	script, err := os.ReadFile("../scripts/seed_db.sql")
-	require.NoError(s.T(), err)
-
-	err = s.db.Exec(string(script)).Error
-	require.NoError(s.T(), err)
+	s.db = template.Clone(s.T())
+	s.factory = fixtures.NewFactory(s.db)
 
 	// Create API server.
 	s.server = api.NewServer(&config.AppConfig{
-		API: &config.APIConfig{},
+		API: &config.APIConfig{
+			JWTSigningKey: jwtSigningKey,
+		},
 		Gin: &config.GinConfig{
 			Mode: gin.TestMode,
 		},
 		Postgres: &config.PostgresConfig{},
-	}, s.db)
-}
-
-func (s *ArticleHandlerTestSuite) TearDownTest() {
-	s.cleanDB()
+	}, s.db, nil)
 }
 
 func (s *ArticleHandlerTestSuite) cleanDB() {
-	script, err := os.ReadFile("../scripts/clean_db.sql")
+	script, err := os.ReadFile(testdb.CleanScript(s.db))
 	require.NoError(s.T(), err)
 
 	err = s.db.Exec(string(script)).Error
@@ -104,16 +69,26 @@ func (s *ArticleHandlerTestSuite) cleanDB() {
 
 func (s *ArticleHandlerTestSuite) createDBError() {
 	// Create/fake a DB error by dropping the articles table.
-	err := s.db.Exec(`DROP TABLE "articles"`).Error
+	err := s.db.Migrator().DropTable(&dao.Article{})
 	require.NoError(s.T(), err)
 }
 
 func TestArticleHandler(t *testing.T) {
+	t.Parallel()
 	suite.Run(t, new(ArticleHandlerTestSuite))
 }
 
 func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
+	author, beta := seeded.Users["author"], seeded.Articles["beta"]
+
+	// Only admins can write articles for other users, including ones that don't exist.
+	admin, err := s.factory.User(context.TODO(), func(user *domain.User) {
+		user.IsAdmin = true
+	})
+	require.NoError(s.T(), err)
+
 	type args struct {
+		caller       domain.User // author when empty
 		buildReqBody func() string
 	}
 	type want struct {
@@ -134,7 +109,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			args: args{
 				buildReqBody: func() string {
 					article := request.CreateArticleRequest{
-						UserID:  123,
+						UserID:  author.ID,
 						Title:   "title 1",
 						Content: "content 1",
 					}
@@ -147,7 +122,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			},
 			want: want{
 				article: domain.Article{
-					UserID:  123,
+					UserID:  author.ID,
 					Title:   "title 1",
 					Content: "content 1",
 				},
@@ -175,7 +150,15 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			want: want{
 				article:  domain.Article{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("content: the length must be between 1 and 5000; title: the length must be between 1 and 128; user_id: cannot be blank.")),
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "content: the length must be between 1 and 5000; title: the length must be between 1 and 128; user_id: cannot be blank.",
+					Errors: []response.FieldError{
+						{Field: "content", Code: "validation_length_out_of_range"},
+						{Field: "title", Code: "validation_length_out_of_range"},
+						{Field: "user_id", Code: "validation_required"},
+					},
+				},
 			},
 			wantErr: true,
 		},
@@ -190,7 +173,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			want: want{
 				article:  domain.Article{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("unexpected EOF")),
+				err:      response.ErrBadRequest(&request.SyntaxError{Line: 1, Column: 2, Msg: "unexpected end of JSON input"}),
 			},
 			wantErr: true,
 		},
@@ -200,9 +183,34 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			args: args{
 				buildReqBody: func() string {
 					article := request.CreateArticleRequest{
-						UserID:  123,
-						Title:   "seeded title 999",
-						Content: "seeded content 999",
+						UserID:  beta.UserID,
+						Title:   beta.Title,
+						Content: beta.Content,
+					}
+
+					body, err := json.Marshal(article)
+					require.NoError(s.T(), err)
+
+					return string(body)
+				},
+			},
+			want: want{
+				article:  domain.Article{},
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(service.ErrArticleDuplicated),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Unknown author",
+			setup: func() {},
+			args: args{
+				caller: admin,
+				buildReqBody: func() string {
+					article := request.CreateArticleRequest{
+						UserID:  456,
+						Title:   "title 1",
+						Content: "content 1",
 					}
 
 					body, err := json.Marshal(article)
@@ -214,7 +222,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			want: want{
 				article:  domain.Article{},
 				respCode: http.StatusBadRequest,
-				err:      response.ErrBadRequest(errors.New("article already exists")),
+				err:      response.ErrBadRequest(service.ErrUserNotFound),
 			},
 			wantErr: true,
 		},
@@ -226,7 +234,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			args: args{
 				buildReqBody: func() string {
 					article := request.CreateArticleRequest{
-						UserID:  123,
+						UserID:  author.ID,
 						Title:   "title 1",
 						Content: "content 1",
 					}
@@ -254,6 +262,11 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 			body := tt.args.buildReqBody()
 			req, err := http.NewRequest("POST", "/api/v1/articles", strings.NewReader(body))
 			require.NoError(t, err)
+			caller := tt.args.caller
+			if caller.ID == 0 {
+				caller = author
+			}
+			authorize(t, req, caller)
 
 			// Execute Request.
 			resp := executeRequest(req, s.server)
@@ -266,8 +279,9 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
-				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
 			} else {
 				var result domain.Article
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
@@ -281,7 +295,186 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleCreateArticle() {
 	}
 }
 
+func (s *ArticleHandlerTestSuite) TestArticleHandler_Unauthenticated() {
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{"POST", "/api/v1/articles"},
+		{"POST", "/api/v1/articles/bulk"},
+		{"PUT", "/api/v1/articles/1"},
+		{"DELETE", "/api/v1/articles/1"},
+	}
+	for _, route := range routes {
+		req, err := http.NewRequest(route.method, route.path, strings.NewReader("{}"))
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, s.server)
+		assert.Equal(s.T(), http.StatusUnauthorized, resp.Code, route.path)
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleImportArticles() {
+	author, beta := seeded.Users["author"], seeded.Articles["beta"]
+
+	type want struct {
+		respCode int
+		statuses []string // of each row
+		summary  response.ImportSummary
+		articles int64 // stored once the import is done
+		err      *response.Err
+	}
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		want        want
+	}{
+		{
+			name:        "200 OK - NDJSON",
+			contentType: request.ContentTypeNDJSON,
+			body: fmt.Sprintf(`{"user_id": %d, "title": "imported 1", "content": "content 1"}`+"\n", author.ID) +
+				fmt.Sprintf(`{"user_id": %d, "title": %q, "content": "content 2"}`+"\n", beta.UserID, beta.Title) +
+				fmt.Sprintf(`{"user_id": %d, "title": "", "content": "content 3"}`+"\n", author.ID) +
+				`{"user_id": 456, "title": "imported 4", "content": "content 4"}` + "\n" +
+				fmt.Sprintf(`{"user_id": %d, "title": "imported 5", "content": "content 5"}`+"\n", author.ID),
+			want: want{
+				respCode: http.StatusOK,
+				statuses: []string{response.ImportCreated, response.ImportDuplicate, response.ImportInvalid, response.ImportInvalid, response.ImportCreated},
+				summary:  response.ImportSummary{Rows: 5, Created: 2, Duplicate: 1, Invalid: 2, Committed: true},
+				articles: 4,
+			},
+		},
+		{
+			name:        "200 OK - Atomic CSV",
+			query:       "?atomic=true",
+			contentType: request.ContentTypeCSV,
+			body:        fmt.Sprintf("user_id,title,content\n%[1]d,imported 1,content 1\n%[1]d,imported 2,content 2\n%[1]d,imported 3,content 3\n", author.ID),
+			want: want{
+				respCode: http.StatusOK,
+				statuses: []string{response.ImportCreated, response.ImportCreated, response.ImportCreated},
+				summary:  response.ImportSummary{Rows: 3, Created: 3, Committed: true},
+				articles: 5,
+			},
+		},
+		{
+			name:        "200 OK - Atomic import rolled back",
+			query:       "?atomic=true",
+			contentType: request.ContentTypeCSV,
+			body:        fmt.Sprintf("user_id,title,content\n%[1]d,imported 1,content 1\n%[1]d,imported 2,content 2\n%[1]d,imported 1,content 3\n", author.ID),
+			want: want{
+				respCode: http.StatusOK,
+				statuses: []string{response.ImportCreated, response.ImportCreated, response.ImportDuplicate},
+				summary:  response.ImportSummary{Rows: 3, Created: 2, Duplicate: 1},
+				articles: 2,
+			},
+		},
+		{
+			name:        "400 Bad Request - Unknown CSV column",
+			contentType: request.ContentTypeCSV,
+			body:        fmt.Sprintf("user_id,title,body\n%d,imported 1,content 1\n", author.ID),
+			want: want{
+				respCode: http.StatusBadRequest,
+				articles: 2,
+				err:      response.ErrBadRequest(errors.New(`unknown CSV column "body"`)),
+			},
+		},
+		{
+			name:        "400 Bad Request - Invalid atomic",
+			query:       "?atomic=maybe",
+			contentType: request.ContentTypeCSV,
+			body:        "user_id,title,content\n",
+			want: want{
+				respCode: http.StatusBadRequest,
+				articles: 2,
+				err:      response.ErrInvalidInput("atomic", "maybe"),
+			},
+		},
+		{
+			name:        "415 Unsupported Media Type",
+			contentType: "application/json",
+			body:        fmt.Sprintf(`[{"user_id": %d, "title": "imported 1", "content": "content 1"}]`, author.ID),
+			want: want{
+				respCode: http.StatusUnsupportedMediaType,
+				articles: 2,
+				err:      response.ErrUnsupportedMediaType(request.ErrUnsupportedImport),
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Each import starts from the seeded articles.
+			s.SetupTest()
+
+			// Small batches so imports span several transactions.
+			server := api.NewServer(&config.AppConfig{
+				API: &config.APIConfig{
+					JWTSigningKey: jwtSigningKey,
+				},
+				Gin: &config.GinConfig{
+					Mode: gin.TestMode,
+				},
+				Postgres: &config.PostgresConfig{},
+				Import:   &config.ImportConfig{BatchSize: 2},
+			}, s.db, nil)
+			defer server.Close()
+
+			req, err := http.NewRequest("POST", "/api/v1/articles/bulk"+tt.query, strings.NewReader(tt.body))
+			require.NoError(s.T(), err)
+			req.Header.Set("Content-Type", tt.contentType)
+			authorize(s.T(), req, author)
+
+			resp := executeRequest(req, server)
+			require.Equal(s.T(), tt.want.respCode, resp.Code)
+
+			var count int64
+			err = s.db.Model(&dao.Article{}).Count(&count).Error
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tt.want.articles, count)
+
+			if tt.want.err != nil {
+				var result response.Err
+				err = json.Unmarshal(resp.Body.Bytes(), &result)
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), tt.want.err.Code, result.Code)
+				assert.Equal(s.T(), tt.want.err.Detail, result.Detail)
+
+				return
+			}
+
+			assert.Equal(s.T(), request.ContentTypeNDJSON, resp.Header().Get("Content-Type"))
+			lines := strings.Split(strings.TrimSuffix(resp.Body.String(), "\n"), "\n")
+			require.Equal(s.T(), len(tt.want.statuses)+1, len(lines))
+
+			for i, status := range tt.want.statuses {
+				var row response.ImportRow
+				err = json.Unmarshal([]byte(lines[i]), &row)
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), i+1, row.Row)
+				assert.Equal(s.T(), status, row.Status)
+				if status == response.ImportCreated {
+					require.NotNil(s.T(), row.Article)
+					assert.NotZero(s.T(), row.Article.ID)
+					assert.Nil(s.T(), row.Error)
+				} else {
+					assert.NotNil(s.T(), row.Error)
+				}
+			}
+
+			var summary struct {
+				Summary response.ImportSummary `json:"summary"`
+			}
+			err = json.Unmarshal([]byte(lines[len(lines)-1]), &summary)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tt.want.summary, summary.Summary)
+		})
+	}
+}
+
 func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
+	beta := seeded.Articles["beta"]
+
 	type args struct {
 		articleID string
 	}
@@ -301,10 +494,10 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
 			name:  "200 OK",
 			setup: func() {},
 			args: args{
-				articleID: "999",
+				articleID: fmt.Sprint(beta.ID),
 			},
 			want: want{
-				article:  testArticle999,
+				article:  beta,
 				respCode: http.StatusOK,
 				err:      nil,
 			},
@@ -314,12 +507,12 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
 			name:  "404 Not Found - articleID is not found",
 			setup: func() {},
 			args: args{
-				articleID: "1",
+				articleID: "999",
 			},
 			want: want{
 				article:  domain.Article{},
 				respCode: http.StatusNotFound,
-				err:      response.ErrNotFound("article", "ID", "1"),
+				err:      response.ErrNotFound("article", "ID", "999"),
 			},
 			wantErr: true,
 		},
@@ -355,7 +548,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
 				s.createDBError()
 			},
 			args: args{
-				articleID: "999",
+				articleID: fmt.Sprint(beta.ID),
 			},
 			want: want{
 				article:  domain.Article{},
@@ -385,8 +578,9 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
-				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
 			} else {
 				var result domain.Article
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
@@ -401,6 +595,8 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleGetArticle() {
 }
 
 func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
+	alpha, beta := seeded.Articles["alpha"], seeded.Articles["beta"]
+
 	type args struct {
 		query string
 	}
@@ -420,12 +616,12 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
 			name:  "200 OK",
 			setup: func() {},
 			args: args{
-				query: "",
+				query: "?page=1",
 			},
 			want: want{
 				articles: []domain.Article{
-					testArticle999,
-					testArticle888,
+					alpha,
+					beta,
 				},
 				respCode: http.StatusOK,
 				err:      nil,
@@ -440,7 +636,7 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
 			},
 			want: want{
 				articles: []domain.Article{
-					testArticle888,
+					beta,
 				},
 				respCode: http.StatusOK,
 				err:      nil,
@@ -462,6 +658,45 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "200 OK - Sorted and filtered",
+			setup: func() {},
+			args: args{
+				query: fmt.Sprintf("?page=1&sort=-title&filter[user_id]=%d&filter[title][contains]=SEEDED&filter[created_at][gte]=2024-01-01", alpha.UserID),
+			},
+			want: want{
+				articles: []domain.Article{
+					beta,
+					alpha,
+				},
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "400 Bad Request - Invalid sort and filter queries",
+			setup: func() {},
+			args: args{
+				query: "?limit=10&sort=title&filter[user_id][gt]=1&filter[id]=abc",
+			},
+			want: want{
+				articles: []domain.Article{},
+				respCode: http.StatusBadRequest,
+				err: &response.Err{
+					Code: response.CodeValidationFailed,
+					Detail: "filter[id]: must be of type integer; " +
+						"filter[user_id][gt]: operator gt isn't supported by field user_id; " +
+						"sort: field title can't be sorted by.",
+					Errors: []response.FieldError{
+						{Field: "filter[id]", Code: "query_invalid_value"},
+						{Field: "filter[user_id][gt]", Code: "query_unsupported_operator"},
+						{Field: "sort", Code: "query_field_not_sortable"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name:  "400 Bad Request - Invalid page query",
 			setup: func() {},
@@ -532,6 +767,9 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
 	}
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
+			// Start from the seeded articles, as setups delete them.
+			s.SetupTest()
+
 			// Setup tests when present.
 			tt.setup()
 
@@ -550,8 +788,9 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
-				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
 			} else {
 				var result []domain.Article
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
@@ -571,7 +810,297 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles() {
 	}
 }
 
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleListArticles_Cursor() {
+	alpha, beta := seeded.Articles["alpha"], seeded.Articles["beta"]
+
+	list := func(query string) (*response.Page[domain.Article], http.Header) {
+		req, err := http.NewRequest("GET", "/api/v1/articles"+query, nil)
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, s.server)
+		require.Equal(s.T(), http.StatusOK, resp.Code)
+
+		var result response.Page[domain.Article]
+		err = json.Unmarshal(resp.Body.Bytes(), &result)
+		require.NoError(s.T(), err)
+
+		return &result, resp.Header()
+	}
+
+	// Articles are sorted by (created_at, id), the seeded ones were created at the same time.
+	first, header := list("?limit=1&count=true")
+	require.Len(s.T(), first.Data, 1)
+	assert.Equal(s.T(), alpha.Title, first.Data[0].Title)
+	assert.Empty(s.T(), first.Prev)
+	assert.NotEmpty(s.T(), first.Next)
+	assert.EqualValues(s.T(), 2, *first.Total)
+	assert.Equal(s.T(), "2", header.Get("X-Total-Count"))
+	assert.Equal(s.T(),
+		`</api/v1/articles?count=true&limit=1>; rel="first", `+
+			`</api/v1/articles?count=true&cursor=`+first.Next+`&limit=1>; rel="next"`,
+		header.Get("Link"),
+	)
+
+	second, header := list("?limit=1&cursor=" + first.Next)
+	require.Len(s.T(), second.Data, 1)
+	assert.Equal(s.T(), beta.Title, second.Data[0].Title)
+	assert.NotEmpty(s.T(), second.Prev)
+	assert.Empty(s.T(), second.Next)
+	assert.Nil(s.T(), second.Total)
+	assert.Empty(s.T(), header.Get("X-Total-Count"))
+	assert.Contains(s.T(), header.Get("Link"), `rel="prev"`)
+	assert.NotContains(s.T(), header.Get("Link"), `rel="next"`)
+
+	back, _ := list("?limit=1&cursor=" + second.Prev)
+	require.Len(s.T(), back.Data, 1)
+	assert.Equal(s.T(), alpha.Title, back.Data[0].Title)
+	assert.Empty(s.T(), back.Prev)
+	assert.Equal(s.T(), first.Next, back.Next)
+
+	// Filters and sorts apply to every page and are kept in the links.
+	desc, header := list(fmt.Sprintf("?limit=1&sort=-created_at&filter[user_id][in]=%d,456", beta.UserID))
+	require.Len(s.T(), desc.Data, 1)
+	assert.Equal(s.T(), beta.Title, desc.Data[0].Title)
+	assert.Contains(s.T(), header.Get("Link"), "sort=-created_at")
+
+	desc, _ = list(fmt.Sprintf("?limit=1&sort=-created_at&filter[user_id][in]=%d,456&cursor=%s", beta.UserID, desc.Next))
+	require.Len(s.T(), desc.Data, 1)
+	assert.Equal(s.T(), alpha.Title, desc.Data[0].Title)
+	assert.Empty(s.T(), desc.Next)
+
+	// An empty cursor loads the first page.
+	filtered, _ := list("?cursor=&count=true&filter[title]=seeded%20title%20beta")
+	require.Len(s.T(), filtered.Data, 1)
+	assert.EqualValues(s.T(), 1, *filtered.Total)
+
+	// Lists are paginated with offsets without cursor nor limit, which returns a bare array.
+	req, err := http.NewRequest("GET", "/api/v1/articles?per_page=1", nil)
+	require.NoError(s.T(), err)
+
+	resp := executeRequest(req, s.server)
+	require.Equal(s.T(), http.StatusOK, resp.Code)
+	var articles []domain.Article
+	err = json.Unmarshal(resp.Body.Bytes(), &articles)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), articles, 1)
+	assert.Equal(s.T(), alpha.Title, articles[0].Title)
+
+	// Offset pagination links to the other pages by number.
+	req, err = http.NewRequest("GET", "/api/v1/articles?page=2&per_page=1&count=true", nil)
+	require.NoError(s.T(), err)
+
+	resp = executeRequest(req, s.server)
+	assert.Equal(s.T(), http.StatusOK, resp.Code)
+	assert.Equal(s.T(), "2", resp.Header().Get("X-Total-Count"))
+	assert.Equal(s.T(),
+		`</api/v1/articles?count=true&page=1&per_page=1>; rel="first", `+
+			`</api/v1/articles?count=true&page=1&per_page=1>; rel="prev", `+
+			`</api/v1/articles?count=true&page=2&per_page=1>; rel="last"`,
+		resp.Header().Get("Link"),
+	)
+
+	// Cursors can't be forged.
+	req, err = http.NewRequest("GET", "/api/v1/articles?cursor=forged", nil)
+	require.NoError(s.T(), err)
+
+	resp = executeRequest(req, s.server)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.Code)
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_FieldsAndInclude() {
+	author := seeded.Users["author"]
+	alpha, beta := seeded.Articles["alpha"], seeded.Articles["beta"]
+
+	get := func(url string) (int, []byte) {
+		req, err := http.NewRequest("GET", url, nil)
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, s.server)
+
+		return resp.Code, resp.Body.Bytes()
+	}
+
+	code, body := get("/api/v1/articles?limit=10&fields=id,title&include=author")
+	require.Equal(s.T(), http.StatusOK, code)
+	var page response.Page[map[string]json.RawMessage]
+	err := json.Unmarshal(body, &page)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Data, 2)
+	for _, article := range page.Data {
+		keys := []string{}
+		for key := range article {
+			keys = append(keys, key)
+		}
+		assert.ElementsMatch(s.T(), []string{"id", "title", "author"}, keys)
+
+		// Only the public profile of authors is embedded, articles are read without a JWT.
+		var got map[string]any
+		err = json.Unmarshal(article["author"], &got)
+		require.NoError(s.T(), err)
+		assert.EqualValues(s.T(), author.ID, got["id"])
+		assert.NotContains(s.T(), got, "email")
+		assert.NotContains(s.T(), got, "is_admin")
+		assert.NotContains(s.T(), got, "password")
+	}
+
+	code, body = get("/api/v1/articles?fields=title")
+	require.Equal(s.T(), http.StatusOK, code)
+	assert.JSONEq(s.T(), fmt.Sprintf(`[{"title": %q}, {"title": %q}]`, alpha.Title, beta.Title), string(body))
+
+	code, body = get(fmt.Sprintf("/api/v1/articles/%d?fields=content,id", beta.ID))
+	require.Equal(s.T(), http.StatusOK, code)
+	assert.Equal(s.T(), fmt.Sprintf(`{"content":%q,"id":%d}`, beta.Content, beta.ID), string(body))
+
+	code, body = get(fmt.Sprintf("/api/v1/articles/%d?include=author", beta.ID))
+	require.Equal(s.T(), http.StatusOK, code)
+	var article domain.Article
+	err = json.Unmarshal(body, &article)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), beta.Title, article.Title)
+	require.NotNil(s.T(), article.Author)
+	assert.Equal(s.T(), author.ID, article.Author.ID)
+	assert.NotContains(s.T(), string(body), "email")
+	assert.NotContains(s.T(), string(body), "is_admin")
+
+	// Authors are only embedded on request.
+	code, body = get(fmt.Sprintf("/api/v1/articles/%d", beta.ID))
+	require.Equal(s.T(), http.StatusOK, code)
+	assert.NotContains(s.T(), string(body), "author")
+
+	code, body = get("/api/v1/articles?fields=id,password&include=comments")
+	require.Equal(s.T(), http.StatusBadRequest, code)
+	var result response.Err
+	err = json.Unmarshal(body, &result)
+	require.NoError(s.T(), err)
+	assertFieldErrors(s.T(), []response.FieldError{
+		{Field: "fields", Code: "query_unknown_field"},
+		{Field: "include", Code: "query_unknown_relation"},
+	}, result.Errors)
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleExportArticles() {
+	alpha, beta := seeded.Articles["alpha"], seeded.Articles["beta"]
+	titles := map[uint]string{alpha.ID: alpha.Title, beta.ID: beta.Title}
+
+	tests := []struct {
+		name            string
+		query           string
+		wantCode        int
+		wantContentType string
+		wantIDs         []uint // of the exported articles, in order
+		wantErrCodes    map[string]string
+		fullTextSearch  bool // skipped unless the test database is PostgreSQL
+	}{
+		{
+			name:            "200 OK - JSON by default",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+			wantIDs:         []uint{alpha.ID, beta.ID},
+		},
+		{
+			name:            "200 OK - NDJSON sorted",
+			query:           "format=ndjson&sort=-id",
+			wantCode:        http.StatusOK,
+			wantContentType: request.ContentTypeNDJSON,
+			wantIDs:         []uint{beta.ID, alpha.ID},
+		},
+		{
+			name:            "200 OK - CSV filtered",
+			query:           fmt.Sprintf("format=csv&filter[id][gt]=%d", alpha.ID),
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantIDs:         []uint{beta.ID},
+		},
+		{
+			name:            "200 OK - Searched",
+			query:           "q=alpha",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+			wantIDs:         []uint{alpha.ID},
+			fullTextSearch:  true,
+		},
+		{
+			name:     "400 Bad Request - Invalid format",
+			query:    "format=xml",
+			wantCode: http.StatusBadRequest,
+			wantErrCodes: map[string]string{
+				"format": "validation_in_invalid",
+			},
+		},
+		{
+			name:     "400 Bad Request - Unknown filter",
+			query:    "filter[password]=x",
+			wantCode: http.StatusBadRequest,
+			wantErrCodes: map[string]string{
+				"filter[password]": "query_unknown_field",
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			if tt.fullTextSearch {
+				testdb.SkipUnlessPostgres(s.T(), s.db)
+			}
+
+			req, err := http.NewRequest("GET", "/api/v1/articles/export?"+tt.query, nil)
+			require.NoError(s.T(), err)
+
+			resp := executeRequest(req, s.server)
+			require.Equal(s.T(), tt.wantCode, resp.Code)
+
+			if tt.wantErrCodes != nil {
+				var result response.Err
+				err = json.Unmarshal(resp.Body.Bytes(), &result)
+				require.NoError(s.T(), err)
+
+				codes := map[string]string{}
+				for _, fieldErr := range result.Errors {
+					codes[fieldErr.Field] = fieldErr.Code
+				}
+				assert.Equal(s.T(), tt.wantErrCodes, codes)
+
+				return
+			}
+
+			assert.Equal(s.T(), tt.wantContentType, resp.Header().Get("Content-Type"))
+
+			var articles []domain.Article
+			switch {
+			case strings.HasPrefix(tt.wantContentType, "text/csv"):
+				records, err := csv.NewReader(resp.Body).ReadAll()
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), response.ArticleExportColumns, records[0])
+				for _, record := range records[1:] {
+					id, err := strconv.ParseUint(record[0], 10, 0)
+					require.NoError(s.T(), err)
+					articles = append(articles, domain.Article{ID: uint(id), Title: record[2]})
+				}
+			case tt.wantContentType == request.ContentTypeNDJSON:
+				decoder := json.NewDecoder(resp.Body)
+				for decoder.More() {
+					var article domain.Article
+					require.NoError(s.T(), decoder.Decode(&article))
+					articles = append(articles, article)
+				}
+			default:
+				err = json.Unmarshal(resp.Body.Bytes(), &articles)
+				require.NoError(s.T(), err)
+			}
+
+			ids := make([]uint, 0, len(articles))
+			for _, article := range articles {
+				ids = append(ids, article.ID)
+				assert.Equal(s.T(), titles[article.ID], article.Title)
+			}
+			assert.Equal(s.T(), tt.wantIDs, ids)
+		})
+	}
+}
+
 func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles() {
+	testdb.SkipUnlessPostgres(s.T(), s.db)
+	alpha, beta := seeded.Articles["alpha"], seeded.Articles["beta"]
+
 	type args struct {
 		query string
 	}
@@ -591,11 +1120,11 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles() {
 			name:  "200 OK - by title",
 			setup: func() {},
 			args: args{
-				query: "title=999",
+				query: "q=title+beta",
 			},
 			want: want{
 				articles: []domain.Article{
-					testArticle999,
+					beta,
 				},
 				respCode: http.StatusOK,
 				err:      nil,
@@ -606,11 +1135,11 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles() {
 			name:  "200 OK - by content",
 			setup: func() {},
 			args: args{
-				query: "content=999",
+				query: "q=CONTENTS+alpha",
 			},
 			want: want{
 				articles: []domain.Article{
-					testArticle999,
+					alpha,
 				},
 				respCode: http.StatusOK,
 				err:      nil,
@@ -618,41 +1147,73 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles() {
 			wantErr: false,
 		},
 		{
-			name:  "200 OK - When there are no results",
+			name:  "200 OK - Web search syntax",
 			setup: func() {},
 			args: args{
-				query: "title=no-title&content=no-content",
+				query: "q=seeded+-beta&lang=simple",
 			},
 			want: want{
-				articles: []domain.Article{},
+				articles: []domain.Article{
+					alpha,
+				},
 				respCode: http.StatusOK,
 				err:      nil,
 			},
 			wantErr: false,
 		},
 		{
-			name:  "200 OK - No query parameters",
+			name:  "200 OK - When there are no results",
 			setup: func() {},
 			args: args{
-				query: "",
+				query: "q=no-title",
 			},
 			want: want{
-				articles: []domain.Article{
-					testArticle999,
-					testArticle888,
-				},
+				articles: []domain.Article{},
 				respCode: http.StatusOK,
 				err:      nil,
 			},
 			wantErr: false,
 		},
+		{
+			name:  "400 Bad Request - Missing q, unknown lang",
+			setup: func() {},
+			args: args{
+				query: "lang=klingon",
+			},
+			want: want{
+				articles: nil,
+				respCode: http.StatusBadRequest,
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "lang: must be a valid value; q: cannot be blank.",
+					Errors: []response.FieldError{
+						{Field: "lang", Code: "validation_in_invalid"},
+						{Field: "q", Code: "validation_required"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Cursors aren't supported",
+			setup: func() {},
+			args: args{
+				query: "q=seeded&cursor=abc",
+			},
+			want: want{
+				articles: nil,
+				respCode: http.StatusBadRequest,
+				err:      response.ErrInvalidInput("cursor", "abc"),
+			},
+			wantErr: true,
+		},
 		{
 			name: "500 - DB error",
 			setup: func() {
 				s.createDBError()
 			},
 			args: args{
-				query: "",
+				query: "q=seeded",
 			},
 			want: want{
 				articles: nil,
@@ -682,23 +1243,565 @@ func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles() {
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want.err.ErrorMsg, result.ErrorMsg)
-				assert.Equal(t, tt.want.err.ErrorCode, result.ErrorCode)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
 			} else {
-				var result []domain.Article
+				var result response.Page[domain.ArticleMatch]
 				err := json.Unmarshal(resp.Body.Bytes(), &result)
 
 				assert.NoError(t, err)
-				assert.Equal(t, len(tt.want.articles), len(result))
+				assert.Equal(t, len(tt.want.articles), len(result.Data))
 
-				for i, v := range result {
+				for i, v := range result.Data {
 					wantArticle := tt.want.articles[i]
 
 					assert.Equal(t, wantArticle.UserID, v.UserID)
 					assert.Equal(t, wantArticle.Title, v.Title)
 					assert.Equal(t, wantArticle.Content, v.Content)
+					assert.Contains(t, v.Headline, "<b>")
+					assert.Greater(t, v.Rank, float32(0))
 				}
 			}
 		})
 	}
 }
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles_Ranking() {
+	testdb.SkipUnlessPostgres(s.T(), s.db)
+	article, err := s.factory.Article(context.TODO(), fixtures.WrittenBy(seeded.Users["author"]), func(article *domain.Article) {
+		article.Title = "postgres"
+		article.Content = "full-text search in postgres"
+	})
+	require.NoError(s.T(), err)
+
+	req, err := http.NewRequest("GET", "/api/v1/articles/search?q=seeded+OR+postgres&per_page=1&count=true", nil)
+	require.NoError(s.T(), err)
+
+	resp := executeRequest(req, s.server)
+	assert.Equal(s.T(), http.StatusOK, resp.Code)
+	assert.Equal(s.T(), "3", resp.Header().Get("X-Total-Count"))
+	assert.Equal(s.T(),
+		`</api/v1/articles/search?count=true&page=1&per_page=1&q=seeded+OR+postgres>; rel="first", `+
+			`</api/v1/articles/search?count=true&page=2&per_page=1&q=seeded+OR+postgres>; rel="next", `+
+			`</api/v1/articles/search?count=true&page=3&per_page=1&q=seeded+OR+postgres>; rel="last"`,
+		resp.Header().Get("Link"),
+	)
+
+	var result response.Page[domain.ArticleMatch]
+	err = json.Unmarshal(resp.Body.Bytes(), &result)
+	require.NoError(s.T(), err)
+
+	// The title is weighted over the content, so the new article ranks first.
+	require.Equal(s.T(), 1, len(result.Data))
+	assert.Equal(s.T(), article.ID, result.Data[0].ID)
+	assert.EqualValues(s.T(), 3, *result.Total)
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles_Unsupported() {
+	req, err := http.NewRequest("GET", "/api/v1/articles/search?q=seeded&fuzzy=true", nil)
+	require.NoError(s.T(), err)
+
+	resp := executeRequest(req, s.server)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.Code)
+
+	var result response.Err
+	err = json.Unmarshal(resp.Body.Bytes(), &result)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), response.CodeSearchUnsupported, result.Code)
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleSearchArticles_Bleve() {
+	author := seeded.Users["author"]
+
+	server := api.NewServer(&config.AppConfig{
+		API: &config.APIConfig{
+			JWTSigningKey: jwtSigningKey,
+		},
+		Gin: &config.GinConfig{
+			Mode: gin.TestMode,
+		},
+		Postgres: &config.PostgresConfig{},
+		Search: &config.SearchConfig{
+			Backend:   config.SearchBackendBleve,
+			BlevePath: filepath.Join(s.T().TempDir(), "articles.bleve"),
+		},
+	}, s.db, nil)
+	defer server.Close()
+
+	search := func(query string) response.ArticleSearchPage {
+		req, err := http.NewRequest("GET", "/api/v1/articles/search?"+query, nil)
+		require.NoError(s.T(), err)
+
+		resp := executeRequest(req, server)
+		require.Equal(s.T(), http.StatusOK, resp.Code)
+
+		var result response.ArticleSearchPage
+		err = json.Unmarshal(resp.Body.Bytes(), &result)
+		require.NoError(s.T(), err)
+
+		return result
+	}
+
+	// Seeded articles were never indexed.
+	assert.Empty(s.T(), search("q=seeded").Data)
+
+	body := fmt.Sprintf(`{"user_id": %d, "title": "postgres", "content": "full-text search in postgres"}`, author.ID)
+	req, err := http.NewRequest("POST", "/api/v1/articles", strings.NewReader(body))
+	require.NoError(s.T(), err)
+	authorize(s.T(), req, author)
+	resp := executeRequest(req, server)
+	require.Equal(s.T(), http.StatusCreated, resp.Code)
+
+	var created domain.Article
+	err = json.Unmarshal(resp.Body.Bytes(), &created)
+	require.NoError(s.T(), err)
+
+	result := search("q=postgers&fuzzy=true&facets=true&count=true")
+	require.Equal(s.T(), 1, len(result.Data))
+	assert.Equal(s.T(), created.ID, result.Data[0].ID)
+	assert.Equal(s.T(), "full-text search in <b>postgres</b>", result.Data[0].Headline)
+	assert.EqualValues(s.T(), 1, *result.Total)
+	assert.Equal(s.T(), []domain.FacetCount{{Value: fmt.Sprint(author.ID), Count: 1}}, result.Facets.Authors)
+
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/articles/%v", created.ID), nil)
+	require.NoError(s.T(), err)
+	authorize(s.T(), req, author)
+	resp = executeRequest(req, server)
+	require.Equal(s.T(), http.StatusNoContent, resp.Code)
+
+	assert.Empty(s.T(), search("q=postgres").Data)
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleService_RebuildSearchIndex() {
+	index, err := repository.OpenBleveSearchIndex(filepath.Join(s.T().TempDir(), "articles.bleve"))
+	require.NoError(s.T(), err)
+	defer index.Close()
+
+	articleRepo := repository.NewArticleRepository(dao.NewArticleDAO(s.db))
+	svc := service.NewArticleService(articleRepo, index, dao.NewTxManager(s.db, dao.TxConfig{}))
+
+	indexed, err := svc.RebuildSearchIndex(context.TODO())
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, indexed)
+
+	result, err := svc.SearchArticles(context.TODO(), domain.ArticleSearch{
+		Query:    "seeded",
+		Language: domain.DefaultSearchLanguage,
+		Page:     1,
+		PerPage:  10,
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, len(result.Matches))
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleUpdateArticle() {
+	alpha, beta := seeded.Articles["alpha"], seeded.Articles["beta"]
+
+	type args struct {
+		articleID string
+		ifMatch   string
+		reqBody   request.UpdateArticleRequest
+	}
+	type want struct {
+		article  domain.Article
+		etag     string
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "200 OK - Without If-Match",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				reqBody: request.UpdateArticleRequest{
+					Title:   "new title",
+					Content: "new content",
+				},
+			},
+			want: want{
+				article: domain.Article{
+					UserID:  beta.UserID,
+					Title:   "new title",
+					Content: "new content",
+					Version: 2,
+				},
+				etag:     fmt.Sprintf(`"%d-2"`, beta.ID),
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "200 OK - With matching If-Match",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				ifMatch:   fmt.Sprintf(`"%d-1"`, beta.ID),
+				reqBody: request.UpdateArticleRequest{
+					Title:   "new title",
+					Content: "new content",
+				},
+			},
+			want: want{
+				article: domain.Article{
+					UserID:  beta.UserID,
+					Title:   "new title",
+					Content: "new content",
+					Version: 2,
+				},
+				etag:     fmt.Sprintf(`"%d-2"`, beta.ID),
+				respCode: http.StatusOK,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "412 Precondition Failed - Stale version",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				ifMatch:   fmt.Sprintf(`"%d-5"`, beta.ID),
+				reqBody: request.UpdateArticleRequest{
+					Title:   "new title",
+					Content: "new content",
+				},
+			},
+			want: want{
+				respCode: http.StatusPreconditionFailed,
+				err:      response.ErrPreconditionFailed(service.ErrArticleModified),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "412 Precondition Failed - ETag of another article",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				ifMatch:   fmt.Sprintf(`"%d-1"`, alpha.ID),
+				reqBody: request.UpdateArticleRequest{
+					Title:   "new title",
+					Content: "new content",
+				},
+			},
+			want: want{
+				respCode: http.StatusPreconditionFailed,
+				err:      response.ErrPreconditionFailed(service.ErrArticleModified),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "404 Not Found - articleID is not found",
+			setup: func() {},
+			args: args{
+				articleID: "999",
+				reqBody: request.UpdateArticleRequest{
+					Title:   "new title",
+					Content: "new content",
+				},
+			},
+			want: want{
+				respCode: http.StatusNotFound,
+				err:      response.ErrNotFound("article", "ID", "999"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Missing title",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				reqBody: request.UpdateArticleRequest{
+					Content: "new content",
+				},
+			},
+			want: want{
+				respCode: http.StatusBadRequest,
+				err: &response.Err{
+					Code:   response.CodeValidationFailed,
+					Detail: "title: cannot be blank.",
+					Errors: []response.FieldError{
+						{Field: "title", Code: "validation_required"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:  "400 Bad Request - Already exists",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				reqBody: request.UpdateArticleRequest{
+					Title:   alpha.Title,
+					Content: "new content",
+				},
+			},
+			want: want{
+				respCode: http.StatusBadRequest,
+				err:      response.ErrBadRequest(service.ErrArticleDuplicated),
+			},
+			wantErr: true,
+		},
+		{
+			name: "500 - DB error",
+			setup: func() {
+				s.createDBError()
+			},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				reqBody: request.UpdateArticleRequest{
+					Title:   "new title",
+					Content: "new content",
+				},
+			},
+			want: want{
+				respCode: http.StatusInternalServerError,
+				err:      response.ErrInternalServerError(testDBErr),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Start from the seeded articles, as updates change their versions.
+			s.SetupTest()
+
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			body, err := json.Marshal(tt.args.reqBody)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("PUT", "/api/v1/articles/"+tt.args.articleID, strings.NewReader(string(body)))
+			require.NoError(t, err)
+			authorize(t, req, seeded.Users["author"])
+			if tt.args.ifMatch != "" {
+				req.Header.Set("If-Match", tt.args.ifMatch)
+			}
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
+			} else {
+				var result domain.Article
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.etag, resp.Header().Get("ETag"))
+				assert.Equal(t, tt.want.article.UserID, result.UserID)
+				assert.Equal(t, tt.want.article.Title, result.Title)
+				assert.Equal(t, tt.want.article.Content, result.Content)
+				assert.Equal(t, tt.want.article.Version, result.Version)
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_HandleDeleteArticle() {
+	beta := seeded.Articles["beta"]
+
+	type args struct {
+		articleID string
+		ifMatch   string
+	}
+	type want struct {
+		respCode int
+		err      *response.Err
+	}
+	tests := []struct {
+		name    string
+		setup   func()
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "204 No Content",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				ifMatch:   fmt.Sprintf(`"%d-1"`, beta.ID),
+			},
+			want: want{
+				respCode: http.StatusNoContent,
+				err:      nil,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "412 Precondition Failed - Stale version",
+			setup: func() {},
+			args: args{
+				articleID: fmt.Sprint(beta.ID),
+				ifMatch:   fmt.Sprintf(`"%d-5"`, beta.ID),
+			},
+			want: want{
+				respCode: http.StatusPreconditionFailed,
+				err:      response.ErrPreconditionFailed(service.ErrArticleModified),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "404 Not Found - articleID is not found",
+			setup: func() {},
+			args: args{
+				articleID: "999",
+			},
+			want: want{
+				respCode: http.StatusNotFound,
+				err:      response.ErrNotFound("article", "ID", "999"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// Start from the seeded articles, as deletions remove them.
+			s.SetupTest()
+
+			// Setup tests when present.
+			tt.setup()
+
+			// Prepare Request.
+			req, err := http.NewRequest("DELETE", "/api/v1/articles/"+tt.args.articleID, nil)
+			require.NoError(t, err)
+			authorize(t, req, seeded.Users["author"])
+			if tt.args.ifMatch != "" {
+				req.Header.Set("If-Match", tt.args.ifMatch)
+			}
+
+			// Execute Request.
+			resp := executeRequest(req, s.server)
+
+			// Check the response code.
+			assert.Equal(t, tt.want.respCode, resp.Code)
+
+			if tt.wantErr {
+				var result response.Err
+				err := json.Unmarshal(resp.Body.Bytes(), &result)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.err.Detail, result.Detail)
+				assert.Equal(t, tt.want.err.Code, result.Code)
+				assertFieldErrors(t, tt.want.err.Errors, result.Errors)
+			} else {
+				assert.Empty(t, resp.Body.String())
+			}
+		})
+	}
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_Authorship() {
+	author, beta := seeded.Users["author"], seeded.Articles["beta"]
+
+	other, err := s.factory.User(context.TODO())
+	require.NoError(s.T(), err)
+	admin, err := s.factory.User(context.TODO(), func(user *domain.User) {
+		user.IsAdmin = true
+	})
+	require.NoError(s.T(), err)
+
+	send := func(user domain.User, method, path, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, path, strings.NewReader(body))
+		require.NoError(s.T(), err)
+		authorize(s.T(), req, user)
+
+		return executeRequest(req, s.server)
+	}
+	path := fmt.Sprintf("/api/v1/articles/%d", beta.ID)
+	update := `{"title": "updated title", "content": "updated content"}`
+
+	// Other users can't change the articles of the author, nor write articles in their name.
+	for _, resp := range []*httptest.ResponseRecorder{
+		send(other, "PUT", path, update),
+		send(other, "DELETE", path, ""),
+		send(other, "POST", "/api/v1/articles", fmt.Sprintf(`{"user_id": %d, "title": "title", "content": "content"}`, author.ID)),
+	} {
+		require.Equal(s.T(), http.StatusForbidden, resp.Code)
+
+		var result response.Err
+		err = json.Unmarshal(resp.Body.Bytes(), &result)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), response.CodePermissionDenied, result.Code)
+	}
+
+	// Admins can change any article.
+	resp := send(admin, "PUT", path, update)
+	assert.Equal(s.T(), http.StatusOK, resp.Code)
+	resp = send(admin, "DELETE", path, "")
+	assert.Equal(s.T(), http.StatusNoContent, resp.Code)
+}
+
+func (s *ArticleHandlerTestSuite) TestArticleHandler_ConditionalGet() {
+	beta := seeded.Articles["beta"]
+	path := fmt.Sprintf("/api/v1/articles/%d", beta.ID)
+
+	for _, path := range []string{path, "/api/v1/articles"} {
+		s.T().Run(path, func(t *testing.T) {
+			req, err := http.NewRequest("GET", path, nil)
+			require.NoError(t, err)
+
+			resp := executeRequest(req, s.server)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			etag := resp.Header().Get("ETag")
+			require.NotEmpty(t, etag)
+
+			// The cached copy is still current.
+			req.Header.Set("If-None-Match", etag)
+			resp = executeRequest(req, s.server)
+			assert.Equal(t, http.StatusNotModified, resp.Code)
+			assert.Empty(t, resp.Body.String())
+
+			// The cached copy is outdated.
+			req.Header.Set("If-None-Match", `"outdated"`)
+			resp = executeRequest(req, s.server)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, etag, resp.Header().Get("ETag"))
+		})
+	}
+
+	req, err := http.NewRequest("GET", path, nil)
+	require.NoError(s.T(), err)
+
+	resp := executeRequest(req, s.server)
+	assert.Equal(s.T(), fmt.Sprintf(`"%d-1"`, beta.ID), resp.Header().Get("ETag"))
+
+	// Embedding the author changes the tag, which is still accepted by writes.
+	req, err = http.NewRequest("GET", path+"?include=author", nil)
+	require.NoError(s.T(), err)
+
+	resp = executeRequest(req, s.server)
+	etag := resp.Header().Get("ETag")
+	assert.NotEqual(s.T(), fmt.Sprintf(`"%d-1"`, beta.ID), etag)
+	assert.True(s.T(), strings.HasPrefix(etag, fmt.Sprintf(`"%d-1-`, beta.ID)), etag)
+
+	req, err = http.NewRequest("PUT", path, strings.NewReader(`{"title": "updated title", "content": "updated content"}`))
+	require.NoError(s.T(), err)
+	req.Header.Set("If-Match", etag)
+	authorize(s.T(), req, seeded.Users["author"])
+
+	resp = executeRequest(req, s.server)
+	assert.Equal(s.T(), http.StatusOK, resp.Code)
+	assert.Equal(s.T(), fmt.Sprintf(`"%d-2"`, beta.ID), resp.Header().Get("ETag"))
+}
@@ -19,7 +19,7 @@ func TestHandleHealthcheck(t *testing.T) {
 			Mode: gin.TestMode,
 		},
 		Postgres: &config.PostgresConfig{},
-	}, nil)
+	}, nil, nil)
 
 	// Create a New Request.
 	req, err := http.NewRequest("GET", "/", nil)
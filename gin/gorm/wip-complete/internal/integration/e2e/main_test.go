@@ -0,0 +1,31 @@
+package e2e
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/fixtures"
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/testdb"
+)
+
+var (
+	// template is migrated and seeded in the preferred test database,
+	// tests of full-text search are skipped unless it's PostgreSQL.
+	template *testdb.Template
+
+	// seeded are the users and articles every test starts with.
+	seeded fixtures.Set
+)
+
+func TestMain(m *testing.M) {
+	template = testdb.NewTemplate(fixtures.MigrateAndSeed(&seeded))
+
+	code := m.Run()
+
+	if err := template.Close(); err != nil {
+		log.Printf("failed to close the test database: %v", err)
+	}
+
+	os.Exit(code)
+}
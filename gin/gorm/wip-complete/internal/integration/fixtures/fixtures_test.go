@@ -0,0 +1,127 @@
+package fixtures
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/testdb"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+)
+
+// template is a migrated test database without any rows.
+var template *testdb.Template
+
+func TestMain(m *testing.M) {
+	template = testdb.NewTemplate(testdb.Migrate)
+
+	code := m.Run()
+
+	if err := template.Close(); err != nil {
+		log.Printf("failed to close the test database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestFactory_User(t *testing.T) {
+	db := template.Clone(t)
+	factory := NewFactory(db)
+
+	first, err := factory.User(context.TODO())
+	require.NoError(t, err)
+	second, err := factory.User(context.TODO(), func(user *domain.User) {
+		user.IsAdmin = true
+	})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first.Email, second.Email)
+	assert.NotEqual(t, first.Password, second.Password)
+	assert.False(t, first.IsAdmin)
+	assert.True(t, second.IsAdmin)
+
+	// Only the hash of the plaintext password is stored.
+	stored, err := dao.NewUserDAO(db).FindByID(context.TODO(), second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, second.Email, stored.Email)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.Password), []byte(second.Password)))
+
+	_, err = factory.User(context.TODO(), func(user *domain.User) {
+		user.Email = first.Email
+	})
+	assert.ErrorIs(t, err, dao.ErrUserEmailExists)
+}
+
+func TestFactory_Article(t *testing.T) {
+	factory := NewFactory(template.Clone(t))
+
+	// A new user writes the article unless told otherwise.
+	article, err := factory.Article(context.TODO())
+	require.NoError(t, err)
+	require.NotNil(t, article.Author)
+	assert.NotEmpty(t, article.ID)
+	assert.Equal(t, article.Author.ID, article.UserID)
+	assert.EqualValues(t, 1, article.Version)
+
+	createdAt := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	other, err := factory.Article(context.TODO(), WrittenBy(domain.User{ID: article.Author.ID}), func(article *domain.Article) {
+		article.Title = "overridden"
+		article.CreatedAt = createdAt
+	})
+	require.NoError(t, err)
+	assert.Equal(t, article.UserID, other.UserID)
+	assert.Equal(t, "overridden", other.Title)
+	assert.NotEqual(t, article.Content, other.Content)
+	assert.True(t, createdAt.Equal(other.CreatedAt))
+
+	_, err = factory.Article(context.TODO(), func(article *domain.Article) {
+		article.UserID = 12345
+	})
+	assert.ErrorIs(t, err, dao.ErrUserNotFound)
+}
+
+func TestFactory_Load(t *testing.T) {
+	factory := NewFactory(template.Clone(t))
+
+	set, err := factory.Load(context.TODO(), "testdata/set.yml")
+	require.NoError(t, err)
+
+	require.Len(t, set.Users, 2)
+	admin := set.Users["admin"]
+	assert.Equal(t, "admin@test.com", admin.Email)
+	assert.Equal(t, "Admin123!", admin.Password)
+	assert.True(t, admin.IsAdmin)
+	assert.NotEmpty(t, set.Users["reader"].Email)
+	assert.NotEmpty(t, set.Users["reader"].Password)
+
+	require.Len(t, set.Articles, 2)
+	assert.Equal(t, "announcement", set.Articles["announcement"].Title)
+	assert.Equal(t, admin.ID, set.Articles["announcement"].UserID)
+	assert.Equal(t, admin.ID, set.Articles["draft"].UserID)
+	assert.Less(t, set.Articles["announcement"].ID, set.Articles["draft"].ID)
+
+	_, err = factory.Load(context.TODO(), "testdata/unknown_author.yml")
+	assert.ErrorContains(t, err, `author "editor" of article announcement isn't a user of the set`)
+
+	_, err = factory.Load(context.TODO(), "testdata/unknown_field.yml")
+	assert.ErrorContains(t, err, "field pasword not found")
+}
+
+func TestSeed(t *testing.T) {
+	set, err := Seed(context.TODO(), template.Clone(t))
+	require.NoError(t, err)
+
+	author := set.Users["author"]
+	assert.Equal(t, "author@test.com", author.Email)
+	assert.Equal(t, author.ID, set.Articles["alpha"].UserID)
+	assert.Equal(t, author.ID, set.Articles["beta"].UserID)
+	assert.Less(t, set.Articles["alpha"].ID, set.Articles["beta"].ID)
+	assert.Equal(t, set.Articles["alpha"].CreatedAt, set.Articles["beta"].CreatedAt)
+}
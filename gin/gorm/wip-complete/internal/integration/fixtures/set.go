@@ -0,0 +1,169 @@
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/integration/testdb"
+)
+
+// seedFile is the set every test database starts with.
+//
+//go:embed seed.yml
+var seedFile []byte
+
+// Set is the users and articles created from a fixture file, by their names.
+type Set struct {
+	Users    map[string]domain.User
+	Articles map[string]domain.Article
+}
+
+type file struct {
+	Users    []user    `yaml:"users"`
+	Articles []article `yaml:"articles"`
+}
+
+// user is a user to create, the fields that are left out are generated like by Factory.User.
+type user struct {
+	Name      string    `yaml:"name"`
+	Email     string    `yaml:"email"`
+	Password  string    `yaml:"password"`
+	IsAdmin   bool      `yaml:"is_admin"`
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+// article is an article to create, written by the user of the set whose name is Author.
+// The fields that are left out are generated like by Factory.Article.
+type article struct {
+	Name      string    `yaml:"name"`
+	Author    string    `yaml:"author"`
+	Title     string    `yaml:"title"`
+	Content   string    `yaml:"content"`
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+// Load creates the users then the articles of the YAML file at path, in order.
+// Unknown fields are rejected, so that typos don't go unnoticed.
+func (f *Factory) Load(ctx context.Context, path string) (Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Set{}, fmt.Errorf("os.ReadFile -> %w", err)
+	}
+
+	set, err := f.load(ctx, data)
+	if err != nil {
+		return Set{}, fmt.Errorf("failed to load %v -> %w", path, err)
+	}
+
+	return set, nil
+}
+
+// Seed creates the users and articles of seed.yml, which are what the suites expect to find.
+func Seed(ctx context.Context, db *gorm.DB) (Set, error) {
+	set, err := NewFactory(db).load(ctx, seedFile)
+	if err != nil {
+		return Set{}, fmt.Errorf("failed to load seed.yml -> %w", err)
+	}
+
+	return set, nil
+}
+
+// MigrateAndSeed prepares templates with testdb.Migrate and Seed, keeping what's seeded in set.
+func MigrateAndSeed(set *Set) func(*gorm.DB) error {
+	return func(db *gorm.DB) error {
+		if err := testdb.Migrate(db); err != nil {
+			return err
+		}
+
+		seeded, err := Seed(context.Background(), db)
+		*set = seeded
+
+		return err
+	}
+}
+
+func (f *Factory) load(ctx context.Context, data []byte) (Set, error) {
+	var fixtures file
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixtures); err != nil {
+		return Set{}, fmt.Errorf("decoder.Decode -> %w", err)
+	}
+
+	set := Set{
+		Users:    make(map[string]domain.User, len(fixtures.Users)),
+		Articles: make(map[string]domain.Article, len(fixtures.Articles)),
+	}
+	for _, u := range fixtures.Users {
+		if err := checkName(u.Name, set.Users); err != nil {
+			return Set{}, fmt.Errorf("invalid user -> %w", err)
+		}
+
+		created, err := f.User(ctx, func(user *domain.User) {
+			user.IsAdmin = u.IsAdmin
+			user.CreatedAt = u.CreatedAt
+			user.UpdatedAt = u.CreatedAt
+			if u.Email != "" {
+				user.Email = u.Email
+			}
+			if u.Password != "" {
+				user.Password = u.Password
+			}
+		})
+		if err != nil {
+			return Set{}, fmt.Errorf("failed to create user %v -> %w", u.Name, err)
+		}
+
+		set.Users[u.Name] = created
+	}
+
+	for _, a := range fixtures.Articles {
+		if err := checkName(a.Name, set.Articles); err != nil {
+			return Set{}, fmt.Errorf("invalid article -> %w", err)
+		}
+
+		author, ok := set.Users[a.Author]
+		if !ok {
+			return Set{}, fmt.Errorf("author %q of article %v isn't a user of the set", a.Author, a.Name)
+		}
+
+		created, err := f.Article(ctx, WrittenBy(author), func(article *domain.Article) {
+			article.CreatedAt = a.CreatedAt
+			article.UpdatedAt = a.CreatedAt
+			if a.Title != "" {
+				article.Title = a.Title
+			}
+			if a.Content != "" {
+				article.Content = a.Content
+			}
+		})
+		if err != nil {
+			return Set{}, fmt.Errorf("failed to create article %v -> %w", a.Name, err)
+		}
+
+		set.Articles[a.Name] = created
+	}
+
+	return set, nil
+}
+
+// checkName checks that fixtures have names, which are unique among the ones of their kind.
+func checkName[T any](name string, named map[string]T) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if _, ok := named[name]; ok {
+		return fmt.Errorf("name %v is already taken", name)
+	}
+
+	return nil
+}
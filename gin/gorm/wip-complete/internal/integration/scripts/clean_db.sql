@@ -17,3 +17,13 @@ DO $$
             EXECUTE 'DELETE FROM public.users';
         END IF;
     END$$;
+
+DO $$
+    BEGIN
+        -- Check if the table exists
+        IF EXISTS (SELECT FROM pg_catalog.pg_tables
+                   WHERE schemaname = 'public' AND tablename  = 'idempotency_records') THEN
+            -- If the table exists, delete all rows from it
+            EXECUTE 'DELETE FROM public.idempotency_records';
+        END IF;
+    END$$;
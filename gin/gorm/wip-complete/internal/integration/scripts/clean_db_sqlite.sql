@@ -0,0 +1,3 @@
+DELETE FROM articles;
+DELETE FROM users;
+DELETE FROM idempotency_records;
@@ -0,0 +1,177 @@
+package testdb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/pkg/dockertester"
+)
+
+// dsnBackend creates a database in the PostgreSQL server of EnvDSN for each test,
+// so that tests running at the same time don't see each other's rows.
+type dsnBackend struct{}
+
+func (dsnBackend) Name() string    { return "dsn" }
+func (dsnBackend) Dialect() string { return "postgres" }
+
+func (dsnBackend) Open(string) (*gorm.DB, func() error, error) {
+	dsn := os.Getenv(EnvDSN)
+	if dsn == "" {
+		return nil, nil, fmt.Errorf("%v isn't set", EnvDSN)
+	}
+
+	server, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("gorm.Open -> %w", err)
+	}
+
+	name, err := createDatabase(server, "")
+	if err != nil {
+		return nil, nil, errors.Join(err, closeDB(server))
+	}
+
+	db, err := connect(server, name)
+	if err != nil {
+		return nil, nil, errors.Join(err, dropDatabase(server, name), closeDB(server))
+	}
+
+	return db, func() error {
+		return errors.Join(closeDB(db), dropDatabase(server, name), closeDB(server))
+	}, nil
+}
+
+// dockerBackend starts a PostgreSQL container for each test.
+type dockerBackend struct{}
+
+func (dockerBackend) Name() string    { return "docker" }
+func (dockerBackend) Dialect() string { return "postgres" }
+
+func (dockerBackend) Open(string) (*gorm.DB, func() error, error) {
+	dt, err := dockertester.InitPostgres()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := dockertester.OpenPostgres(dt.Resource, dt.HostPort)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("dockertester.OpenPostgres -> %w", err), dt.Pool.Purge(dt.Resource))
+	}
+
+	return db, func() error {
+		return errors.Join(closeDB(db), dt.Pool.Purge(dt.Resource))
+	}, nil
+}
+
+// embeddedBackend runs a PostgreSQL binary for each test, which is downloaded once and cached in
+// ~/.embedded-postgres-go. PostgreSQL refuses to run as root, so it isn't available then.
+type embeddedBackend struct{}
+
+func (embeddedBackend) Name() string    { return "embedded" }
+func (embeddedBackend) Dialect() string { return "postgres" }
+
+func (embeddedBackend) Open(dir string) (*gorm.DB, func() error, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conf := embeddedpostgres.DefaultConfig().
+		Port(port).
+		RuntimePath(filepath.Join(dir, "postgres")).
+		Logger(io.Discard)
+	server := embeddedpostgres.NewDatabase(conf)
+	if err = server.Start(); err != nil {
+		return nil, nil, fmt.Errorf("server.Start -> %w", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(conf.GetConnectionURL()+"?sslmode=disable"), &gorm.Config{})
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("gorm.Open -> %w", err), server.Stop())
+	}
+
+	return db, func() error {
+		return errors.Join(closeDB(db), server.Stop())
+	}, nil
+}
+
+// createDatabase creates a database with a random name in the server of db, copied from template unless it's empty.
+func createDatabase(db *gorm.DB, template string) (string, error) {
+	name, err := randomName()
+	if err != nil {
+		return "", err
+	}
+
+	stmt := "CREATE DATABASE " + name
+	if template != "" {
+		stmt += " TEMPLATE " + template
+	}
+	if err = db.Exec(stmt).Error; err != nil {
+		return "", fmt.Errorf("failed to create database -> %w", err)
+	}
+
+	return name, nil
+}
+
+// dropDatabase drops a database of the server of db, even when it's still connected to.
+func dropDatabase(db *gorm.DB, name string) error {
+	if err := db.Exec("DROP DATABASE IF EXISTS " + name + " WITH (FORCE)").Error; err != nil {
+		return fmt.Errorf("failed to drop database -> %w", err)
+	}
+
+	return nil
+}
+
+// connect connects to another database of the server of db, with the same settings.
+func connect(db *gorm.DB, name string) (*gorm.DB, error) {
+	dialector, ok := db.Dialector.(*postgres.Dialector)
+	if !ok {
+		return nil, fmt.Errorf("%v isn't a postgres database", db.Dialector.Name())
+	}
+
+	conf, err := pgx.ParseConfig(dialector.Config.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("pgx.ParseConfig -> %w", err)
+	}
+	conf.Database = name
+
+	// The DSN is kept to connect to more databases, but conf is what's connected to.
+	other, err := gorm.Open(postgres.New(postgres.Config{DSN: dialector.Config.DSN, Conn: stdlib.OpenDB(*conf)}), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("gorm.Open -> %w", err)
+	}
+
+	return other, nil
+}
+
+// freePort returns a port that nothing listens on.
+func freePort() (uint32, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, fmt.Errorf("net.Listen -> %w", err)
+	}
+	defer listener.Close()
+
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
+
+// randomName returns a random database name.
+func randomName() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand.Read -> %w", err)
+	}
+
+	return "test_" + hex.EncodeToString(b), nil
+}
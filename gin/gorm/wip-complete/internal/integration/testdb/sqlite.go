@@ -0,0 +1,26 @@
+package testdb
+
+import (
+	"path/filepath"
+
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/config"
+	"github.com/yizeng/gab/gin/wip-complete/internal/db"
+)
+
+// sqliteBackend opens a database in a file of the test, with the same settings as the application.
+// It's always available, but only supports what the migrations and DAOs do on every driver.
+type sqliteBackend struct{}
+
+func (sqliteBackend) Name() string    { return "sqlite" }
+func (sqliteBackend) Dialect() string { return "sqlite" }
+
+func (sqliteBackend) Open(dir string) (*gorm.DB, func() error, error) {
+	sqliteDB, err := db.OpenSQLite(&config.SQLiteConfig{Path: filepath.Join(dir, "test.db")}, "test")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sqliteDB, func() error { return closeDB(sqliteDB) }, nil
+}
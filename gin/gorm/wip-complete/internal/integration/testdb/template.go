@@ -0,0 +1,205 @@
+package testdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/config"
+	"github.com/yizeng/gab/gin/wip-complete/internal/db"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+)
+
+// Template is a prepared database, e.g. migrated and seeded, which each test clones to get a database of its own.
+// So tests don't see each other's rows and can run in parallel. It's created once per package in TestMain:
+//
+//	var template *testdb.Template
+//
+//	func TestMain(m *testing.M) {
+//		template = testdb.NewTemplate(testdb.Migrate)
+//		code := m.Run()
+//		template.Close()
+//		os.Exit(code)
+//	}
+type Template struct {
+	backend Backend
+	server  *gorm.DB
+	close   func() error
+
+	// name is the template database with PostgreSQL, SQLite copies the database of the server.
+	name string
+
+	// unavailable is why no backend is available, tests cloning the template are skipped then.
+	unavailable string
+
+	// err is why the template couldn't be prepared, tests cloning the template fail then.
+	err error
+}
+
+// NewTemplate prepares a template in a database of the first available of Backends, see Open for dialects.
+func NewTemplate(prepare func(*gorm.DB) error, dialects ...string) *Template {
+	backends, err := Backends()
+	if err != nil {
+		return &Template{err: err}
+	}
+
+	return NewTemplateWith(prepare, filter(backends, dialects)...)
+}
+
+// NewTemplateWith prepares a template in a database of the first available of backends, see NewTemplate.
+func NewTemplateWith(prepare func(*gorm.DB) error, backends ...Backend) *Template {
+	dir, err := os.MkdirTemp("", "testdb")
+	if err != nil {
+		return &Template{err: fmt.Errorf("os.MkdirTemp -> %w", err)}
+	}
+
+	var errs []error
+	for _, backend := range backends {
+		server, closeServer, err := backend.Open(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", backend.Name(), err))
+
+			continue
+		}
+
+		t := &Template{
+			backend: backend,
+			server:  server,
+			close: func() error {
+				return errors.Join(closeServer(), os.RemoveAll(dir))
+			},
+		}
+		if err = t.prepare(prepare); err != nil {
+			t.err = fmt.Errorf("failed to prepare the %v template -> %w", backend.Name(), err)
+		}
+
+		return t
+	}
+
+	return &Template{
+		unavailable: unavailable(backends, errs),
+		close: func() error {
+			return os.RemoveAll(dir)
+		},
+	}
+}
+
+func (t *Template) prepare(prepare func(*gorm.DB) error) error {
+	if t.server.Dialector.Name() != "postgres" {
+		return prepare(t.server)
+	}
+
+	// PostgreSQL can't copy databases that are connected to, like the one of the server.
+	name, err := createDatabase(t.server, "")
+	if err != nil {
+		return err
+	}
+	t.name = name
+
+	template, err := connect(t.server, name)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(prepare(template), closeDB(template))
+}
+
+// Clone returns a copy of the template, which is removed after tb.
+// tb is skipped when no backend is available for the template, and fails when it couldn't be prepared.
+func (t *Template) Clone(tb testing.TB) *gorm.DB {
+	tb.Helper()
+
+	if t.unavailable != "" {
+		tb.Skip(t.unavailable)
+	}
+	if t.err != nil {
+		tb.Fatal(t.err)
+	}
+
+	clone, closeClone, err := t.clone(tb.TempDir())
+	if err != nil {
+		tb.Fatalf("failed to clone the %v template: %v", t.backend.Name(), err)
+	}
+
+	tb.Cleanup(func() {
+		if err := closeClone(); err != nil {
+			tb.Errorf("failed to close the %v test database: %v", t.backend.Name(), err)
+		}
+	})
+
+	return clone
+}
+
+func (t *Template) clone(dir string) (*gorm.DB, func() error, error) {
+	if t.server.Dialector.Name() != "postgres" {
+		path := filepath.Join(dir, "test.db")
+		if err := t.server.Exec("VACUUM INTO ?", path).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to copy database -> %w", err)
+		}
+
+		clone, err := db.OpenSQLite(&config.SQLiteConfig{Path: path}, "test")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return clone, func() error { return closeDB(clone) }, nil
+	}
+
+	name, err := createDatabase(t.server, t.name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clone, err := connect(t.server, name)
+	if err != nil {
+		return nil, nil, errors.Join(err, dropDatabase(t.server, name))
+	}
+
+	return clone, func() error {
+		return errors.Join(closeDB(clone), dropDatabase(t.server, name))
+	}, nil
+}
+
+// Close removes the template along with the database of its backend.
+func (t *Template) Close() error {
+	var err error
+	if t.name != "" {
+		err = dropDatabase(t.server, t.name)
+	}
+	if t.close != nil {
+		err = errors.Join(err, t.close())
+	}
+
+	return err
+}
+
+// Migrate applies all the migrations to db, it prepares templates of empty databases.
+func Migrate(db *gorm.DB) error {
+	migrator, err := dao.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("dao.NewMigrator -> %w", err)
+	}
+
+	if _, err = migrator.Up(context.Background()); err != nil {
+		return fmt.Errorf("migrator.Up -> %w", err)
+	}
+
+	return nil
+}
+
+// filter returns the backends of dialects, or all of them when there are none.
+func filter(backends []Backend, dialects []string) []Backend {
+	if len(dialects) == 0 {
+		return backends
+	}
+
+	return slices.DeleteFunc(backends, func(backend Backend) bool {
+		return !slices.Contains(dialects, backend.Dialect())
+	})
+}
@@ -0,0 +1,140 @@
+// Package testdb provides databases to integration tests from whichever backend is available:
+// a running PostgreSQL server, a PostgreSQL container, an embedded PostgreSQL binary or an SQLite file.
+package testdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// EnvBackend forces the backend of Open and NewTemplate, by name.
+	EnvBackend = "TEST_DATABASE_BACKEND"
+
+	// EnvDSN is the connection string of the PostgreSQL server of the dsn backend.
+	EnvDSN = "TEST_DATABASE_DSN"
+)
+
+// Backend opens test databases.
+type Backend interface {
+	// Name is how the backend is chosen with EnvBackend.
+	Name() string
+
+	// Dialect is the GORM dialector name of the databases, e.g. postgres.
+	Dialect() string
+
+	// Open opens a new database, which may keep its files in dir, along with a func closing and removing it.
+	// It fails when the backend isn't available.
+	Open(dir string) (*gorm.DB, func() error, error)
+}
+
+var (
+	DSN      Backend = dsnBackend{}
+	Docker   Backend = dockerBackend{}
+	Embedded Backend = embeddedBackend{}
+	SQLite   Backend = sqliteBackend{}
+)
+
+// Backends returns the backend forced by EnvBackend, or all of them from the most to the least preferred.
+// SQLite comes last, as it doesn't support everything PostgreSQL does.
+func Backends() ([]Backend, error) {
+	all := []Backend{DSN, Docker, Embedded, SQLite}
+
+	name := os.Getenv(EnvBackend)
+	if name == "" {
+		return all, nil
+	}
+
+	for _, backend := range all {
+		if backend.Name() == name {
+			return []Backend{backend}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown %v %q, it must be dsn, docker, embedded or sqlite", EnvBackend, name)
+}
+
+// Open opens a database of the first available of Backends, which is closed after tb.
+// Only the backends of dialects are tried when some are given, e.g. for tests of PostgreSQL features.
+// tb is skipped when no backend is available.
+func Open(tb testing.TB, dialects ...string) *gorm.DB {
+	tb.Helper()
+
+	backends, err := Backends()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return OpenWith(tb, filter(backends, dialects)...)
+}
+
+// OpenWith opens a database of the first available of backends, see Open.
+func OpenWith(tb testing.TB, backends ...Backend) *gorm.DB {
+	tb.Helper()
+
+	var errs []error
+	for _, backend := range backends {
+		db, closeDB, err := backend.Open(tb.TempDir())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", backend.Name(), err))
+
+			continue
+		}
+
+		tb.Cleanup(func() {
+			if err := closeDB(); err != nil {
+				tb.Errorf("failed to close the %v test database: %v", backend.Name(), err)
+			}
+		})
+		tb.Logf("using the %v test database", backend.Name())
+
+		return db
+	}
+
+	tb.Skip(unavailable(backends, errs))
+
+	return nil
+}
+
+// CleanScript is the path of the script deleting all the rows of db, relative to the packages of the suites.
+func CleanScript(db *gorm.DB) string {
+	if name := db.Dialector.Name(); name != "postgres" {
+		return "../scripts/clean_db_" + name + ".sql"
+	}
+
+	return "../scripts/clean_db.sql"
+}
+
+// unavailable tells why none of backends is available.
+func unavailable(backends []Backend, errs []error) string {
+	names := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		names = append(names, backend.Name())
+	}
+
+	return fmt.Sprintf("no test database is available (tried %v), set %v or start Docker to run this test:\n%v",
+		strings.Join(names, ", "), EnvDSN, errors.Join(errs...))
+}
+
+// SkipUnlessPostgres skips tests of what only PostgreSQL supports, when db is another database.
+func SkipUnlessPostgres(tb testing.TB, db *gorm.DB) {
+	tb.Helper()
+
+	if name := db.Dialector.Name(); name != "postgres" {
+		tb.Skipf("only supported by PostgreSQL, the test database is %v", name)
+	}
+}
+
+// closeDB closes the connections of db.
+func closeDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
@@ -0,0 +1,72 @@
+package testdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+)
+
+func TestBackends(t *testing.T) {
+	t.Setenv(EnvBackend, "")
+	backends, err := Backends()
+	require.NoError(t, err)
+	assert.Equal(t, []Backend{DSN, Docker, Embedded, SQLite}, backends)
+
+	t.Setenv(EnvBackend, "sqlite")
+	backends, err = Backends()
+	require.NoError(t, err)
+	assert.Equal(t, []Backend{SQLite}, backends)
+
+	t.Setenv(EnvBackend, "oracle")
+	_, err = Backends()
+	assert.Error(t, err)
+}
+
+func TestOpen_SQLite(t *testing.T) {
+	t.Setenv(EnvBackend, "sqlite")
+
+	db := Open(t)
+	assert.Equal(t, "sqlite", db.Dialector.Name())
+	assert.Equal(t, "../scripts/clean_db_sqlite.sql", CleanScript(db))
+}
+
+func TestTemplate_SQLite(t *testing.T) {
+	template := NewTemplateWith(func(db *gorm.DB) error {
+		if err := Migrate(db); err != nil {
+			return err
+		}
+
+		_, err := dao.NewUserDAO(db).Insert(context.Background(), dao.User{Email: "user@test.com", Password: "hash"})
+
+		return err
+	}, SQLite)
+	defer func() {
+		assert.NoError(t, template.Close())
+	}()
+
+	// Clones have the rows of the template, and don't see each other's changes.
+	clone := template.Clone(t)
+	require.NoError(t, clone.Exec("DELETE FROM users").Error)
+
+	var count int64
+	require.NoError(t, template.Clone(t).Model(&dao.User{}).Count(&count).Error)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestTemplate_Unavailable(t *testing.T) {
+	t.Setenv(EnvDSN, "")
+
+	template := NewTemplateWith(Migrate, DSN)
+	defer func() {
+		assert.NoError(t, template.Close())
+	}()
+
+	// Tests cloning it are skipped with the reason.
+	assert.Contains(t, template.unavailable, "dsn: TEST_DATABASE_DSN isn't set")
+	assert.NoError(t, template.err)
+}
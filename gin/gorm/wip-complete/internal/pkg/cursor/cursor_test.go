@@ -0,0 +1,76 @@
+package cursor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type position struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"id"`
+}
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec("test_key")
+	want := position{CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC), ID: 999}
+
+	token, err := codec.Encode(want)
+	require.NoError(t, err)
+
+	var got position
+	err = codec.Decode(token, &got)
+	require.NoError(t, err)
+	assert.True(t, want.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, want.ID, got.ID)
+
+	payload, sig, _ := strings.Cut(token, ".")
+	forged, err := NewCodec("test_key").Encode(position{ID: 1})
+	require.NoError(t, err)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "Empty", token: ""},
+		{name: "Missing signature", token: payload},
+		{name: "Not base64", token: "!!!." + sig},
+		{name: "Tampered payload", token: forgedPayload + "." + sig},
+		{name: "Other key", token: mustEncode(t, NewCodec("other_key"), want)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got position
+			err := codec.Decode(tt.token, &got)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	key := DeriveKey("test_key")
+	assert.Len(t, key, 32)
+	assert.NotEqual(t, "test_key", key)
+
+	// The same secret always derives the same key, so cursors outlive restarts.
+	assert.Equal(t, key, DeriveKey("test_key"))
+	assert.NotEqual(t, key, DeriveKey("other_key"))
+
+	// Cursors signed with the secret itself aren't accepted.
+	var got position
+	err := NewCodec(key).Decode(mustEncode(t, NewCodec("test_key"), position{ID: 1}), &got)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func mustEncode(t *testing.T, codec *Codec, v any) string {
+	t.Helper()
+
+	token, err := codec.Encode(v)
+	require.NoError(t, err)
+
+	return token
+}
@@ -0,0 +1,133 @@
+// Package fieldset parses the sparse fieldsets and included relations of responses, e.g.
+// ?fields=id,title&include=author, and trims JSON objects down to them.
+// Only the fields and relations whitelisted by a Schema are accepted.
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	FieldsQueryKey  = "fields"
+	IncludeQueryKey = "include"
+)
+
+var (
+	errUnknownField    = validation.NewError("query_unknown_field", "unknown field {{.field}}")
+	errUnknownRelation = validation.NewError("query_unknown_relation", "unknown relation {{.relation}}")
+)
+
+// Schema whitelists the fields and relations of a resource by their names in its JSON representation.
+type Schema struct {
+	Fields    []string
+	Relations []string // embedded under their names when included
+}
+
+// Spec is which fields of a resource are returned and which relations are embedded.
+type Spec struct {
+	Fields  []string // in the order of the query, all fields are returned when empty
+	Include []string
+}
+
+// Parse reads the fields and include queries of values, other queries are ignored.
+// Invalid queries are reported as validation.Errors keyed by the query name.
+func (s Schema) Parse(values url.Values) (Spec, error) {
+	var spec Spec
+	errs := validation.Errors{}
+
+	if raw := values.Get(FieldsQueryKey); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			if !slices.Contains(s.Fields, name) {
+				errs[FieldsQueryKey] = errUnknownField.SetParams(map[string]any{"field": name})
+
+				break
+			}
+			if !slices.Contains(spec.Fields, name) {
+				spec.Fields = append(spec.Fields, name)
+			}
+		}
+	}
+
+	if raw := values.Get(IncludeQueryKey); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			if !slices.Contains(s.Relations, name) {
+				errs[IncludeQueryKey] = errUnknownRelation.SetParams(map[string]any{"relation": name})
+
+				break
+			}
+			if !slices.Contains(spec.Include, name) {
+				spec.Include = append(spec.Include, name)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return Spec{}, errs
+	}
+
+	return spec, nil
+}
+
+// Includes tells whether relation is embedded.
+func (s Spec) Includes(relation string) bool {
+	return slices.Contains(s.Include, relation)
+}
+
+// Select returns the JSON representation of v, an object, with only the fields of s and its included relations.
+func (s Spec) Select(v any) (json.RawMessage, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal -> %w", err)
+	}
+	if len(s.Fields) == 0 {
+		return body, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err = json.Unmarshal(body, &object); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal -> %w", err)
+	}
+
+	// Fields are written in the order of the query, which a map wouldn't keep.
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, name := range append(slices.Clone(s.Fields), s.Include...) {
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// SelectAll is Select for each of items.
+func SelectAll[T any](s Spec, items []T) ([]json.RawMessage, error) {
+	selected := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		object, err := s.Select(item)
+		if err != nil {
+			return nil, err
+		}
+
+		selected = append(selected, object)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,148 @@
+// Package i18n translates messages with catalogues of templates per language
+// and negotiates the language from an Accept-Language header, see https://www.rfc-editor.org/rfc/rfc9110#section-12.5.4.
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// Translator looks messages up in the catalogue of a language.
+// Messages missing from a catalogue fall back to the parent language, e.g. de-CH to de,
+// and then to the caller's own message in the source language.
+type Translator struct {
+	source     language.Tag
+	supported  []language.Tag
+	matcher    language.Matcher
+	catalogues map[language.Tag]map[string]*template.Template
+}
+
+// New loads a catalogue from every YAML file of fsys, the file name being the language, e.g. de.yaml or pt-BR.yaml.
+// Catalogues hold templates of text/template, nested keys are joined with dots, e.g. titles.not_found.
+// The source is the language of the messages in the code, it doesn't need a catalogue.
+func New(fsys fs.FS, source language.Tag) (*Translator, error) {
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("fs.Glob -> %w", err)
+	}
+
+	t := &Translator{
+		source:     source,
+		supported:  []language.Tag{source},
+		catalogues: make(map[language.Tag]map[string]*template.Template, len(files)),
+	}
+
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("language.Parse -> %w", err)
+		}
+
+		catalogue, err := loadCatalogue(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("loadCatalogue(%v) -> %w", file, err)
+		}
+
+		t.catalogues[tag] = catalogue
+		if tag != source {
+			t.supported = append(t.supported, tag)
+		}
+	}
+
+	// The matcher prefers the first of equally good languages, so de-AT matches de rather than de-CH.
+	others := t.supported[1:]
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].String() < others[j].String()
+	})
+	t.matcher = language.NewMatcher(t.supported)
+
+	return t, nil
+}
+
+func loadCatalogue(fsys fs.FS, file string) (map[string]*template.Template, error) {
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("fs.ReadFile -> %w", err)
+	}
+
+	var entries map[string]any
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal -> %w", err)
+	}
+
+	messages := make(map[string]string)
+	flatten("", entries, messages)
+
+	catalogue := make(map[string]*template.Template, len(messages))
+	for key, msg := range messages {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(msg)
+		if err != nil {
+			return nil, fmt.Errorf("template.Parse -> %w", err)
+		}
+
+		catalogue[key] = tmpl
+	}
+
+	return catalogue, nil
+}
+
+func flatten(prefix string, entries map[string]any, messages map[string]string) {
+	for key, value := range entries {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(key, v, messages)
+		case string:
+			messages[key] = v
+		}
+	}
+}
+
+// Match returns the supported language that best matches an Accept-Language header,
+// or the source language if none of them is acceptable.
+func (t *Translator) Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return t.source
+	}
+
+	_, i, confidence := t.matcher.Match(tags...)
+	if confidence == language.No {
+		return t.source
+	}
+
+	return t.supported[i]
+}
+
+// Translate renders the message of key in lang with params.
+// It returns false if no catalogue in the fallback chain of lang has the message,
+// then the caller should use its message in the source language.
+func (t *Translator) Translate(lang language.Tag, key string, params map[string]any) (string, bool) {
+	for tag := lang; tag != language.Und && tag != t.source; tag = tag.Parent() {
+		tmpl, ok := t.catalogues[tag][key]
+		if !ok {
+			continue
+		}
+
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, params); err != nil {
+			// A param is missing, the message in the source language is better than a broken one.
+			return "", false
+		}
+
+		return b.String(), true
+	}
+
+	return "", false
+}
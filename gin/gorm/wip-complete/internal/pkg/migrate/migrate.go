@@ -0,0 +1,391 @@
+// Package migrate applies versioned SQL migrations to PostgreSQL, MySQL or SQLite.
+//
+// A migration is a pair of files named like "000001_create_users.up.sql" and "000001_create_users.down.sql".
+// Applied versions are recorded in the schema_migrations table, each migration runs in its own transaction,
+// and a session-level lock keeps concurrent migrators, like replicas starting together, from racing.
+// SQLite needs no lock as it's only used by a single process.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockKey identifies the advisory lock held while migrating, it's arbitrary but shared by all migrators.
+const lockKey = "7351206439280131"
+
+// dialect is the SQL of a database the migrator runs on top of the migrations.
+type dialect struct {
+	createTable string
+	hasTable    string
+	insert      string
+	delete      string
+
+	// lock and unlock are empty when the database doesn't need a lock.
+	lock   string
+	unlock string
+}
+
+// dialects are keyed by the names of the GORM dialectors.
+var dialects = map[string]dialect{
+	"postgres": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`,
+		hasTable: "SELECT to_regclass('schema_migrations') IS NOT NULL",
+		insert:   "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		delete:   "DELETE FROM schema_migrations WHERE version = $1",
+		lock:     "SELECT pg_advisory_lock(" + lockKey + ")",
+		unlock:   "SELECT pg_advisory_unlock(" + lockKey + ")",
+	},
+	"mysql": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+		hasTable: "SELECT count(*) > 0 FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'",
+		insert:   "INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		delete:   "DELETE FROM schema_migrations WHERE version = ?",
+		lock:     "SELECT GET_LOCK('schema_migrations_" + lockKey + "', -1)",
+		unlock:   "SELECT RELEASE_LOCK('schema_migrations_" + lockKey + "')",
+	},
+	"sqlite": {
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version integer PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+		hasTable: "SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+		insert:   "INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		delete:   "DELETE FROM schema_migrations WHERE version = ?",
+	},
+}
+
+var (
+	ErrInvalidName = errors.New("migration name must only contain letters, digits and underscores")
+	ErrNoDown      = errors.New("migration has no down script")
+	ErrNoDialect   = errors.New("migrations aren't supported on this database")
+
+	fileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	nameRegex = regexp.MustCompile(`^\w+$`)
+)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+
+	hasUp, hasDown bool
+}
+
+// Status is a migration and when it was applied, AppliedAt is zero when it's pending.
+type Status struct {
+	Migration
+
+	AppliedAt time.Time
+}
+
+// Pending reports whether s hasn't been applied yet.
+func (s Status) Pending() bool {
+	return s.AppliedAt.IsZero()
+}
+
+// Load reads the migrations at the root of fsys, sorted by version.
+// Files that don't look like migrations are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("fs.ReadDir -> %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		matches := fileRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration %v -> %w", entry.Name(), err)
+		}
+
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("fs.ReadFile -> %w", err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		}
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("migrations %v and %v have the same version", m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up, m.hasUp = string(script), true
+		} else {
+			m.Down, m.hasDown = string(script), true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if !m.hasUp {
+			return nil, fmt.Errorf("migration %06d_%v has no up script", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create writes empty up and down scripts of a new migration into dir,
+// versioned after the latest one, and returns their paths.
+func Create(dir, name string) (string, string, error) {
+	if !nameRegex.MatchString(name) {
+		return "", "", ErrInvalidName
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", fmt.Errorf("migrate.Load -> %w", err)
+	}
+
+	var version uint64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	prefix := filepath.Join(dir, fmt.Sprintf("%06d_%v", version, strings.ToLower(name)))
+	up, down := prefix+".up.sql", prefix+".down.sql"
+	for _, path := range []string{up, down} {
+		if err = os.WriteFile(path, nil, 0o644); err != nil {
+			return "", "", fmt.Errorf("os.WriteFile -> %w", err)
+		}
+	}
+
+	return up, down, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// New creates a migrator of the migrations of fsys, for a database of the dialect named
+// like the GORM dialectors: postgres, mysql or sqlite.
+func New(db *sql.DB, fsys fs.FS, dialectName string) (*Migrator, error) {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNoDialect, dialectName)
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("migrate.Load -> %w", err)
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    d,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies all the pending migrations in order, and returns them.
+// It stops at the first migration that fails, the ones before it stay applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range statuses {
+			if !s.Pending() {
+				continue
+			}
+
+			err = inTx(ctx, conn, s.Up, m.dialect.insert, s.Version, s.Name)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %06d_%v -> %w", s.Version, s.Name, err)
+			}
+
+			applied = append(applied, s.Migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the latest steps applied migrations in reverse order, and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(statuses) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			s := statuses[i]
+			if s.Pending() {
+				continue
+			}
+			if !s.hasDown {
+				return fmt.Errorf("failed to roll back migration %06d_%v -> %w", s.Version, s.Name, ErrNoDown)
+			}
+
+			err = inTx(ctx, conn, s.Down, m.dialect.delete, s.Version)
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %06d_%v -> %w", s.Version, s.Name, err)
+			}
+
+			rolledBack = append(rolledBack, s.Migration)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status returns all the migrations, applied or pending.
+// It only reads, so it neither waits for the migration lock nor creates the schema_migrations table,
+// all the migrations are pending when there isn't one yet.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("db.Conn -> %w", err)
+	}
+	defer conn.Close()
+
+	var hasTable bool
+	if err = conn.QueryRowContext(ctx, m.dialect.hasTable).Scan(&hasTable); err != nil {
+		return nil, fmt.Errorf("failed to look up schema_migrations table -> %w", err)
+	}
+	if !hasTable {
+		return m.statuses(nil), nil
+	}
+
+	return m.status(ctx, conn)
+}
+
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) ([]Status, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("conn.QueryContext -> %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[uint64]time.Time)
+	for rows.Next() {
+		var (
+			version int64
+			at      time.Time
+		)
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("rows.Scan -> %w", err)
+		}
+
+		appliedAt[uint64(version)] = at
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err -> %w", err)
+	}
+
+	return m.statuses(appliedAt), nil
+}
+
+// statuses pairs the migrations with when they were applied, by version.
+func (m *Migrator) statuses(appliedAt map[uint64]time.Time) []Status {
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, Status{
+			Migration: migration,
+			AppliedAt: appliedAt[migration.Version],
+		})
+	}
+
+	return statuses
+}
+
+// withLock runs fn on a connection holding the migration lock, once the schema_migrations table exists.
+// The lock belongs to the session, so everything has to run on the same connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Conn -> %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		if _, err = conn.ExecContext(ctx, m.dialect.lock); err != nil {
+			return fmt.Errorf("failed to acquire migration lock -> %w", err)
+		}
+		defer func() {
+			// The lock must be released even if ctx is canceled, as the connection goes back to the pool.
+			_, _ = conn.ExecContext(context.WithoutCancel(ctx), m.dialect.unlock)
+		}()
+	}
+
+	if _, err = conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table -> %w", err)
+	}
+
+	return fn(conn)
+}
+
+// inTx runs script and records it with the record statement, all in a transaction.
+// The script is run without arguments, so it can contain several statements.
+// MySQL commits statements that change the schema right away though, so its migrations aren't atomic.
+func inTx(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("conn.BeginTx -> %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("tx.ExecContext -> %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("tx.ExecContext -> %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("tx.Commit -> %w", err)
+	}
+
+	return nil
+}
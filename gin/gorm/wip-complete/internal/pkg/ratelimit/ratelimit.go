@@ -0,0 +1,124 @@
+// Package ratelimit implements the Generic Cell Rate Algorithm (GCRA),
+// a token-bucket equivalent that only needs to store one timestamp per key.
+// See https://brandur.org/rate-limiting for a good introduction.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidLimit = errors.New(`limit must look like "10/1m"`)
+
+// Limit allows Rate requests per Period, with bursts of up to Burst requests.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// ParseLimit parses limits like "10/1m" or "100/1h".
+// An empty string returns a zero Limit, which means unlimited.
+func ParseLimit(s string) (Limit, error) {
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	rawRate, rawPeriod, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, errInvalidLimit
+	}
+
+	rate, err := strconv.Atoi(rawRate)
+	if err != nil || rate <= 0 {
+		return Limit{}, errInvalidLimit
+	}
+
+	period, err := time.ParseDuration(rawPeriod)
+	if err != nil || period <= 0 {
+		return Limit{}, errInvalidLimit
+	}
+
+	return Limit{
+		Rate:   rate,
+		Period: period,
+		Burst:  rate,
+	}, nil
+}
+
+// IsZero reports whether l is unlimited.
+func (l Limit) IsZero() bool {
+	return l.Rate == 0
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%v", l.Rate, l.Period)
+}
+
+// emissionInterval is the time between two requests when they arrive at a steady rate.
+func (l Limit) emissionInterval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// tolerance is how far ahead of the schedule requests may run, i.e. the burst size.
+func (l Limit) tolerance() time.Duration {
+	burst := l.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return l.emissionInterval() * time.Duration(burst)
+}
+
+type Result struct {
+	Allowed bool
+
+	// Limit is the burst size, i.e. the number of requests allowed at once.
+	Limit int
+
+	// Remaining is how many requests can still be made right now.
+	Remaining int
+
+	// ResetAfter is when the limit will be fully replenished.
+	ResetAfter time.Duration
+
+	// RetryAfter is when the next request will be allowed. Zero if Allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter decides whether the request identified by key is within limit.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra returns the result for a request arriving at now,
+// given the theoretical arrival time (TAT) stored for its key.
+// newTAT is what should be stored if the request is allowed.
+func gcra(now, tat time.Time, limit Limit) (newTAT time.Time, result Result) {
+	if tat.Before(now) {
+		tat = now
+	}
+
+	tolerance := limit.tolerance()
+	interval := limit.emissionInterval()
+	result.Limit = int(tolerance / interval)
+
+	newTAT = tat.Add(interval)
+	allowAt := newTAT.Add(-tolerance)
+	if now.Before(allowAt) {
+		result.ResetAfter = tat.Sub(now)
+		result.RetryAfter = allowAt.Sub(now)
+
+		return tat, result
+	}
+
+	result.Allowed = true
+	result.ResetAfter = newTAT.Sub(now)
+	result.Remaining = int((tolerance - result.ResetAfter) / interval)
+
+	return newTAT, result
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript is the Lua version of gcra, so that reading and updating the TAT
+// is atomic across all the replicas sharing the same Redis.
+// Times are in microseconds and taken from the Redis clock to avoid skew between replicas.
+var gcraScript = redis.NewScript(`
+local key = KEYS[1]
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", key))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - tolerance
+if now < allow_at then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", key, new_tat, "PX", math.ceil(reset_after / 1000))
+
+return {1, math.floor((tolerance - reset_after) / interval), reset_after, 0}
+`)
+
+// RedisLimiter keeps the state in Redis, so limits are shared by all replicas.
+type RedisLimiter struct {
+	client redis.Scripter
+}
+
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{
+		client: client,
+	}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.IsZero() {
+		return Result{Allowed: true}, nil
+	}
+
+	interval := limit.emissionInterval()
+	tolerance := limit.tolerance()
+
+	values, err := gcraScript.Run(ctx, l.client, []string{key}, interval.Microseconds(), tolerance.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("gcraScript.Run -> %w", err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected result from gcraScript: %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      int(tolerance / interval),
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[2]) * time.Microsecond,
+		RetryAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/registry"
+	blevesearch "github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/highlight"
+	htmlformat "github.com/blevesearch/bleve/v2/search/highlight/format/html"
+	simplefragmenter "github.com/blevesearch/bleve/v2/search/highlight/fragmenter/simple"
+	simplehighlighter "github.com/blevesearch/bleve/v2/search/highlight/highlighter/simple"
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+)
+
+const (
+	// bleveHighlighter escapes headlines and marks their matches with <b></b>, like PostgresSearchIndex.
+	bleveHighlighter = "article_headline"
+
+	// bleveOpenTimeout is how long to wait for the lock of an index opened by another process.
+	bleveOpenTimeout = "5s"
+
+	titleBoost        = 2.0 // titles weigh more than contents
+	headlineMaxWords  = 20  // length of headlines of articles matching by title only
+	authorFacetSize   = 10
+	monthFacetSize    = 120
+	monthFacetLayout  = "2006-01"
+	clearBatchSize    = 1000
+	fragmentDelimiter = " ... "
+)
+
+func init() {
+	registry.RegisterHighlighter(bleveHighlighter, func(config map[string]any, cache *registry.Cache) (highlight.Highlighter, error) {
+		fragmenter, err := cache.FragmenterNamed(simplefragmenter.Name)
+		if err != nil {
+			return nil, fmt.Errorf("cache.FragmenterNamed -> %w", err)
+		}
+
+		formatter := htmlformat.NewFragmentFormatter("<b>", "</b>")
+
+		return simplehighlighter.NewHighlighter(fragmenter, formatter, fragmentDelimiter), nil
+	})
+}
+
+// BleveSearchIndex searches articles with an embedded Bleve index stored on disk, independently of the database.
+// It supports fuzzy matching and facets, but only in domain.DefaultSearchLanguage.
+type BleveSearchIndex struct {
+	index bleve.Index
+}
+
+// OpenBleveSearchIndex opens the index at path, it's created when it doesn't exist yet.
+// An index can only be opened by one process at a time.
+func OpenBleveSearchIndex(path string) (*BleveSearchIndex, error) {
+	index, err := bleve.OpenUsing(path, map[string]any{"bolt_timeout": bleveOpenTimeout})
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, newArticleMapping())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BleveSearchIndex{
+		index: index,
+	}, nil
+}
+
+func (i *BleveSearchIndex) Close() error {
+	return i.index.Close()
+}
+
+func (i *BleveSearchIndex) Search(ctx context.Context, search domain.ArticleSearch) (domain.ArticleSearchResult, error) {
+	if search.Language != domain.DefaultSearchLanguage {
+		return domain.ArticleSearchResult{}, fmt.Errorf("%w: the bleve backend only searches in %v", ErrSearchUnsupported, domain.DefaultSearchLanguage)
+	}
+
+	// page number is starting from 1.
+	offset := (search.Page - 1) * search.PerPage
+	req := bleve.NewSearchRequestOptions(i.buildQuery(search.Query, search.Fuzzy), int(search.PerPage), int(offset), false)
+	req.Fields = []string{"*"}
+	req.SortBy([]string{"-_score", "_id"})
+	req.Highlight = bleve.NewHighlightWithStyle(bleveHighlighter)
+	req.Highlight.AddField("content")
+	if search.Facets {
+		req.AddFacet("authors", bleve.NewFacetRequest("user_id", authorFacetSize))
+		req.AddFacet("months", bleve.NewFacetRequest("month", monthFacetSize))
+	}
+
+	found, err := i.index.SearchInContext(ctx, req)
+	if err != nil {
+		return domain.ArticleSearchResult{}, fmt.Errorf("i.index.SearchInContext -> %w", err)
+	}
+
+	result := domain.ArticleSearchResult{
+		Matches: make([]domain.ArticleMatch, 0, len(found.Hits)),
+	}
+	for _, hit := range found.Hits {
+		article, err := documentToDomain(hit.ID, hit.Fields)
+		if err != nil {
+			return domain.ArticleSearchResult{}, fmt.Errorf("documentToDomain -> %w", err)
+		}
+
+		headline := strings.Join(hit.Fragments["content"], fragmentDelimiter)
+		if headline == "" {
+			// Unlike the fragments, the content isn't escaped by the highlighter.
+			headline = html.EscapeString(firstWords(article.Content, headlineMaxWords))
+		}
+
+		result.Matches = append(result.Matches, domain.ArticleMatch{
+			Article:  article,
+			Rank:     float32(hit.Score),
+			Headline: headline,
+		})
+	}
+	if search.Count {
+		total := int64(found.Total)
+		result.Total = &total
+	}
+	if search.Facets {
+		result.Facets = &domain.ArticleFacets{
+			Authors: facetCounts(found.Facets["authors"]),
+			Months:  facetCounts(found.Facets["months"]),
+		}
+		slices.SortFunc(result.Facets.Months, func(a, b domain.FacetCount) int {
+			return strings.Compare(a.Value, b.Value)
+		})
+	}
+
+	return result, nil
+}
+
+func (i *BleveSearchIndex) Index(ctx context.Context, articles ...domain.Article) error {
+	batch := i.index.NewBatch()
+	for _, article := range articles {
+		if err := batch.Index(strconv.FormatUint(uint64(article.ID), 10), domainToDocument(article)); err != nil {
+			return fmt.Errorf("batch.Index -> %w", err)
+		}
+	}
+
+	if err := i.index.Batch(batch); err != nil {
+		return fmt.Errorf("i.index.Batch -> %w", err)
+	}
+
+	return nil
+}
+
+func (i *BleveSearchIndex) Remove(ctx context.Context, id uint) error {
+	if err := i.index.Delete(strconv.FormatUint(uint64(id), 10)); err != nil {
+		return fmt.Errorf("i.index.Delete -> %w", err)
+	}
+
+	return nil
+}
+
+func (i *BleveSearchIndex) Clear(ctx context.Context) error {
+	for {
+		req := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), clearBatchSize, 0, false)
+		found, err := i.index.SearchInContext(ctx, req)
+		if err != nil {
+			return fmt.Errorf("i.index.SearchInContext -> %w", err)
+		}
+		if len(found.Hits) == 0 {
+			return nil
+		}
+
+		batch := i.index.NewBatch()
+		for _, hit := range found.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err = i.index.Batch(batch); err != nil {
+			return fmt.Errorf("i.index.Batch -> %w", err)
+		}
+	}
+}
+
+// buildQuery translates query from the syntax of websearch_to_tsquery, see parseWebSearch.
+// Words stemmed away entirely, like stop words, are ignored.
+func (i *BleveSearchIndex) buildQuery(rawQuery string, fuzzy bool) query.Query {
+	var (
+		must    [][]query.Query // alternatives of each required term
+		mustNot []query.Query
+	)
+	for _, term := range parseWebSearch(rawQuery) {
+		if i.isStopWord(term.text) {
+			continue
+		}
+
+		q := termQuery(term, fuzzy)
+		switch {
+		case term.negated:
+			mustNot = append(mustNot, q)
+		case term.or && len(must) > 0:
+			must[len(must)-1] = append(must[len(must)-1], q)
+		default:
+			must = append(must, []query.Query{q})
+		}
+	}
+
+	if len(must) == 0 && len(mustNot) == 0 {
+		return bleve.NewMatchNoneQuery()
+	}
+
+	result := bleve.NewBooleanQuery()
+	for _, alternatives := range must {
+		if len(alternatives) == 1 {
+			result.AddMust(alternatives[0])
+		} else {
+			result.AddMust(bleve.NewDisjunctionQuery(alternatives...))
+		}
+	}
+	if len(must) == 0 {
+		result.AddMust(bleve.NewMatchAllQuery())
+	}
+	result.AddMustNot(mustNot...)
+
+	return result
+}
+
+func (i *BleveSearchIndex) isStopWord(text string) bool {
+	return len(i.index.Mapping().AnalyzerNamed(en.AnalyzerName).Analyze([]byte(text))) == 0
+}
+
+// termQuery matches a term in either the title or the content.
+func termQuery(term webSearchTerm, fuzzy bool) query.Query {
+	fields := make([]query.Query, 0, 2)
+	for _, field := range []string{"title", "content"} {
+		var q interface {
+			query.FieldableQuery
+			query.BoostableQuery
+		}
+		if term.phrase {
+			q = bleve.NewMatchPhraseQuery(term.text)
+		} else {
+			match := bleve.NewMatchQuery(term.text)
+			match.SetOperator(query.MatchQueryOperatorAnd)
+			if fuzzy {
+				match.SetFuzziness(1)
+			}
+			q = match
+		}
+
+		q.SetField(field)
+		if field == "title" {
+			q.SetBoost(titleBoost)
+		}
+
+		fields = append(fields, q)
+	}
+
+	return bleve.NewDisjunctionQuery(fields...)
+}
+
+// webSearchTerm is a term of a query in the syntax of websearch_to_tsquery.
+type webSearchTerm struct {
+	text    string
+	phrase  bool // whether the term was quoted
+	negated bool // whether the term was preceded by -
+	or      bool // whether the term was preceded by OR, making it an alternative to the previous one
+}
+
+// parseWebSearch splits a query like `postgres "full text" OR fulltext -mysql` into terms.
+// Unquoted words are required, "quoted phrases" match words in order, -terms are excluded,
+// and OR makes the terms around it alternatives.
+func parseWebSearch(rawQuery string) []webSearchTerm {
+	var (
+		terms []webSearchTerm
+		or    bool
+	)
+	for rest := strings.TrimSpace(rawQuery); rest != ""; rest = strings.TrimSpace(rest) {
+		term := webSearchTerm{or: or}
+		or = false
+
+		if strings.HasPrefix(rest, "-") {
+			term.negated = true
+			rest = rest[1:]
+		}
+
+		if text, ok := strings.CutPrefix(rest, `"`); ok {
+			term.phrase = true
+			term.text, rest, _ = strings.Cut(text, `"`)
+		} else {
+			end := strings.IndexAny(rest, " \t\n\"")
+			if end < 0 {
+				end = len(rest)
+			}
+			term.text, rest = rest[:end], rest[end:]
+		}
+
+		if !term.phrase && !term.negated && term.text == "OR" {
+			or = len(terms) > 0
+
+			continue
+		}
+		if strings.TrimSpace(term.text) != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// newArticleMapping indexes titles and contents in English, authors and months of creation are kept as is for facets.
+// The other fields are only stored, so articles can be returned without querying the database.
+func newArticleMapping() mapping.IndexMapping {
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = en.AnalyzerName
+
+	keyword := bleve.NewKeywordFieldMapping()
+	keyword.IncludeInAll = false
+
+	stored := bleve.NewTextFieldMapping()
+	stored.Index = false
+	stored.IncludeInAll = false
+	stored.IncludeTermVectors = false
+
+	article := bleve.NewDocumentStaticMapping()
+	article.AddFieldMappingsAt("title", text)
+	article.AddFieldMappingsAt("content", text)
+	article.AddFieldMappingsAt("user_id", keyword)
+	article.AddFieldMappingsAt("month", keyword)
+	article.AddFieldMappingsAt("version", stored)
+	article.AddFieldMappingsAt("created_at", stored)
+	article.AddFieldMappingsAt("updated_at", stored)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = article
+	indexMapping.DefaultAnalyzer = en.AnalyzerName
+
+	return indexMapping
+}
+
+func domainToDocument(a domain.Article) map[string]any {
+	return map[string]any{
+		"title":      a.Title,
+		"content":    a.Content,
+		"user_id":    strconv.FormatUint(uint64(a.UserID), 10),
+		"month":      a.CreatedAt.UTC().Format(monthFacetLayout),
+		"version":    strconv.FormatUint(uint64(a.Version), 10),
+		"created_at": a.CreatedAt.UTC().Format(time.RFC3339Nano),
+		"updated_at": a.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func documentToDomain(id string, fields map[string]any) (domain.Article, error) {
+	str := func(name string) string {
+		value, _ := fields[name].(string)
+
+		return value
+	}
+
+	articleID, err := strconv.ParseUint(id, 10, 0)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("invalid ID %q -> %w", id, err)
+	}
+	userID, err := strconv.ParseUint(str("user_id"), 10, 0)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("invalid user_id of %v -> %w", id, err)
+	}
+	version, err := strconv.ParseUint(str("version"), 10, 0)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("invalid version of %v -> %w", id, err)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, str("created_at"))
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("invalid created_at of %v -> %w", id, err)
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, str("updated_at"))
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("invalid updated_at of %v -> %w", id, err)
+	}
+
+	return domain.Article{
+		ID:        uint(articleID),
+		UserID:    uint(userID),
+		Title:     str("title"),
+		Content:   str("content"),
+		Version:   uint(version),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func facetCounts(facet *blevesearch.FacetResult) []domain.FacetCount {
+	counts := []domain.FacetCount{}
+	if facet == nil || facet.Terms == nil {
+		return counts
+	}
+
+	for _, term := range facet.Terms.Terms() {
+		counts = append(counts, domain.FacetCount{Value: term.Term, Count: term.Count})
+	}
+
+	return counts
+}
+
+func firstWords(text string, n int) string {
+	words := strings.Fields(text)
+	if len(words) > n {
+		words = words[:n]
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+)
+
+var testArticles = []domain.Article{
+	{
+		ID: 1, UserID: 123, Version: 1,
+		Title:     "Full-text search in Postgres",
+		Content:   "Postgres ranks documents with tsvector columns and GIN indexes.",
+		CreatedAt: time.Date(2024, 1, 31, 15, 26, 31, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+	},
+	{
+		ID: 2, UserID: 123, Version: 3,
+		Title:     "Embedded indexes",
+		Content:   "Bleve is a full-text search library, it keeps its index next to the application.",
+		CreatedAt: time.Date(2024, 2, 10, 8, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 2, 10, 8, 0, 0, 0, time.UTC),
+	},
+	{
+		ID: 3, UserID: 456, Version: 1,
+		Title:     "Caching",
+		Content:   "Redis keeps hot articles in memory.",
+		CreatedAt: time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC),
+	},
+}
+
+func openTestIndex(t *testing.T) *BleveSearchIndex {
+	t.Helper()
+
+	index, err := OpenBleveSearchIndex(filepath.Join(t.TempDir(), "articles.bleve"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = index.Close() })
+
+	require.NoError(t, index.Index(context.TODO(), testArticles...))
+
+	return index
+}
+
+func searchIDs(t *testing.T, index *BleveSearchIndex, search domain.ArticleSearch) []uint {
+	t.Helper()
+
+	search.Language = domain.DefaultSearchLanguage
+	search.Page, search.PerPage = 1, 10
+
+	result, err := index.Search(context.TODO(), search)
+	require.NoError(t, err)
+
+	ids := []uint{}
+	for _, match := range result.Matches {
+		ids = append(ids, match.ID)
+	}
+
+	return ids
+}
+
+func TestBleveSearchIndex_Search(t *testing.T) {
+	index := openTestIndex(t)
+
+	tests := []struct {
+		name   string
+		search domain.ArticleSearch
+		want   []uint
+	}{
+		{name: "Stemmed and case-insensitive", search: domain.ArticleSearch{Query: "INDEX"}, want: []uint{2, 1}},
+		{name: "All words are required", search: domain.ArticleSearch{Query: "search postgres"}, want: []uint{1}},
+		{name: "Quoted phrase", search: domain.ArticleSearch{Query: `"search library"`}, want: []uint{2}},
+		{name: "Excluded word", search: domain.ArticleSearch{Query: "full-text -postgres"}, want: []uint{2}},
+		{name: "OR", search: domain.ArticleSearch{Query: "redis OR bleve"}, want: []uint{2, 3}},
+		{name: "Stop words are ignored", search: domain.ArticleSearch{Query: "the redis"}, want: []uint{3}},
+		{name: "Only stop words", search: domain.ArticleSearch{Query: "the"}, want: []uint{}},
+		{name: "Typo", search: domain.ArticleSearch{Query: "postgers"}, want: []uint{}},
+		{name: "Typo with fuzzy matching", search: domain.ArticleSearch{Query: "postgers", Fuzzy: true}, want: []uint{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchIDs(t, index, tt.search)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestBleveSearchIndex_Search_Result(t *testing.T) {
+	index := openTestIndex(t)
+
+	result, err := index.Search(context.TODO(), domain.ArticleSearch{
+		Query:    "postgres OR bleve",
+		Language: domain.DefaultSearchLanguage,
+		Page:     1,
+		PerPage:  1,
+		Count:    true,
+		Facets:   true,
+	})
+	require.NoError(t, err)
+
+	// Matches in titles weigh more than those in contents.
+	require.Equal(t, 1, len(result.Matches))
+	assert.Equal(t, testArticles[0], result.Matches[0].Article)
+	assert.Greater(t, result.Matches[0].Rank, float32(0))
+	assert.Contains(t, result.Matches[0].Headline, "<b>Postgres</b>")
+	assert.EqualValues(t, 2, *result.Total)
+	assert.Equal(t, &domain.ArticleFacets{
+		Authors: []domain.FacetCount{{Value: "123", Count: 2}},
+		Months:  []domain.FacetCount{{Value: "2024-01", Count: 1}, {Value: "2024-02", Count: 1}},
+	}, result.Facets)
+
+	_, err = index.Search(context.TODO(), domain.ArticleSearch{Query: "postgres", Language: "german", Page: 1, PerPage: 1})
+	assert.ErrorIs(t, err, ErrSearchUnsupported)
+}
+
+func TestBleveSearchIndex_Search_EscapedHeadline(t *testing.T) {
+	index := openTestIndex(t)
+
+	article := domain.Article{ID: 4, UserID: 123, Title: "Unsafe", Content: `<script>alert("xss")</script> & injected markup`}
+	require.NoError(t, index.Index(context.TODO(), article))
+
+	// Matches in contents are highlighted, the rest of the content is escaped.
+	result, err := index.Search(context.TODO(), domain.ArticleSearch{Query: "injected", Language: domain.DefaultSearchLanguage, Page: 1, PerPage: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Matches))
+	assert.Contains(t, result.Matches[0].Headline, "<b>injected</b>")
+	assert.NotContains(t, result.Matches[0].Headline, "<script>")
+
+	// Articles matching by title only get the escaped beginning of their content.
+	result, err = index.Search(context.TODO(), domain.ArticleSearch{Query: "unsafe", Language: domain.DefaultSearchLanguage, Page: 1, PerPage: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Matches))
+	assert.Equal(t, "&lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt; &amp; injected markup", result.Matches[0].Headline)
+}
+
+func TestBleveSearchIndex_Sync(t *testing.T) {
+	index := openTestIndex(t)
+
+	updated := testArticles[2]
+	updated.Content = "Memcached keeps hot articles in memory."
+	require.NoError(t, index.Index(context.TODO(), updated))
+	assert.Equal(t, []uint{}, searchIDs(t, index, domain.ArticleSearch{Query: "redis"}))
+	assert.Equal(t, []uint{3}, searchIDs(t, index, domain.ArticleSearch{Query: "memcached"}))
+
+	require.NoError(t, index.Remove(context.TODO(), 3))
+	assert.Equal(t, []uint{}, searchIDs(t, index, domain.ArticleSearch{Query: "memcached"}))
+
+	require.NoError(t, index.Clear(context.TODO()))
+	assert.Equal(t, []uint{}, searchIDs(t, index, domain.ArticleSearch{Query: "full-text"}))
+}
+
+func TestParseWebSearch(t *testing.T) {
+	got := parseWebSearch(`OR postgres  "full text" OR -"sql" fulltext -mysql "unclosed`)
+
+	assert.Equal(t, []webSearchTerm{
+		{text: "postgres"},
+		{text: "full text", phrase: true},
+		{text: "sql", phrase: true, negated: true, or: true},
+		{text: "fulltext"},
+		{text: "mysql", negated: true},
+		{text: "unclosed", phrase: true},
+	}, got)
+}
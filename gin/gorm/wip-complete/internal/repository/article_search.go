@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/repository/dao"
+)
+
+// ErrSearchUnsupported is returned for searches a search index or the database can't run.
+var ErrSearchUnsupported = dao.ErrSearchUnsupported
+
+// PostgresSearchIndex searches articles with the full-text search of Postgres.
+// Postgres keeps the search vectors of articles up to date, so there is nothing to index.
+// Fuzzy matching and facets aren't supported.
+type PostgresSearchIndex struct {
+	articles *ArticleRepository
+}
+
+func NewPostgresSearchIndex(articles *ArticleRepository) *PostgresSearchIndex {
+	return &PostgresSearchIndex{
+		articles: articles,
+	}
+}
+
+func (i *PostgresSearchIndex) Search(ctx context.Context, search domain.ArticleSearch) (domain.ArticleSearchResult, error) {
+	if search.Fuzzy || search.Facets {
+		return domain.ArticleSearchResult{}, fmt.Errorf("%w: fuzzy matching and facets need the bleve backend", ErrSearchUnsupported)
+	}
+
+	matches, err := i.articles.Search(ctx, search.Query, search.Language, search.Page, search.PerPage)
+	if err != nil {
+		return domain.ArticleSearchResult{}, fmt.Errorf("i.articles.Search -> %w", err)
+	}
+
+	result := domain.ArticleSearchResult{
+		Matches: matches,
+	}
+	if search.Count {
+		total, err := i.articles.CountMatches(ctx, search.Query, search.Language)
+		if err != nil {
+			return domain.ArticleSearchResult{}, fmt.Errorf("i.articles.CountMatches -> %w", err)
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+func (i *PostgresSearchIndex) Index(ctx context.Context, articles ...domain.Article) error {
+	return nil
+}
+
+func (i *PostgresSearchIndex) Remove(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (i *PostgresSearchIndex) Clear(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/pkg/listquery"
+)
+
+// likeEscaper escapes the wildcards of LIKE patterns, so filter values are matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// applyFilters adds the filters of spec to tx as WHERE conditions.
+// columns maps the field names of spec to table columns, fields outside of it are rejected.
+func applyFilters(tx *gorm.DB, spec listquery.Spec, columns map[string]string) (*gorm.DB, error) {
+	for _, filter := range spec.Filters {
+		name, ok := columns[filter.Field]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter field %q", filter.Field)
+		}
+		column := clause.Column{Name: name}
+
+		var expr clause.Expression
+		switch filter.Operator {
+		case listquery.Eq:
+			expr = clause.Eq{Column: column, Value: filter.Value}
+		case listquery.Ne:
+			expr = clause.Neq{Column: column, Value: filter.Value}
+		case listquery.Gt:
+			expr = clause.Gt{Column: column, Value: filter.Value}
+		case listquery.Gte:
+			expr = clause.Gte{Column: column, Value: filter.Value}
+		case listquery.Lt:
+			expr = clause.Lt{Column: column, Value: filter.Value}
+		case listquery.Lte:
+			expr = clause.Lte{Column: column, Value: filter.Value}
+		case listquery.In:
+			values, _ := filter.Value.([]any)
+			expr = clause.IN{Column: column, Values: values}
+		case listquery.Contains:
+			pattern := "%" + likeEscaper.Replace(fmt.Sprint(filter.Value)) + "%"
+			expr = clause.Expr{SQL: containsSQL(tx.Dialector.Name()), Vars: []any{column, pattern}}
+		default:
+			return nil, fmt.Errorf("unknown filter operator %q", filter.Operator)
+		}
+
+		tx = tx.Where(expr)
+	}
+
+	return tx, nil
+}
+
+// containsSQL matches a column to a LIKE pattern regardless of case.
+// MySQL and SQLite already ignore it with LIKE, but SQLite has no default escape character.
+func containsSQL(dialect string) string {
+	switch dialect {
+	case "postgres":
+		return "? ILIKE ?"
+	case "sqlite":
+		return `? LIKE ? ESCAPE '\'`
+	default:
+		return "? LIKE ?"
+	}
+}
+
+// applySorts adds the sorts of spec to tx as ORDER BY columns, see applyFilters for columns.
+func applySorts(tx *gorm.DB, spec listquery.Spec, columns map[string]string) (*gorm.DB, error) {
+	for _, sort := range spec.Sorts {
+		name, ok := columns[sort.Field]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q", sort.Field)
+		}
+
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: name}, Desc: sort.Desc})
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,9 @@
+CREATE TABLE users (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    email varchar(255) NOT NULL,
+    password varchar(255) NOT NULL,
+    is_admin boolean NOT NULL DEFAULT false,
+    created_at datetime(6) NOT NULL,
+    updated_at datetime(6) NOT NULL,
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_records (
+    key text PRIMARY KEY,
+    request_hash text NOT NULL,
+    completed boolean NOT NULL DEFAULT false,
+    status_code bigint,
+    header bytea,
+    body bytea,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_records_expires_at ON idempotency_records (expires_at);
@@ -0,0 +1,8 @@
+CREATE TABLE users (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    email text NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password text NOT NULL,
+    is_admin boolean NOT NULL DEFAULT false,
+    created_at datetime NOT NULL,
+    updated_at datetime NOT NULL
+);
@@ -0,0 +1,13 @@
+CREATE TABLE idempotency_records (
+    key text PRIMARY KEY,
+    request_hash text NOT NULL,
+    completed boolean NOT NULL DEFAULT false,
+    status_code integer,
+    header blob,
+    body blob,
+    expires_at datetime NOT NULL,
+    created_at datetime NOT NULL,
+    updated_at datetime NOT NULL
+);
+
+CREATE INDEX idx_idempotency_records_expires_at ON idempotency_records (expires_at);
@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type TxConfig struct {
+	// Isolation is the isolation level of transactions, sql.LevelDefault uses the one of the database.
+	Isolation sql.IsolationLevel
+
+	// MaxRetries is how many times a transaction is run again after a serialization failure,
+	// which happens with the repeatable read and serializable isolation levels of Postgres, and on deadlocks with MySQL.
+	MaxRetries int
+
+	// RetryBackoff is how long to wait before the first retry, it's doubled before each next one.
+	RetryBackoff time.Duration
+}
+
+// TxManager runs functions in transactions, which the DAOs called with the context given to the functions pick up.
+// So a transaction can span several DAOs without them knowing about each other.
+type TxManager struct {
+	db   *gorm.DB
+	conf TxConfig
+}
+
+func NewTxManager(db *gorm.DB, conf TxConfig) *TxManager {
+	return &TxManager{
+		db:   db,
+		conf: conf,
+	}
+}
+
+// WithinTx runs fn in a transaction, which is committed when fn returns nil and rolled back otherwise.
+// Calls nested in fn are run within savepoints, so their failure only rolls back what they did.
+//
+// The transaction is run again from the start after a serialization failure, up to MaxRetries times,
+// so fn must not have side effects outside the database. Nested calls are never retried on their own.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return m.WithinTxOnce(ctx, fn)
+	}
+
+	backoff := m.conf.RetryBackoff
+	for retries := 0; ; retries++ {
+		err := m.WithinTxOnce(ctx, fn)
+		if err == nil || retries >= m.conf.MaxRetries || !isSerializationFailure(err) {
+			return err
+		}
+
+		zap.L().Warn("retrying transaction after serialization failure", zap.Int("retries", retries+1), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// WithinTxOnce is WithinTx without retries, for functions with side effects that can't be repeated,
+// like streaming a response.
+func (m *TxManager) WithinTxOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	run := func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	}
+
+	// GORM nests transactions started from a transaction with savepoints.
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Transaction(run)
+	}
+
+	return m.db.WithContext(ctx).Transaction(run, &sql.TxOptions{Isolation: m.conf.Isolation})
+}
+
+// conn returns the transaction of ctx if there's one, db otherwise.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
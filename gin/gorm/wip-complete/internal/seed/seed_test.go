@@ -0,0 +1,131 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yizeng/gab/gin/wip-complete/internal/domain"
+	"github.com/yizeng/gab/gin/wip-complete/internal/service"
+)
+
+// fakeServices keeps users and articles in memory, with the same unique constraints as the database.
+type fakeServices struct {
+	users    map[string]domain.User
+	articles map[string]domain.Article // by user ID and title
+}
+
+func newFakeServices() *fakeServices {
+	return &fakeServices{
+		users:    make(map[string]domain.User),
+		articles: make(map[string]domain.Article),
+	}
+}
+
+func (f *fakeServices) Signup(_ context.Context, user domain.User) (domain.User, error) {
+	if _, ok := f.users[user.Email]; ok {
+		return domain.User{}, service.ErrUserEmailExists
+	}
+
+	user.ID = uint(len(f.users) + 1)
+	f.users[user.Email] = user
+
+	return user, nil
+}
+
+func (f *fakeServices) GetUserByEmail(_ context.Context, email string) (domain.User, error) {
+	user, ok := f.users[email]
+	if !ok {
+		return domain.User{}, service.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (f *fakeServices) CreateArticle(_ context.Context, _ domain.Caller, article domain.Article) (domain.Article, error) {
+	key := fmt.Sprintf("%d/%s", article.UserID, article.Title)
+	if _, ok := f.articles[key]; ok {
+		return domain.Article{}, service.ErrArticleDuplicated
+	}
+
+	article.ID = uint(len(f.articles) + 1)
+	f.articles[key] = article
+
+	return article, nil
+}
+
+// fakeTxManager runs functions right away, without a transaction.
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTxManager) WithinTxOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestLoad(t *testing.T) {
+	fixtures, err := Load("testdata/fixtures.yml")
+	require.NoError(t, err)
+
+	assert.Equal(t, []User{
+		{Email: "admin@test.com", Password: "Admin123!", IsAdmin: true},
+		{Email: "user@test.com", Password: "User123!"},
+	}, fixtures.Users)
+	assert.Equal(t, []Article{
+		{Author: "admin@test.com", Title: "title 1", Content: "content 1"},
+		{Author: "user@test.com", Title: "title 2", Content: "content 2"},
+	}, fixtures.Articles)
+
+	// Files are appended to each other.
+	fixtures, err = Load("testdata/fixtures.yml", "testdata/fixtures.yml")
+	require.NoError(t, err)
+	assert.Len(t, fixtures.Users, 4)
+
+	_, err = Load("testdata/unknown_field.yml")
+	assert.ErrorContains(t, err, "field pasword not found")
+
+	_, err = Load("testdata/not_found.yml")
+	assert.Error(t, err)
+}
+
+func TestFixtures_Validate(t *testing.T) {
+	fixtures := Fixtures{
+		Users:    []User{{Email: "not an email", Password: "123"}},
+		Articles: []Article{{Author: "admin", Title: "title"}},
+	}
+
+	assert.EqualError(t, fixtures.Validate(),
+		"Articles: (0: (Author: must be a valid email address; Content: cannot be blank.).); Users: (0: (Email: must be a valid email address.).).")
+}
+
+func TestSeeder_Seed(t *testing.T) {
+	ctx := context.Background()
+	fixtures, err := Load("testdata/fixtures.yml")
+	require.NoError(t, err)
+
+	fakes := newFakeServices()
+	seeder := NewSeeder(fakes, fakes, fakes, fakeTxManager{})
+
+	result, err := seeder.Seed(ctx, fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Users: 2, Articles: 2}, result)
+	assert.True(t, fakes.users["admin@test.com"].IsAdmin)
+
+	// Seeding again skips everything, authors are found by their email.
+	result, err = seeder.Seed(ctx, fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Skipped: 4}, result)
+
+	// Articles of unknown authors can't be seeded, and nothing of a failed seed is counted as it's rolled back.
+	result, err = seeder.Seed(ctx, Fixtures{
+		Users:    []User{{Email: "new@test.com", Password: "password"}},
+		Articles: []Article{{Author: "unknown@test.com", Title: "title 3", Content: "content 3"}},
+	})
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+	assert.Equal(t, Result{}, result)
+}
@@ -14,6 +14,7 @@ var (
 
 type UserRepository interface {
 	FindByID(ctx context.Context, id uint) (domain.User, error)
+	FindByEmail(ctx context.Context, email string) (domain.User, error)
 }
 
 type UserService struct {
@@ -34,3 +35,12 @@ func (s *UserService) GetUser(ctx context.Context, id uint) (domain.User, error)
 
 	return user, nil
 }
+
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	user, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("s.repo.FindByEmail -> %w", err)
+	}
+
+	return user, nil
+}
@@ -1,13 +1,15 @@
 package main
 
 import (
+	"os"
+
 	_ "github.com/joho/godotenv/autoload" // Autoload .env file.
 
 	"github.com/yizeng/gab/gin/wip-complete/cmd/app"
 )
 
 func main() {
-	if err := app.Start(); err != nil {
-		panic(err)
+	if err := app.NewCommand().Execute(); err != nil {
+		os.Exit(1)
 	}
 }
@@ -35,7 +35,7 @@ func NewCountryHandler(svc CountryService) *CountryHandler {
 // @Router       /countries/sum-population-by-state [post]
 func (h *CountryHandler) HandleSumPopulationByState(ctx *gin.Context) {
 	req := request.SumPopulationByState{}
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	if err := ctx.ShouldBindWith(&req, request.JSON); err != nil {
 		response.RenderErr(ctx, response.ErrBadRequest(err))
 
 		return
@@ -0,0 +1,144 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report invalid fields by their JSON names, e.g. states[0].name instead of States[0].Name.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonName)
+	}
+}
+
+// SyntaxError reports malformed JSON with the position where decoding failed.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("malformed JSON at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// TypeError reports a JSON value that can't be decoded into the type of its field.
+type TypeError struct {
+	Field string // dotted path of the field, e.g. states.1.population
+	Type  string // expected JSON type
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("%s: must be a %s", e.Field, e.Type)
+}
+
+// JSON is a gin binding decoding with DecodeJSON, so malformed JSON is reported with its position.
+// Use it with ctx.ShouldBindWith instead of ctx.ShouldBindJSON.
+var JSON binding.BindingBody = jsonBinding{}
+
+type jsonBinding struct{}
+
+func (jsonBinding) Name() string {
+	return "json"
+}
+
+func (jsonBinding) Bind(r *http.Request, obj any) error {
+	return decodeAndValidate(r.Body, obj)
+}
+
+func (jsonBinding) BindBody(body []byte, obj any) error {
+	return decodeAndValidate(bytes.NewReader(body), obj)
+}
+
+func decodeAndValidate(body io.Reader, obj any) error {
+	if err := DecodeJSON(body, obj); err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// DecodeJSON decodes body into v.
+// Malformed JSON is returned as *SyntaxError, and values of the wrong type as *TypeError.
+func DecodeJSON(body io.Reader, v any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("io.ReadAll -> %w", err)
+	}
+
+	err = json.Unmarshal(data, v)
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, column := position(data, syntaxErr.Offset)
+
+		return &SyntaxError{
+			Line:   line,
+			Column: column,
+			Msg:    syntaxErr.Error(),
+		}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &TypeError{
+			Field: typeErr.Field,
+			Type:  jsonType(typeErr),
+		}
+	}
+
+	return err
+}
+
+// position converts the offset of a json.SyntaxError into a 1-based line and column.
+func position(data []byte, offset int64) (line, column int) {
+	// The offset is right after the invalid character, or the end of the input when it's truncated.
+	i := int(offset) - 1
+	if i < 0 || int(offset) >= len(data) {
+		i = int(offset)
+	}
+
+	before := data[:min(i, len(data))]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// jsonType names the JSON type a Go value is decoded from.
+func jsonType(err *json.UnmarshalTypeError) string {
+	switch err.Type.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "number"
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
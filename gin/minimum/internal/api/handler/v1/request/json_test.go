@@ -0,0 +1,71 @@
+package request
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type state struct {
+		Population uint `json:"population"`
+	}
+	type body struct {
+		States []state `json:"states"`
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		wantSyntax *SyntaxError
+		wantType   *TypeError
+	}{
+		{
+			name:  "Valid JSON",
+			input: `{"states":[{"population":1}]}`,
+		},
+		{
+			name:       "Truncated JSON",
+			input:      "[",
+			wantSyntax: &SyntaxError{Line: 1, Column: 2, Msg: "unexpected end of JSON input"},
+		},
+		{
+			name:       "Empty body",
+			input:      "",
+			wantSyntax: &SyntaxError{Line: 1, Column: 1, Msg: "unexpected end of JSON input"},
+		},
+		{
+			name:       "Invalid character on another line",
+			input:      "{\n  \"states\": [],\n  x\n}",
+			wantSyntax: &SyntaxError{Line: 3, Column: 3, Msg: "invalid character 'x' looking for beginning of object key string"},
+		},
+		{
+			name:     "Wrong type",
+			input:    `{"states":[{},{"population":"many"}]}`,
+			wantType: &TypeError{Field: "states.1.population", Type: "number"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v body
+			err := DecodeJSON(strings.NewReader(tt.input), &v)
+
+			switch {
+			case tt.wantSyntax != nil:
+				var syntaxErr *SyntaxError
+				require.ErrorAs(t, err, &syntaxErr)
+				assert.Equal(t, tt.wantSyntax, syntaxErr)
+			case tt.wantType != nil:
+				var typeErr *TypeError
+				require.ErrorAs(t, err, &typeErr)
+				assert.Equal(t, tt.wantType, typeErr)
+				assert.Equal(t, "states.1.population: must be a number", typeErr.Error())
+			default:
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, v.States[0].Population)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/yizeng/gab/gin/minimum/internal/api/handler/v1/request"
 )
 
 type Err struct {
@@ -13,6 +17,10 @@ type Err struct {
 
 	ErrorCode int    `json:"error_code,omitempty"` // application-specific error code
 	ErrorMsg  string `json:"error"`                // user-facing error message
+
+	Errors []FieldError `json:"errors,omitempty"` // invalid fields of the request
+	Line   int          `json:"line,omitempty"`   // line of malformed JSON
+	Column int          `json:"column,omitempty"` // column of malformed JSON
 }
 
 func RenderErr(ctx *gin.Context, e *Err) {
@@ -24,8 +32,49 @@ func RenderErr(ctx *gin.Context, e *Err) {
 }
 
 func ErrBadRequest(err error) *Err {
+	var syntaxErr *request.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return ErrMalformedJSON(syntaxErr)
+	}
+
+	var typeErr *request.TypeError
+	if errors.As(err, &typeErr) {
+		return &Err{
+			statusCode: http.StatusBadRequest,
+			ErrorMsg:   typeErr.Error(),
+			Errors: []FieldError{{
+				Field:   fieldPath(typeErr.Field),
+				Code:    "validation_type_mismatch",
+				Message: "must be a " + typeErr.Type,
+				Params:  map[string]any{"type": typeErr.Type},
+			}},
+		}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return ErrValidation(validationErrs)
+	}
+
+	return &Err{
+		statusCode: http.StatusBadRequest,
+		ErrorMsg:   err.Error(),
+	}
+}
+
+func ErrValidation(errs validator.ValidationErrors) *Err {
+	return &Err{
+		statusCode: http.StatusBadRequest,
+		ErrorMsg:   errs.Error(),
+		Errors:     fieldErrors(errs),
+	}
+}
+
+func ErrMalformedJSON(err *request.SyntaxError) *Err {
 	return &Err{
 		statusCode: http.StatusBadRequest,
 		ErrorMsg:   err.Error(),
+		Line:       err.Line,
+		Column:     err.Column,
 	}
 }
@@ -0,0 +1,70 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yizeng/gab/gin/minimum/internal/api/handler/v1/request"
+)
+
+func TestErrBadRequest_Validation(t *testing.T) {
+	type state struct {
+		Name       string `json:"name" binding:"required,max=3"`
+		Population uint   `json:"population" binding:"required"`
+	}
+	type body struct {
+		Title  string  `json:"title" binding:"required"`
+		States []state `json:"states" binding:"required,dive"`
+	}
+	states := make([]state, 11)
+	states[2] = state{Name: "Texas"}
+	states[10] = state{Population: 1}
+
+	err := binding.Validator.ValidateStruct(&body{States: states})
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, http.StatusBadRequest, got.statusCode)
+	assert.Equal(t, FieldError{Field: "title", Code: "validation_required", Message: "cannot be blank"}, got.Errors[0])
+	assert.Equal(t, []FieldError{
+		{Field: "states[0].name", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[0].population", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[1].name", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[1].population", Code: "validation_required", Message: "cannot be blank"},
+		{Field: "states[2].name", Code: "validation_max", Message: "must satisfy max=3", Params: map[string]any{"max": "3"}},
+		{Field: "states[2].population", Code: "validation_required", Message: "cannot be blank"},
+	}, got.Errors[1:7])
+	assert.Equal(t, FieldError{Field: "states[10].name", Code: "validation_required", Message: "cannot be blank"}, got.Errors[len(got.Errors)-1])
+}
+
+func TestErrBadRequest_TypeMismatch(t *testing.T) {
+	var v struct {
+		States []struct {
+			Population uint `json:"population"`
+		} `json:"states"`
+	}
+	err := request.DecodeJSON(strings.NewReader(`{"states":[{},{"population":"many"}]}`), &v)
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, http.StatusBadRequest, got.statusCode)
+	assert.Equal(t, []FieldError{
+		{Field: "states[1].population", Code: "validation_type_mismatch", Message: "must be a number", Params: map[string]any{"type": "number"}},
+	}, got.Errors)
+}
+
+func TestErrBadRequest_MalformedJSON(t *testing.T) {
+	var v map[string]any
+	err := request.DecodeJSON(strings.NewReader("{\n  x\n}"), &v)
+
+	got := ErrBadRequest(err)
+
+	assert.Equal(t, http.StatusBadRequest, got.statusCode)
+	assert.Equal(t, 2, got.Line)
+	assert.Equal(t, 3, got.Column)
+	assert.Empty(t, got.Errors)
+}
@@ -0,0 +1,79 @@
+package response
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError explains why a single field of the request is invalid.
+type FieldError struct {
+	Field   string         `json:"field" example:"states[2].population"` // path of the field, made of JSON names
+	Code    string         `json:"code" example:"validation_required"`   // stable code of the failed rule
+	Message string         `json:"message" example:"cannot be blank"`    // user-facing message
+	Params  map[string]any `json:"params,omitempty"`                     // parameters of the failed rule, e.g. min and max
+}
+
+// fieldErrors converts the errors of gin's validator in the order the fields are declared.
+// Unlike the other gin trees, which validate requests with ozzo-validation, minimum only uses the binding
+// tags of gin's validator, which is why this file and request/json.go differ from their copies there.
+func fieldErrors(errs validator.ValidationErrors) []FieldError {
+	result := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		result = append(result, FieldError{
+			Field:   fieldName(fe),
+			Code:    "validation_" + fe.Tag(),
+			Message: fieldMessage(fe),
+			Params:  fieldParams(fe),
+		})
+	}
+
+	return result
+}
+
+// fieldName drops the name of the top-level struct from the namespace, e.g. SumPopulationByState.states[0].name.
+func fieldName(fe validator.FieldError) string {
+	_, name, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+
+	return name
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	if fe.Tag() == "required" {
+		return "cannot be blank"
+	}
+	if fe.Param() != "" {
+		return "must satisfy " + fe.Tag() + "=" + fe.Param()
+	}
+
+	return "must satisfy " + fe.Tag()
+}
+
+func fieldParams(fe validator.FieldError) map[string]any {
+	if fe.Param() == "" {
+		return nil
+	}
+
+	return map[string]any{fe.Tag(): fe.Param()}
+}
+
+// fieldPath converts a dotted path like states.1.population into states[1].population.
+func fieldPath(dotted string) string {
+	var b strings.Builder
+	for i, key := range strings.Split(dotted, ".") {
+		switch _, err := strconv.Atoi(key); {
+		case err == nil:
+			b.WriteString("[" + key + "]")
+		case i > 0:
+			b.WriteString("." + key)
+		default:
+			b.WriteString(key)
+		}
+	}
+
+	return b.String()
+}
@@ -77,7 +77,7 @@ func TestHandleCalculateTotalPopulation(t *testing.T) {
 			},
 			want: want{
 				respCode: http.StatusBadRequest,
-				body:     `{"error":"unexpected EOF"}`,
+				body:     `{"error":"malformed JSON at line 1, column 2: unexpected end of JSON input","line":1,"column":2}`,
 			},
 		},
 		{
@@ -89,7 +89,19 @@ func TestHandleCalculateTotalPopulation(t *testing.T) {
 			},
 			want: want{
 				respCode: http.StatusBadRequest,
-				body:     `{"error":"Key: 'SumPopulationByState.States[0].Name' Error:Field validation for 'Name' failed on the 'required' tag"}`,
+				body:     `{"error":"Key: 'SumPopulationByState.states[0].name' Error:Field validation for 'name' failed on the 'required' tag","errors":[{"field":"states[0].name","code":"validation_required","message":"cannot be blank"}]}`,
+			},
+		},
+		{
+			name: "400 Bad Request - Wrong type",
+			args: args{
+				buildReqBody: func() string {
+					return `{"states": [{"name": "Texas", "population": 1}, {"name": "Ohio", "population": "many"}]}`
+				},
+			},
+			want: want{
+				respCode: http.StatusBadRequest,
+				body:     `{"error":"states.1.population: must be a number","errors":[{"field":"states[1].population","code":"validation_type_mismatch","message":"must be a number","params":{"type":"number"}}]}`,
 			},
 		},
 	}
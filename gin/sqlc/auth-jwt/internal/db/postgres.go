@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/tracelog"
+	"go.uber.org/zap"
+
+	"github.com/yizeng/gab/gin/sqlc/auth-jwt/internal/config"
+	"github.com/yizeng/gab/gin/sqlc/auth-jwt/internal/repository/dao"
+)
+
+func OpenPostgres(conf *config.PostgresConfig) (*pgxpool.Pool, error) {
+	dsn := fmt.Sprintf(
+		"host=%v port=%v user=%v password=%v dbname=%v sslmode=disable",
+		conf.Host, conf.Port, conf.User, conf.Password, conf.DB,
+	)
+
+	poolConf, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.ParseConfig -> %w", err)
+	}
+	poolConf.ConnConfig.Tracer = createTracer(conf.LogLevel)
+
+	ctx := context.Background()
+	pool, err := pgxpool.NewWithConfig(ctx, poolConf)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.NewWithConfig -> %w", err)
+	}
+
+	if err = dao.InitTables(ctx, pool); err != nil {
+		return nil, fmt.Errorf("dao.InitTables -> %w", err)
+	}
+
+	return pool, nil
+}
+
+// createTracer logs queries with zap, up to the level set by POSTGRES_LOG_LEVEL.
+func createTracer(logLevel string) *tracelog.TraceLog {
+	var l tracelog.LogLevel
+
+	switch strings.ToLower(logLevel) {
+	case "silent":
+		l = tracelog.LogLevelNone
+	case "error":
+		l = tracelog.LogLevelError
+	case "warn":
+		l = tracelog.LogLevelWarn
+	case "info":
+		l = tracelog.LogLevelInfo
+	default:
+		l = tracelog.LogLevelError
+	}
+
+	logger := tracelog.LoggerFunc(func(_ context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+		fields := make([]zap.Field, 0, len(data))
+		for k, v := range data {
+			fields = append(fields, zap.Any(k, v))
+		}
+
+		switch level {
+		case tracelog.LogLevelError:
+			zap.L().Error(msg, fields...)
+		case tracelog.LogLevelWarn:
+			zap.L().Warn(msg, fields...)
+		case tracelog.LogLevelInfo:
+			zap.L().Info(msg, fields...)
+		default:
+			zap.L().Debug(msg, fields...)
+		}
+	})
+
+	return &tracelog.TraceLog{
+		Logger:   logger,
+		LogLevel: l,
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type User struct {
+	ID uint `json:"id"`
+
+	Email    string `json:"email"`
+	Password string `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
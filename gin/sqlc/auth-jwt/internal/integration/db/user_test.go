@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/yizeng/gab/gin/sqlc/auth-jwt/internal/integration/testdb"
+	"github.com/yizeng/gab/gin/sqlc/auth-jwt/internal/repository/dao"
+)
+
+type UserDBTestSuite struct {
+	suite.Suite
+
+	db *pgxpool.Pool
+
+	userDAO *dao.UserDAO
+}
+
+func (s *UserDBTestSuite) SetupSuite() {
+	s.db = testdb.Open(s.T())
+}
+
+func (s *UserDBTestSuite) SetupTest() {
+	// Run migrations.
+	err := dao.InitTables(context.Background(), s.db)
+	require.NoError(s.T(), err)
+
+	// Seed database.
+	script, err := os.ReadFile("../scripts/seed_db.sql")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(context.Background(), string(script))
+	require.NoError(s.T(), err)
+
+	// Initialize DAO.
+	s.userDAO = dao.NewUserDAO(s.db)
+}
+
+func (s *UserDBTestSuite) TearDownTest() {
+	s.cleanDB()
+}
+
+func (s *UserDBTestSuite) cleanDB() {
+	script, err := os.ReadFile("../scripts/clean_db.sql")
+	require.NoError(s.T(), err)
+
+	_, err = s.db.Exec(context.Background(), string(script))
+	require.NoError(s.T(), err)
+}
+
+func TestUserDB(t *testing.T) {
+	suite.Run(t, new(UserDBTestSuite))
+}
+
+func (s *UserDBTestSuite) TestUserDB_FindByID() {
+	result, err := s.userDAO.FindByID(context.TODO(), 12345)
+	assert.ErrorIs(s.T(), err, dao.ErrUserNotFound)
+
+	result, err = s.userDAO.FindByID(context.TODO(), 123)
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.EqualValues(s.T(), 123, result.ID)
+	assert.Equal(s.T(), "123@test.com", result.Email)
+}
+
+func (s *UserDBTestSuite) TestUserDB_FindByEmail() {
+	result, err := s.userDAO.FindByEmail(context.TODO(), "non-exist@test.com")
+	assert.ErrorIs(s.T(), err, dao.ErrUserNotFound)
+
+	result, err = s.userDAO.FindByEmail(context.TODO(), "123@test.com")
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.EqualValues(s.T(), 123, result.ID)
+	assert.Equal(s.T(), "123@test.com", result.Email)
+}
+
+func (s *UserDBTestSuite) TestUserDB_Insert() {
+	result, err := s.userDAO.Insert(context.TODO(), dao.User{
+		Email:    "123@test.com",
+		Password: "any",
+	})
+	assert.Error(s.T(), dao.ErrUserEmailExists)
+
+	const (
+		id       = 789
+		email    = "789@test.com"
+		password = "Test@789"
+	)
+	result, err = s.userDAO.Insert(context.TODO(), dao.User{
+		ID:       id,
+		Email:    email,
+		Password: password,
+	})
+	assert.NoError(s.T(), err)
+
+	assert.NotNil(s.T(), result)
+	assert.EqualValues(s.T(), id, result.ID)
+	assert.Equal(s.T(), email, result.Email)
+	assert.Equal(s.T(), password, result.Password)
+}
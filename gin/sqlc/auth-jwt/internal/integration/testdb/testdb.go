@@ -0,0 +1,93 @@
+// Package testdb provides PostgreSQL databases to integration tests from whichever backend is available:
+// a running PostgreSQL server or a PostgreSQL container.
+package testdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// EnvBackend forces the backend of Open, by name.
+	EnvBackend = "TEST_DATABASE_BACKEND"
+
+	// EnvDSN is the connection string of the PostgreSQL server of the dsn backend.
+	EnvDSN = "TEST_DATABASE_DSN"
+)
+
+// Backend opens test databases.
+type Backend interface {
+	// Name is how the backend is chosen with EnvBackend.
+	Name() string
+
+	// Open opens a new database along with a func closing and removing it.
+	// It fails when the backend isn't available.
+	Open() (*pgxpool.Pool, func() error, error)
+}
+
+var (
+	DSN    Backend = dsnBackend{}
+	Docker Backend = dockerBackend{}
+)
+
+// Backends returns the backend forced by EnvBackend, or all of them from the most to the least preferred.
+func Backends() ([]Backend, error) {
+	all := []Backend{DSN, Docker}
+
+	name := os.Getenv(EnvBackend)
+	if name == "" {
+		return all, nil
+	}
+
+	for _, backend := range all {
+		if backend.Name() == name {
+			return []Backend{backend}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown %v %q, it must be dsn or docker", EnvBackend, name)
+}
+
+// Open opens a database of the first available of Backends, which is closed after tb.
+// tb is skipped when no backend is available.
+func Open(tb testing.TB) *pgxpool.Pool {
+	tb.Helper()
+
+	backends, err := Backends()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var errs []error
+	for _, backend := range backends {
+		db, closeDB, err := backend.Open()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", backend.Name(), err))
+
+			continue
+		}
+
+		tb.Cleanup(func() {
+			if err := closeDB(); err != nil {
+				tb.Errorf("failed to close the %v test database: %v", backend.Name(), err)
+			}
+		})
+		tb.Logf("using the %v test database", backend.Name())
+
+		return db
+	}
+
+	names := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		names = append(names, backend.Name())
+	}
+	tb.Skipf("no test database is available (tried %v), set %v or start Docker to run this test:\n%v",
+		strings.Join(names, ", "), EnvDSN, errors.Join(errs...))
+
+	return nil
+}
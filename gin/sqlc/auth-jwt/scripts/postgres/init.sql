@@ -0,0 +1 @@
+CREATE DATABASE gin_sqlc_auth_jwt;
@@ -0,0 +1,6 @@
+package middleware
+
+const (
+	PageQueryKey    string = "page"
+	PerPageQueryKey string = "per_page"
+)
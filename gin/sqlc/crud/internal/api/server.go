@@ -0,0 +1,78 @@
+package api
+
+import (
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	"github.com/yizeng/gab/gin/sqlc/crud/docs"
+	v1 "github.com/yizeng/gab/gin/sqlc/crud/internal/api/handler/v1"
+	"github.com/yizeng/gab/gin/sqlc/crud/internal/api/middleware"
+	"github.com/yizeng/gab/gin/sqlc/crud/internal/config"
+	"github.com/yizeng/gab/gin/sqlc/crud/internal/repository"
+	"github.com/yizeng/gab/gin/sqlc/crud/internal/repository/dao"
+	"github.com/yizeng/gab/gin/sqlc/crud/internal/service"
+)
+
+type Server struct {
+	Config *config.AppConfig
+	Router *gin.Engine
+}
+
+func NewServer(conf *config.AppConfig, db *pgxpool.Pool) *Server {
+	gin.SetMode(conf.Gin.Mode)
+	engine := gin.New()
+
+	s := &Server{
+		Config: conf,
+		Router: engine,
+	}
+
+	s.MountMiddlewares()
+
+	articleHandler := s.initArticleHandler(db)
+	s.MountHandlers(articleHandler)
+
+	return s
+}
+
+func (s *Server) MountMiddlewares() {
+	// Logger and Recovery are needed unless we use gin.Default().
+	s.Router.Use(gin.Logger())
+	s.Router.Use(gin.Recovery())
+	s.Router.Use(requestid.New())
+	s.Router.Use(middleware.ConfigCORS(s.Config.API.AllowedCORSDomains))
+}
+
+func (s *Server) MountHandlers(articleHandler *v1.ArticleHandler) {
+	const basePath = "/api/v1"
+
+	apiV1 := s.Router.Group(basePath)
+	{
+		apiV1.GET("/articles", middleware.Paginate(), articleHandler.HandleListArticles)
+		apiV1.POST("/articles", articleHandler.HandleCreateArticle)
+		apiV1.GET("/articles/:articleID", articleHandler.HandleGetArticle)
+		apiV1.GET("/articles/search", articleHandler.HandleSearchArticles)
+	}
+
+	s.Router.GET("/", v1.HandleHealthcheck)
+
+	// Setup Swagger UI.
+	docs.SwaggerInfo.Host = s.Config.API.BaseURL
+	docs.SwaggerInfo.BasePath = basePath
+	docs.SwaggerInfo.Title = "API for gin/crud-sqlc"
+	docs.SwaggerInfo.Description = "This is an example of Go API with Gin."
+	docs.SwaggerInfo.Version = "1.0"
+	s.Router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+}
+
+func (s *Server) initArticleHandler(db *pgxpool.Pool) *v1.ArticleHandler {
+	articleDAO := dao.NewArticleDAO(db)
+	articleRepo := repository.NewArticleRepository(articleDAO)
+	articleSvc := service.NewArticleService(articleRepo)
+	articleHandler := v1.NewArticleHandler(articleSvc)
+
+	return articleHandler
+}
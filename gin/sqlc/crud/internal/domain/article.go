@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+type Article struct {
+	ID     uint `json:"id"`
+	UserID uint `json:"user_id"`
+
+	Title   string `json:"title"`
+	Content string `json:"content"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
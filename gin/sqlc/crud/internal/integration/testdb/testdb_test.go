@@ -0,0 +1,31 @@
+package testdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackends(t *testing.T) {
+	t.Setenv(EnvBackend, "")
+	backends, err := Backends()
+	require.NoError(t, err)
+	assert.Equal(t, []Backend{DSN, Docker}, backends)
+
+	t.Setenv(EnvBackend, "docker")
+	backends, err = Backends()
+	require.NoError(t, err)
+	assert.Equal(t, []Backend{Docker}, backends)
+
+	t.Setenv(EnvBackend, "sqlite")
+	_, err = Backends()
+	assert.Error(t, err)
+}
+
+func TestDSN_Unset(t *testing.T) {
+	t.Setenv(EnvDSN, "")
+
+	_, _, err := DSN.Open()
+	assert.EqualError(t, err, "TEST_DATABASE_DSN isn't set")
+}
@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrArticleDuplicated = errors.New("article already exists")
+	ErrArticleNotFound   = errors.New("article not found")
+)
+
+// ArticleDAO wraps the queries generated by sqlc from queries/article.sql,
+// translating Postgres errors into the errors of this package.
+type ArticleDAO struct {
+	q *Queries
+}
+
+func NewArticleDAO(db DBTX) *ArticleDAO {
+	return &ArticleDAO{
+		q: New(db),
+	}
+}
+
+func (d *ArticleDAO) Insert(ctx context.Context, article Article) (Article, error) {
+	created, err := d.q.InsertArticle(ctx, InsertArticleParams{
+		UserID:  article.UserID,
+		Title:   article.Title,
+		Content: article.Content,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return Article{}, ErrArticleDuplicated
+		}
+
+		return Article{}, err
+	}
+
+	return created, nil
+}
+
+func (d *ArticleDAO) FindByID(ctx context.Context, id uint) (Article, error) {
+	article, err := d.q.FindArticleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Article{}, ErrArticleNotFound
+		}
+
+		return Article{}, err
+	}
+
+	return article, nil
+}
+
+func (d *ArticleDAO) FindAll(ctx context.Context, page, perPage uint) ([]Article, error) {
+	// page number is starting from 1.
+	offset := (page - 1) * perPage
+	articles, err := d.q.ListArticles(ctx, ListArticlesParams{
+		Limit:  int32(perPage),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+func (d *ArticleDAO) Search(ctx context.Context, title, content string) ([]Article, error) {
+	// An empty pattern matches everything, so either filter can be left out.
+	articles, err := d.q.SearchArticles(ctx, SearchArticlesParams{
+		Title:   "%" + title + "%",
+		Content: "%" + content + "%",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
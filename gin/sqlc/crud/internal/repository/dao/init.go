@@ -0,0 +1,17 @@
+package dao
+
+import (
+	"context"
+	_ "embed"
+)
+
+// schema is what sqlc generates the code from, it's idempotent so it can be applied at every start.
+//
+//go:embed schema.sql
+var schema string
+
+func InitTables(ctx context.Context, db DBTX) error {
+	_, err := db.Exec(ctx, schema)
+
+	return err
+}
@@ -0,0 +1,16 @@
+-- name: InsertArticle :one
+INSERT INTO articles (user_id, title, content)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: FindArticleByID :one
+SELECT * FROM articles
+WHERE id = $1;
+
+-- name: ListArticles :many
+SELECT * FROM articles
+LIMIT $1 OFFSET $2;
+
+-- name: SearchArticles :many
+SELECT * FROM articles
+WHERE title LIKE @title AND content LIKE @content;
@@ -0,0 +1 @@
+CREATE DATABASE gin_sqlc_crud;